@@ -0,0 +1,35 @@
+package bolt
+
+import "ship_line/swagger"
+
+var containerTypesBucket = []byte("containerTypes")
+
+// containerTypes returns the record store of the container types.
+func (b *BoltStorage) containerTypes() recordStore[swagger.ContainerType] {
+	return recordStore[swagger.ContainerType]{db: b.db, bucket: containerTypesBucket, id: func(containerType *swagger.ContainerType) *string { return &containerType.Id }}
+}
+
+// ListContainerTypes returns every stored container type ordered by ID.
+func (b *BoltStorage) ListContainerTypes() ([]swagger.ContainerType, error) {
+	return b.containerTypes().list()
+}
+
+// GetContainerType retrieves a container type by ID. It returns nil if it does not exist.
+func (b *BoltStorage) GetContainerType(id string) (*swagger.ContainerType, error) {
+	return b.containerTypes().get(id)
+}
+
+// CreateContainerType assigns the next container type ID to containerType and stores it.
+func (b *BoltStorage) CreateContainerType(containerType *swagger.ContainerType) error {
+	return b.containerTypes().create(containerType)
+}
+
+// UpdateContainerType overwrites a stored container type.
+func (b *BoltStorage) UpdateContainerType(containerType *swagger.ContainerType) error {
+	return b.containerTypes().update(containerType)
+}
+
+// DeleteContainerType removes a container type by ID if it exists.
+func (b *BoltStorage) DeleteContainerType(id string) error {
+	return b.containerTypes().delete(id)
+}
@@ -0,0 +1,35 @@
+package bolt
+
+import "ship_line/swagger"
+
+var customersBucket = []byte("customers")
+
+// customers returns the record store of the customers.
+func (b *BoltStorage) customers() recordStore[swagger.Customer] {
+	return recordStore[swagger.Customer]{db: b.db, bucket: customersBucket, id: func(customer *swagger.Customer) *string { return &customer.Id }}
+}
+
+// ListCustomers returns every stored customer ordered by ID.
+func (b *BoltStorage) ListCustomers() ([]swagger.Customer, error) {
+	return b.customers().list()
+}
+
+// GetCustomer retrieves a customer by ID. It returns nil if it does not exist.
+func (b *BoltStorage) GetCustomer(id string) (*swagger.Customer, error) {
+	return b.customers().get(id)
+}
+
+// CreateCustomer assigns the next customer ID to customer and stores it.
+func (b *BoltStorage) CreateCustomer(customer *swagger.Customer) error {
+	return b.customers().create(customer)
+}
+
+// UpdateCustomer overwrites a stored customer.
+func (b *BoltStorage) UpdateCustomer(customer *swagger.Customer) error {
+	return b.customers().update(customer)
+}
+
+// DeleteCustomer removes a customer by ID if it exists.
+func (b *BoltStorage) DeleteCustomer(id string) error {
+	return b.customers().delete(id)
+}
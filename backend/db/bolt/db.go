@@ -1,32 +1,45 @@
 // Package bolt provides a BoltDB-based implementation of the PackRepository interface.
-// It allows for storing, retrieving, updating, and deleting pack sizes.
+// It allows for storing, retrieving, updating, and deleting pack sizes, and
+// persists the other records the services keep, such as packs and orders.
 package bolt
 
 import (
 	"encoding/json"
+	"sort"
 	"time"
 
+	"ship_line/swagger"
+
 	bolt "go.etcd.io/bbolt"
 )
 
+// bucketName holds the legacy pack-size list. Pack sizes now live in the packs
+// bucket; the list is migrated the first time an older database is opened.
 var bucketName = []byte("packSizes")
 
+// buckets lists every bucket created when the database is opened.
+var buckets = [][]byte{bucketName, ordersBucket, packsBucket, quotesBucket, rateCardsBucket, containerTypesBucket, hierarchiesBucket, looseStockBucket, warehousesBucket, kitsBucket, quantityRulesBucket, customersBucket, ruleSetsBucket}
+
 // BoltStorage implements the PackRepository interface.
 type BoltStorage struct {
 	db *bolt.DB
 }
 
 // NewBoltStorage opens or creates a BoltDB database at the specified path and
-// ensures the required bucket exists.
+// ensures the required buckets exist.
 func NewBoltStorage(path string) (*BoltStorage, error) {
 	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
 	if err != nil {
 		return nil, err
 	}
-	// Ensure bucket exists.
+	// Ensure buckets exist.
 	err = db.Update(func(tx *bolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists(bucketName)
-		return err
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return migrateLegacyPackSizes(tx)
 	})
 	if err != nil {
 		return nil, err
@@ -34,96 +47,109 @@ func NewBoltStorage(path string) (*BoltStorage, error) {
 	return &BoltStorage{db: db}, nil
 }
 
-// GetPackSizes retrieves the stored pack sizes from the BoltDB.
+// migrateLegacyPackSizes turns the pack sizes stored as a bare list into
+// individual pack records and removes the list.
+func migrateLegacyPackSizes(tx *bolt.Tx) error {
+	legacy := tx.Bucket(bucketName)
+	data := legacy.Get([]byte("packSizes"))
+	if data == nil {
+		return nil
+	}
+	var sizes []int
+	if err := json.Unmarshal(data, &sizes); err != nil {
+		return err
+	}
+	if err := mergePackSizes(tx.Bucket(packsBucket), sizes); err != nil {
+		return err
+	}
+	return legacy.Delete([]byte("packSizes"))
+}
+
+// GetPackSizes retrieves the sizes of the packs in effect now from the BoltDB.
 // If no pack sizes are stored, it returns an empty slice.
 func (b *BoltStorage) GetPackSizes() ([]int, error) {
 	var sizes []int
 	err := b.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(bucketName)
-		data := bucket.Get([]byte("packSizes"))
-		if data == nil {
-			return nil // not stored yet
+		packs, err := readPacks(tx.Bucket(packsBucket))
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		seen := make(map[int]bool)
+		for _, pack := range packs {
+			if pack.InEffect(now) && !seen[pack.Size] {
+				seen[pack.Size] = true
+				sizes = append(sizes, pack.Size)
+			}
 		}
-		return json.Unmarshal(data, &sizes)
+		return nil
 	})
+	sort.Ints(sizes)
 	return sizes, err
 }
 
 // SetPackSizes stores the given pack sizes in BoltDB.
-// It merges with existing sizes and removes duplicates.
+// It merges with existing sizes and removes duplicates: a size without a pack
+// in effect reactivates a deactivated pack of that size, or gets a new pack.
 func (b *BoltStorage) SetPackSizes(sizes []int) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(bucketName)
+		bucket := tx.Bucket(packsBucket)
 		if bucket == nil {
 			return bolt.ErrBucketNotFound
 		}
+		return mergePackSizes(bucket, sizes)
+	})
+}
 
-		// Retrieve existing pack sizes
-		var existingSizes []int
-		data := bucket.Get([]byte("packSizes"))
-		if data != nil {
-			if err := json.Unmarshal(data, &existingSizes); err != nil {
+// mergePackSizes makes sure a pack is in effect now for every size.
+func mergePackSizes(bucket *bolt.Bucket, sizes []int) error {
+	packs, err := readPacks(bucket)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, size := range sizes {
+		if packInEffect(packs, size, now) >= 0 {
+			continue
+		}
+		if i := deactivatedPack(packs, size, now); i >= 0 {
+			packs[i].Active = true
+			if err := putJSON(bucket, packs[i].Id, &packs[i]); err != nil {
 				return err
 			}
+			continue
 		}
-
-		// Merge and remove duplicates
-		sizeSet := make(map[int]struct{})
-		for _, s := range existingSizes {
-			sizeSet[s] = struct{}{}
-		}
-		for _, s := range sizes {
-			sizeSet[s] = struct{}{}
-		}
-
-		// Convert map keys back to a slice
-		updatedSizes := make([]int, 0, len(sizeSet))
-		for s := range sizeSet {
-			updatedSizes = append(updatedSizes, s)
-		}
-
-		// Store the updated pack sizes
-		newData, err := json.Marshal(updatedSizes)
-		if err != nil {
+		pack := swagger.Pack{Size: size, Active: true}
+		if err := insertPack(bucket, &pack); err != nil {
 			return err
 		}
-
-		return bucket.Put([]byte("packSizes"), newData)
-	})
+		packs = append(packs, pack)
+	}
+	return nil
 }
 
-// DeletePackSize removes a specific pack size from storage if it exists.
+// DeletePackSize deactivates the packs of a specific size in effect now, if
+// any. Scheduled and already deactivated packs of the size are kept.
 func (b *BoltStorage) DeletePackSize(size int) error {
 	return b.db.Update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(bucketName)
+		bucket := tx.Bucket(packsBucket)
 		if bucket == nil {
 			return bolt.ErrBucketNotFound
 		}
-
-		// Retrieve existing pack sizes
-		var existingSizes []int
-		data := bucket.Get([]byte("packSizes"))
-		if data != nil {
-			if err := json.Unmarshal(data, &existingSizes); err != nil {
-				return err
-			}
-		}
-
-		// Filter out the size to be deleted
-		updatedSizes := make([]int, 0, len(existingSizes))
-		for _, s := range existingSizes {
-			if s != size {
-				updatedSizes = append(updatedSizes, s)
-			}
-		}
-
-		// Store the updated pack sizes
-		newData, err := json.Marshal(updatedSizes)
+		packs, err := readPacks(bucket)
 		if err != nil {
 			return err
 		}
-
-		return bucket.Put([]byte("packSizes"), newData)
+		now := time.Now()
+		for _, pack := range packs {
+			if pack.Size == size && pack.InEffect(now) {
+				pack.Active = false
+				if err := putJSON(bucket, pack.Id, &pack); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
 	})
 }
 
@@ -0,0 +1,35 @@
+package bolt
+
+import "ship_line/swagger"
+
+var hierarchiesBucket = []byte("hierarchies")
+
+// hierarchies returns the record store of the hierarchies.
+func (b *BoltStorage) hierarchies() recordStore[swagger.Hierarchy] {
+	return recordStore[swagger.Hierarchy]{db: b.db, bucket: hierarchiesBucket, id: func(hierarchy *swagger.Hierarchy) *string { return &hierarchy.Id }}
+}
+
+// ListHierarchies returns every stored hierarchy ordered by ID.
+func (b *BoltStorage) ListHierarchies() ([]swagger.Hierarchy, error) {
+	return b.hierarchies().list()
+}
+
+// GetHierarchy retrieves a hierarchy by ID. It returns nil if it does not exist.
+func (b *BoltStorage) GetHierarchy(id string) (*swagger.Hierarchy, error) {
+	return b.hierarchies().get(id)
+}
+
+// CreateHierarchy assigns the next hierarchy ID to hierarchy and stores it.
+func (b *BoltStorage) CreateHierarchy(hierarchy *swagger.Hierarchy) error {
+	return b.hierarchies().create(hierarchy)
+}
+
+// UpdateHierarchy overwrites a stored hierarchy.
+func (b *BoltStorage) UpdateHierarchy(hierarchy *swagger.Hierarchy) error {
+	return b.hierarchies().update(hierarchy)
+}
+
+// DeleteHierarchy removes a hierarchy by ID if it exists.
+func (b *BoltStorage) DeleteHierarchy(id string) error {
+	return b.hierarchies().delete(id)
+}
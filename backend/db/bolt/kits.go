@@ -0,0 +1,35 @@
+package bolt
+
+import "ship_line/swagger"
+
+var kitsBucket = []byte("kits")
+
+// kits returns the record store of the kits.
+func (b *BoltStorage) kits() recordStore[swagger.Kit] {
+	return recordStore[swagger.Kit]{db: b.db, bucket: kitsBucket, id: func(kit *swagger.Kit) *string { return &kit.Id }}
+}
+
+// ListKits returns every stored kit ordered by ID.
+func (b *BoltStorage) ListKits() ([]swagger.Kit, error) {
+	return b.kits().list()
+}
+
+// GetKit retrieves a kit by ID. It returns nil if it does not exist.
+func (b *BoltStorage) GetKit(id string) (*swagger.Kit, error) {
+	return b.kits().get(id)
+}
+
+// CreateKit assigns the next kit ID to kit and stores it.
+func (b *BoltStorage) CreateKit(kit *swagger.Kit) error {
+	return b.kits().create(kit)
+}
+
+// UpdateKit overwrites a stored kit.
+func (b *BoltStorage) UpdateKit(kit *swagger.Kit) error {
+	return b.kits().update(kit)
+}
+
+// DeleteKit removes a kit by ID if it exists.
+func (b *BoltStorage) DeleteKit(id string) error {
+	return b.kits().delete(id)
+}
@@ -0,0 +1,81 @@
+package bolt
+
+import (
+	"encoding/json"
+
+	"ship_line/swagger"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	looseStockBucket = []byte("looseStock")
+	looseStockKey    = []byte("pool")
+)
+
+// GetLooseStock returns the number of items in the loose-item pool.
+func (b *BoltStorage) GetLooseStock() (int, error) {
+	var items int
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		items, err = readLooseStock(tx.Bucket(looseStockBucket))
+		return err
+	})
+	return items, err
+}
+
+// SetLooseStock replaces the number of items in the loose-item pool.
+func (b *BoltStorage) SetLooseStock(items int) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(looseStockBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return putJSON(bucket, string(looseStockKey), swagger.LooseStock{Items: items})
+	})
+}
+
+// TakeLooseStock removes up to limit items from the loose-item pool and
+// returns how many were taken.
+func (b *BoltStorage) TakeLooseStock(limit int) (int, error) {
+	taken := 0
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(looseStockBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		items, err := readLooseStock(bucket)
+		if err != nil {
+			return err
+		}
+		taken = min(items, max(limit, 0))
+		return putJSON(bucket, string(looseStockKey), swagger.LooseStock{Items: items - taken})
+	})
+	return taken, err
+}
+
+// AddLooseStock adds items to the loose-item pool.
+func (b *BoltStorage) AddLooseStock(items int) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(looseStockBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		current, err := readLooseStock(bucket)
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, string(looseStockKey), swagger.LooseStock{Items: current + items})
+	})
+}
+
+// readLooseStock returns the pool size stored in bucket, 0 if none is stored.
+func readLooseStock(bucket *bolt.Bucket) (int, error) {
+	data := bucket.Get(looseStockKey)
+	if data == nil {
+		return 0, nil
+	}
+	var stock swagger.LooseStock
+	err := json.Unmarshal(data, &stock)
+	return stock.Items, err
+}
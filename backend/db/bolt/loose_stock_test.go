@@ -0,0 +1,32 @@
+package bolt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltStorage_LooseStock(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "loose.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	items, err := storage.GetLooseStock()
+	require.NoError(t, err)
+	assert.Zero(t, items)
+
+	require.NoError(t, storage.AddLooseStock(249))
+	taken, err := storage.TakeLooseStock(100)
+	require.NoError(t, err)
+	assert.Equal(t, 100, taken)
+	taken, err = storage.TakeLooseStock(500)
+	require.NoError(t, err)
+	assert.Equal(t, 149, taken)
+
+	require.NoError(t, storage.SetLooseStock(30))
+	items, err = storage.GetLooseStock()
+	require.NoError(t, err)
+	assert.Equal(t, 30, items)
+}
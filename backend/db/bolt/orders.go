@@ -0,0 +1,85 @@
+package bolt
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"ship_line/swagger"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var ordersBucket = []byte("orders")
+
+// CreateOrder assigns the next order ID to order and stores it.
+func (b *BoltStorage) CreateOrder(order *swagger.Order) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ordersBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		order.Id = strconv.FormatUint(seq, 10)
+		return putJSON(bucket, order.Id, order)
+	})
+}
+
+// UpdateOrder overwrites a stored order.
+func (b *BoltStorage) UpdateOrder(order *swagger.Order) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ordersBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return putJSON(bucket, order.Id, order)
+	})
+}
+
+// GetOrder retrieves an order by ID. It returns nil if the order does not exist.
+func (b *BoltStorage) GetOrder(id string) (*swagger.Order, error) {
+	var order *swagger.Order
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(ordersBucket).Get([]byte(id))
+		if data == nil {
+			return nil // not stored
+		}
+		order = &swagger.Order{}
+		return json.Unmarshal(data, order)
+	})
+	return order, err
+}
+
+// ListOrders returns every stored order, oldest first.
+func (b *BoltStorage) ListOrders() ([]swagger.Order, error) {
+	var orders []swagger.Order
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(ordersBucket).ForEach(func(_, data []byte) error {
+			var order swagger.Order
+			if err := json.Unmarshal(data, &order); err != nil {
+				return err
+			}
+			orders = append(orders, order)
+			return nil
+		})
+	})
+	// Keys are decimal IDs, so sort numerically rather than by key bytes.
+	sort.Slice(orders, func(i, j int) bool {
+		a, _ := strconv.Atoi(orders[i].Id)
+		b, _ := strconv.Atoi(orders[j].Id)
+		return a < b
+	})
+	return orders, err
+}
+
+// putJSON stores value as JSON under key.
+func putJSON(bucket *bolt.Bucket, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), data)
+}
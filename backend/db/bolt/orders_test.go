@@ -0,0 +1,43 @@
+package bolt
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestBoltStorage_Orders(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "orders.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	// Unknown orders are reported as nil.
+	order, err := storage.GetOrder("1")
+	require.NoError(t, err)
+	assert.Nil(t, order)
+
+	for _, items := range []int{501, 12001, 250} {
+		err := storage.CreateOrder(&swagger.Order{ItemsOrdered: items, CreatedAt: time.Now()})
+		require.NoError(t, err)
+	}
+
+	order, err = storage.GetOrder("2")
+	require.NoError(t, err)
+	require.NotNil(t, order)
+	assert.Equal(t, 12001, order.ItemsOrdered)
+
+	order.ItemsOrdered = 13000
+	require.NoError(t, storage.UpdateOrder(order))
+	order, err = storage.GetOrder("2")
+	require.NoError(t, err)
+	assert.Equal(t, 13000, order.ItemsOrdered)
+
+	orders, err := storage.ListOrders()
+	require.NoError(t, err)
+	require.Len(t, orders, 3)
+	assert.Equal(t, []string{"1", "2", "3"}, []string{orders[0].Id, orders[1].Id, orders[2].Id})
+}
@@ -0,0 +1,122 @@
+package bolt
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"ship_line/swagger"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var packsBucket = []byte("packs")
+
+// ListPacks returns every stored pack ordered by ID.
+func (b *BoltStorage) ListPacks() ([]swagger.Pack, error) {
+	var packs []swagger.Pack
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		packs, err = readPacks(tx.Bucket(packsBucket))
+		return err
+	})
+	return packs, err
+}
+
+// GetPack retrieves a pack by ID. It returns nil if the pack does not exist.
+func (b *BoltStorage) GetPack(id string) (*swagger.Pack, error) {
+	var pack *swagger.Pack
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(packsBucket).Get([]byte(id))
+		if data == nil {
+			return nil // not stored
+		}
+		pack = &swagger.Pack{}
+		return json.Unmarshal(data, pack)
+	})
+	return pack, err
+}
+
+// CreatePack assigns the next pack ID to pack and stores it.
+func (b *BoltStorage) CreatePack(pack *swagger.Pack) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(packsBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return insertPack(bucket, pack)
+	})
+}
+
+// UpdatePack overwrites a stored pack.
+func (b *BoltStorage) UpdatePack(pack *swagger.Pack) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(packsBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return putJSON(bucket, pack.Id, pack)
+	})
+}
+
+// DeletePack removes a pack by ID if it exists.
+func (b *BoltStorage) DeletePack(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(packsBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// insertPack assigns the next pack ID to pack and stores it.
+func insertPack(bucket *bolt.Bucket, pack *swagger.Pack) error {
+	seq, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	pack.Id = strconv.FormatUint(seq, 10)
+	return putJSON(bucket, pack.Id, pack)
+}
+
+// readPacks decodes every pack in bucket, ordered numerically by ID.
+func readPacks(bucket *bolt.Bucket) ([]swagger.Pack, error) {
+	var packs []swagger.Pack
+	err := bucket.ForEach(func(_, data []byte) error {
+		var pack swagger.Pack
+		if err := json.Unmarshal(data, &pack); err != nil {
+			return err
+		}
+		packs = append(packs, pack)
+		return nil
+	})
+	sort.Slice(packs, func(i, j int) bool {
+		a, _ := strconv.Atoi(packs[i].Id)
+		b, _ := strconv.Atoi(packs[j].Id)
+		return a < b
+	})
+	return packs, err
+}
+
+// packInEffect returns the index of the pack of size in effect at at, or -1.
+func packInEffect(packs []swagger.Pack, size int, at time.Time) int {
+	for i, pack := range packs {
+		if pack.Size == size && pack.InEffect(at) {
+			return i
+		}
+	}
+	return -1
+}
+
+// deactivatedPack returns the index of a deactivated pack of size whose
+// effective window contains at, or -1.
+func deactivatedPack(packs []swagger.Pack, size int, at time.Time) int {
+	for i, pack := range packs {
+		if pack.Size == size && !pack.Active && pack.WithinWindow(at) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,120 @@
+package bolt
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBoltStorage_Packs(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "packs.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	pack, err := storage.GetPack("1")
+	require.NoError(t, err)
+	assert.Nil(t, pack)
+
+	sku := "BOX-250"
+	require.NoError(t, storage.CreatePack(&swagger.Pack{Size: 250, Active: true, Sku: &sku}))
+	require.NoError(t, storage.CreatePack(&swagger.Pack{Size: 500, Active: false}))
+
+	pack, err = storage.GetPack("1")
+	require.NoError(t, err)
+	require.NotNil(t, pack)
+	assert.Equal(t, "BOX-250", *pack.Sku)
+
+	// Only active packs show up as pack sizes.
+	sizes, err := storage.GetPackSizes()
+	require.NoError(t, err)
+	assert.Equal(t, []int{250}, sizes)
+
+	// Setting pack sizes reactivates the 500 pack and creates a 1000 pack.
+	require.NoError(t, storage.SetPackSizes([]int{500, 1000}))
+	packs, err := storage.ListPacks()
+	require.NoError(t, err)
+	require.Len(t, packs, 3)
+	assert.True(t, packs[1].Active)
+	assert.Equal(t, 1000, packs[2].Size)
+
+	pack.Active = false
+	require.NoError(t, storage.UpdatePack(pack))
+	require.NoError(t, storage.DeletePack("3"))
+	sizes, err = storage.GetPackSizes()
+	require.NoError(t, err)
+	assert.Equal(t, []int{500}, sizes)
+}
+
+func TestNewBoltStorage_MigratesLegacyPackSizes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := bolt.Open(path, 0600, nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(bucketName)
+		if err != nil {
+			return err
+		}
+		data, _ := json.Marshal([]int{1000, 250, 500})
+		return bucket.Put([]byte("packSizes"), data)
+	}))
+	require.NoError(t, db.Close())
+
+	storage, err := NewBoltStorage(path)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	sizes, err := storage.GetPackSizes()
+	require.NoError(t, err)
+	assert.Equal(t, []int{250, 500, 1000}, sizes)
+	packs, err := storage.ListPacks()
+	require.NoError(t, err)
+	assert.Len(t, packs, 3)
+}
+
+func TestBoltStorage_PackSchedule(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "schedule.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, storage.CreatePack(&swagger.Pack{Size: 250, Active: true, EffectiveUntil: &past}))
+	require.NoError(t, storage.CreatePack(&swagger.Pack{Size: 500, Active: true, EffectiveFrom: &future}))
+	require.NoError(t, storage.CreatePack(&swagger.Pack{Size: 1000, Active: false}))
+
+	sizes, err := storage.GetPackSizes()
+	require.NoError(t, err)
+	assert.Empty(t, sizes)
+
+	// Expired and scheduled packs are left alone; the deactivated pack is reused.
+	require.NoError(t, storage.SetPackSizes([]int{250, 1000}))
+	packs, err := storage.ListPacks()
+	require.NoError(t, err)
+	require.Len(t, packs, 4)
+	assert.True(t, packs[2].Active)
+	assert.Equal(t, 250, packs[3].Size)
+
+	sizes, err = storage.GetPackSizes()
+	require.NoError(t, err)
+	assert.Equal(t, []int{250, 1000}, sizes)
+
+	// Deleting a size deactivates the pack in effect and keeps the others.
+	require.NoError(t, storage.CreatePack(&swagger.Pack{Size: 250, Active: true, EffectiveFrom: &future}))
+	require.NoError(t, storage.DeletePackSize(250))
+	packs, err = storage.ListPacks()
+	require.NoError(t, err)
+	require.Len(t, packs, 5)
+	assert.True(t, packs[0].Active, "the expired pack is untouched")
+	assert.False(t, packs[3].Active)
+	assert.True(t, packs[4].Active)
+	sizes, err = storage.GetPackSizes()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1000}, sizes)
+}
@@ -0,0 +1,65 @@
+package bolt
+
+import (
+	"encoding/json"
+
+	"ship_line/swagger"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// quantityRulesBucket holds the order quantity rules of products, keyed by SKU.
+var quantityRulesBucket = []byte("quantityRules")
+
+// ListQuantityRules returns every stored quantity rule ordered by SKU.
+func (b *BoltStorage) ListQuantityRules() ([]swagger.QuantityRule, error) {
+	var rules []swagger.QuantityRule
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(quantityRulesBucket).ForEach(func(_, data []byte) error {
+			var rule swagger.QuantityRule
+			if err := json.Unmarshal(data, &rule); err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+			return nil
+		})
+	})
+	return rules, err
+}
+
+// GetQuantityRule retrieves the quantity rule of a SKU. It returns nil if it
+// does not exist.
+func (b *BoltStorage) GetQuantityRule(sku string) (*swagger.QuantityRule, error) {
+	var rule *swagger.QuantityRule
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(quantityRulesBucket).Get([]byte(sku))
+		if data == nil {
+			return nil // not stored
+		}
+		rule = &swagger.QuantityRule{}
+		return json.Unmarshal(data, rule)
+	})
+	return rule, err
+}
+
+// SetQuantityRule stores rule under its SKU, replacing any previous rule.
+func (b *BoltStorage) SetQuantityRule(rule *swagger.QuantityRule) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(quantityRulesBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return putJSON(bucket, rule.Sku, rule)
+	})
+}
+
+// DeleteQuantityRule removes the quantity rule of a SKU if it exists.
+func (b *BoltStorage) DeleteQuantityRule(sku string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(quantityRulesBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return bucket.Delete([]byte(sku))
+	})
+}
@@ -0,0 +1,37 @@
+package bolt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+func TestBoltStorage_QuantityRules(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "rules.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	rule, err := storage.GetQuantityRule("A")
+	require.NoError(t, err)
+	assert.Nil(t, rule)
+
+	require.NoError(t, storage.SetQuantityRule(&swagger.QuantityRule{Sku: "B", MinOrder: utils.Ptr(10)}))
+	require.NoError(t, storage.SetQuantityRule(&swagger.QuantityRule{Sku: "A", Multiple: utils.Ptr(5)}))
+	require.NoError(t, storage.SetQuantityRule(&swagger.QuantityRule{Sku: "B", MaxOrder: utils.Ptr(100)}))
+
+	rule, err = storage.GetQuantityRule("B")
+	require.NoError(t, err)
+	require.NotNil(t, rule)
+	assert.Nil(t, rule.MinOrder)
+	assert.Equal(t, 100, *rule.MaxOrder)
+
+	require.NoError(t, storage.DeleteQuantityRule("B"))
+	rules, err := storage.ListQuantityRules()
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "A", rules[0].Sku)
+}
@@ -0,0 +1,53 @@
+package bolt
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"ship_line/swagger"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var quotesBucket = []byte("quotes")
+
+// CreateQuote assigns the next quote ID to quote and stores it.
+func (b *BoltStorage) CreateQuote(quote *swagger.Quote) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(quotesBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		quote.Id = strconv.FormatUint(seq, 10)
+		return putJSON(bucket, quote.Id, quote)
+	})
+}
+
+// GetQuote retrieves a quote by ID. It returns nil if the quote does not exist.
+func (b *BoltStorage) GetQuote(id string) (*swagger.Quote, error) {
+	var quote *swagger.Quote
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(quotesBucket).Get([]byte(id))
+		if data == nil {
+			return nil // not stored
+		}
+		quote = &swagger.Quote{}
+		return json.Unmarshal(data, quote)
+	})
+	return quote, err
+}
+
+// UpdateQuote overwrites a stored quote.
+func (b *BoltStorage) UpdateQuote(quote *swagger.Quote) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(quotesBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return putJSON(bucket, quote.Id, quote)
+	})
+}
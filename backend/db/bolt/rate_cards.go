@@ -0,0 +1,35 @@
+package bolt
+
+import "ship_line/swagger"
+
+var rateCardsBucket = []byte("rateCards")
+
+// rateCards returns the record store of the carrier rate cards.
+func (b *BoltStorage) rateCards() recordStore[swagger.RateCard] {
+	return recordStore[swagger.RateCard]{db: b.db, bucket: rateCardsBucket, id: func(card *swagger.RateCard) *string { return &card.Id }}
+}
+
+// ListRateCards returns every stored carrier rate card ordered by ID.
+func (b *BoltStorage) ListRateCards() ([]swagger.RateCard, error) {
+	return b.rateCards().list()
+}
+
+// GetRateCard retrieves a rate card by ID. It returns nil if it does not exist.
+func (b *BoltStorage) GetRateCard(id string) (*swagger.RateCard, error) {
+	return b.rateCards().get(id)
+}
+
+// CreateRateCard assigns the next rate card ID to card and stores it.
+func (b *BoltStorage) CreateRateCard(card *swagger.RateCard) error {
+	return b.rateCards().create(card)
+}
+
+// UpdateRateCard overwrites a stored rate card.
+func (b *BoltStorage) UpdateRateCard(card *swagger.RateCard) error {
+	return b.rateCards().update(card)
+}
+
+// DeleteRateCard removes a rate card by ID if it exists.
+func (b *BoltStorage) DeleteRateCard(id string) error {
+	return b.rateCards().delete(id)
+}
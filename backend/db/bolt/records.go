@@ -0,0 +1,91 @@
+package bolt
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// recordStore stores JSON records of type T in one bucket, keyed by IDs
+// taken from the sequence of the bucket. id returns the ID field of a record.
+type recordStore[T any] struct {
+	db     *bolt.DB
+	bucket []byte
+	id     func(record *T) *string
+}
+
+// list returns every stored record ordered by ID.
+func (s recordStore[T]) list() ([]T, error) {
+	var records []T
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).ForEach(func(_, data []byte) error {
+			var record T
+			if err := json.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	sort.Slice(records, func(i, j int) bool {
+		a, _ := strconv.Atoi(*s.id(&records[i]))
+		b, _ := strconv.Atoi(*s.id(&records[j]))
+		return a < b
+	})
+	return records, err
+}
+
+// get retrieves a record by ID. It returns nil if it does not exist.
+func (s recordStore[T]) get(id string) (*T, error) {
+	var record *T
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(s.bucket).Get([]byte(id))
+		if data == nil {
+			return nil // not stored
+		}
+		record = new(T)
+		return json.Unmarshal(data, record)
+	})
+	return record, err
+}
+
+// create assigns the next ID to record and stores it.
+func (s recordStore[T]) create(record *T) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		id := s.id(record)
+		*id = strconv.FormatUint(seq, 10)
+		return putJSON(bucket, *id, record)
+	})
+}
+
+// update overwrites a stored record.
+func (s recordStore[T]) update(record *T) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return putJSON(bucket, *s.id(record), record)
+	})
+}
+
+// delete removes a record by ID if it exists.
+func (s recordStore[T]) delete(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
@@ -0,0 +1,83 @@
+package bolt
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestRecordStore(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "records.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+	kits := storage.kits()
+
+	kit, err := kits.get("1")
+	require.NoError(t, err)
+	assert.Nil(t, kit)
+
+	for i := 1; i <= 10; i++ {
+		require.NoError(t, kits.create(&swagger.Kit{Name: "Kit " + strconv.Itoa(i), Contents: map[string]int{"A": i}}))
+	}
+	kit, err = kits.get("2")
+	require.NoError(t, err)
+	require.NotNil(t, kit)
+	kit.Contents["A"] = 25
+	require.NoError(t, kits.update(kit))
+	require.NoError(t, kits.delete("1"))
+
+	list, err := kits.list()
+	require.NoError(t, err)
+	require.Len(t, list, 9)
+	// IDs are ordered numerically, not as strings.
+	assert.Equal(t, "2", list[0].Id)
+	assert.Equal(t, "10", list[8].Id)
+	assert.Equal(t, map[string]int{"A": 25}, list[0].Contents)
+}
+
+func TestBoltStorage_Records(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "records.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	// Every kind of record has its own bucket and sequence.
+	require.NoError(t, storage.CreateRateCard(&swagger.RateCard{Carrier: "Parcelly"}))
+	require.NoError(t, storage.CreateContainerType(&swagger.ContainerType{Name: "Box"}))
+	require.NoError(t, storage.CreateHierarchy(&swagger.Hierarchy{Name: "Cartons"}))
+	require.NoError(t, storage.CreateWarehouse(&swagger.Warehouse{Name: "Main"}))
+	require.NoError(t, storage.CreateKit(&swagger.Kit{Name: "Starter"}))
+	require.NoError(t, storage.CreateCustomer(&swagger.Customer{Name: "Acme"}))
+
+	card, err := storage.GetRateCard("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Parcelly", card.Carrier)
+	containerType, err := storage.GetContainerType("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Box", containerType.Name)
+	hierarchy, err := storage.GetHierarchy("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Cartons", hierarchy.Name)
+	warehouse, err := storage.GetWarehouse("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Main", warehouse.Name)
+	kit, err := storage.GetKit("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Starter", kit.Name)
+	customer, err := storage.GetCustomer("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", customer.Name)
+
+	customer.Name = "Acme Ltd"
+	require.NoError(t, storage.UpdateCustomer(customer))
+	require.NoError(t, storage.DeleteKit("1"))
+	kits, err := storage.ListKits()
+	require.NoError(t, err)
+	assert.Empty(t, kits)
+	customers, err := storage.ListCustomers()
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Ltd", customers[0].Name)
+}
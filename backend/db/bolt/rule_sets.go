@@ -0,0 +1,78 @@
+package bolt
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"ship_line/swagger"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ruleSetsBucket holds every version of the packing rules, keyed by version.
+var ruleSetsBucket = []byte("ruleSets")
+
+// ListRuleSets returns every stored rule set ordered by version.
+func (b *BoltStorage) ListRuleSets() ([]swagger.RuleSet, error) {
+	var sets []swagger.RuleSet
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(ruleSetsBucket).ForEach(func(_, data []byte) error {
+			var set swagger.RuleSet
+			if err := json.Unmarshal(data, &set); err != nil {
+				return err
+			}
+			sets = append(sets, set)
+			return nil
+		})
+	})
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Version < sets[j].Version })
+	return sets, err
+}
+
+// GetRuleSet retrieves a rule set by version. It returns nil if it does not
+// exist.
+func (b *BoltStorage) GetRuleSet(version int) (*swagger.RuleSet, error) {
+	var set *swagger.RuleSet
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(ruleSetsBucket).Get([]byte(strconv.Itoa(version)))
+		if data == nil {
+			return nil // not stored
+		}
+		set = &swagger.RuleSet{}
+		return json.Unmarshal(data, set)
+	})
+	return set, err
+}
+
+// LatestRuleSet retrieves the most recently stored rule set. It returns nil if
+// none has been stored.
+func (b *BoltStorage) LatestRuleSet() (*swagger.RuleSet, error) {
+	var set *swagger.RuleSet
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ruleSetsBucket)
+		data := bucket.Get([]byte(strconv.FormatUint(bucket.Sequence(), 10)))
+		if data == nil {
+			return nil // not stored
+		}
+		set = &swagger.RuleSet{}
+		return json.Unmarshal(data, set)
+	})
+	return set, err
+}
+
+// CreateRuleSet assigns the next version to set and stores it.
+func (b *BoltStorage) CreateRuleSet(set *swagger.RuleSet) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ruleSetsBucket)
+		if bucket == nil {
+			return bolt.ErrBucketNotFound
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		set.Version = int(seq)
+		return putJSON(bucket, strconv.Itoa(set.Version), set)
+	})
+}
@@ -0,0 +1,41 @@
+package bolt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestBoltStorage_RuleSets(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "rules.db"))
+	require.NoError(t, err)
+	defer storage.Close()
+
+	set, err := storage.LatestRuleSet()
+	require.NoError(t, err)
+	assert.Nil(t, set)
+
+	for _, name := range []string{"first", "second"} {
+		set := &swagger.RuleSet{Rules: []swagger.PolicyRule{{Name: name}}}
+		require.NoError(t, storage.CreateRuleSet(set))
+	}
+
+	set, err = storage.LatestRuleSet()
+	require.NoError(t, err)
+	require.NotNil(t, set)
+	assert.Equal(t, 2, set.Version)
+	assert.Equal(t, "second", set.Rules[0].Name)
+
+	set, err = storage.GetRuleSet(1)
+	require.NoError(t, err)
+	require.NotNil(t, set)
+	assert.Equal(t, "first", set.Rules[0].Name)
+
+	sets, err := storage.ListRuleSets()
+	require.NoError(t, err)
+	require.Len(t, sets, 2)
+	assert.Equal(t, 1, sets[0].Version)
+}
@@ -0,0 +1,35 @@
+package bolt
+
+import "ship_line/swagger"
+
+var warehousesBucket = []byte("warehouses")
+
+// warehouses returns the record store of the warehouses.
+func (b *BoltStorage) warehouses() recordStore[swagger.Warehouse] {
+	return recordStore[swagger.Warehouse]{db: b.db, bucket: warehousesBucket, id: func(warehouse *swagger.Warehouse) *string { return &warehouse.Id }}
+}
+
+// ListWarehouses returns every stored warehouse ordered by ID.
+func (b *BoltStorage) ListWarehouses() ([]swagger.Warehouse, error) {
+	return b.warehouses().list()
+}
+
+// GetWarehouse retrieves a warehouse by ID. It returns nil if it does not exist.
+func (b *BoltStorage) GetWarehouse(id string) (*swagger.Warehouse, error) {
+	return b.warehouses().get(id)
+}
+
+// CreateWarehouse assigns the next warehouse ID to warehouse and stores it.
+func (b *BoltStorage) CreateWarehouse(warehouse *swagger.Warehouse) error {
+	return b.warehouses().create(warehouse)
+}
+
+// UpdateWarehouse overwrites a stored warehouse.
+func (b *BoltStorage) UpdateWarehouse(warehouse *swagger.Warehouse) error {
+	return b.warehouses().update(warehouse)
+}
+
+// DeleteWarehouse removes a warehouse by ID if it exists.
+func (b *BoltStorage) DeleteWarehouse(id string) error {
+	return b.warehouses().delete(id)
+}
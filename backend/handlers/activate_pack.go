@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+// ActivatePack handles POST /v1/packs/{id}/activate.
+// With ?dryRun=true nothing is stored and the response previews the change,
+// as for CreatePack.
+func (h *Handler) ActivatePack(c *gin.Context) {
+	if previewDryRun(c, func(opts services.PreviewOptions) (*swagger.PackSizesPreview, error) {
+		return h.ps.PreviewSetPackActive(c.Param("id"), true, opts)
+	}) {
+		return
+	}
+	pack, err := h.ps.ActivatePack(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pack)
+}
+
+// DeactivatePack handles POST /v1/packs/{id}/deactivate.
+// The pack stays in the catalogue but is no longer used for calculations.
+// ?dryRun=true previews the change as for ActivatePack.
+func (h *Handler) DeactivatePack(c *gin.Context) {
+	if previewDryRun(c, func(opts services.PreviewOptions) (*swagger.PackSizesPreview, error) {
+		return h.ps.PreviewSetPackActive(c.Param("id"), false, opts)
+	}) {
+		return
+	}
+	pack, err := h.ps.DeactivatePack(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pack)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// AmendOrder handles PATCH /v1/orders/:id.
+// It expects a JSON payload like: { "items": 1001 } and repacks the order with
+// as few pack changes as possible, returning the packs to add and remove.
+// The customer and attributes of the order cannot be changed.
+func (h *Handler) AmendOrder(c *gin.Context) {
+	var payload swagger.OrderPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+	if payload.Items < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "items must be a non-negative integer"})
+		return
+	}
+	if payload.CustomerId != nil || payload.Attributes != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "only items can be amended; the customer and attributes of an order are kept"})
+		return
+	}
+
+	amendment, err := h.ps.AmendOrder(c.Param("id"), payload.Items)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, amendment)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_AmendOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.POST("/v1/orders", handler.CreateOrder)
+	router.PATCH("/v1/orders/:id", handler.AmendOrder)
+	router.GET("/v1/loose-stock", handler.GetLooseStock)
+	amend := func(id, body string) swagger.OrderAmendment {
+		w := send(router, "PATCH", "/v1/orders/"+id, body)
+		require.Equal(t, http.StatusOK, w.Code)
+		var amendment swagger.OrderAmendment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &amendment))
+		return amendment
+	}
+
+	w := send(router, "POST", "/v1/orders", `{"items": 501}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var order swagger.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+
+	t.Run("Increase adds a pack", func(t *testing.T) {
+		amendment := amend(order.Id, `{"items": 1001}`)
+		assert.Equal(t, 501, amendment.PreviousItemsOrdered)
+		assert.Equal(t, map[string]int{"500": 1}, amendment.PacksAdded)
+		assert.Empty(t, amendment.PacksRemoved)
+		assert.Equal(t, 1001, amendment.Order.ItemsOrdered)
+		assert.Equal(t, 1250, *amendment.Order.Result.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"500": 2, "250": 1}, *amendment.Order.Result.PacksUsed)
+		history := *amendment.Order.History
+		require.Len(t, history, 2)
+		assert.Equal(t, swagger.Amended, history[1].Type)
+		assert.Equal(t, map[string]int{"500": 1}, *history[1].PacksAdded)
+		assert.Contains(t, send(router, "GET", "/v1/loose-stock", "").Body.String(), `"items":249`)
+	})
+
+	t.Run("Decrease removes packs", func(t *testing.T) {
+		amendment := amend(order.Id, `{"items": 200}`)
+		assert.Equal(t, 1001, amendment.PreviousItemsOrdered)
+		assert.Empty(t, amendment.PacksAdded)
+		assert.Equal(t, map[string]int{"500": 2}, amendment.PacksRemoved)
+		assert.Equal(t, 250, *amendment.Order.Result.TotalItemsUsed)
+		// The overage that is no longer shipped is taken back from the pool.
+		assert.Contains(t, send(router, "GET", "/v1/loose-stock", "").Body.String(), `"items":50`)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, send(router, "PATCH", "/v1/orders/999", `{"items": 10}`).Code)
+		assert.Equal(t, http.StatusBadRequest, send(router, "PATCH", "/v1/orders/"+order.Id, `{"items": -1}`).Code)
+		assert.Equal(t, http.StatusBadRequest, send(router, "PATCH", "/v1/orders/"+order.Id, `{"items": 10, "customerId": "1"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, send(router, "PATCH", "/v1/orders/"+order.Id, `{"items": 10, "attributes": {"channel": "retail"}}`).Code)
+	})
+}
+
+func TestHandler_AmendOrderPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.POST("/v1/customers", handler.CreateCustomer)
+	router.POST("/v1/orders", handler.CreateOrder)
+	router.PATCH("/v1/orders/:id", handler.AmendOrder)
+
+	w := send(router, "POST", "/v1/customers", `{"name": "Acme", "policy": {"forbiddenPackSizes": [250]}}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var customer swagger.Customer
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &customer))
+	w = send(router, "POST", "/v1/orders", `{"items": 1000, "customerId": "`+customer.Id+`", "attributes": {"channel": "retail"}}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var order swagger.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, map[string]string{"channel": "retail"}, *order.Attributes)
+
+	// The amendment keeps the customer's policy: no 250-pack is added.
+	w = send(router, "PATCH", "/v1/orders/"+order.Id, `{"items": 1250}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var amendment swagger.OrderAmendment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &amendment))
+	assert.Equal(t, map[string]int{"500": 1}, amendment.PacksAdded)
+	assert.Equal(t, map[string]int{"1000": 1, "500": 1}, *amendment.Order.Result.PacksUsed)
+	assert.Equal(t, customer.Id, *amendment.Order.CustomerId)
+}
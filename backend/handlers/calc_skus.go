@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// SkuCalcHandler handles POST /v1/calc/skus.
+// It expects a JSON payload like: { "items": { "A": 25, "B": 7 } } and fills
+// the order with kits and single-SKU packs.
+func (h *Handler) SkuCalcHandler(c *gin.Context) {
+	var payload swagger.SkuOrder
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	result, err := h.ps.CalculateSkus(payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// CreateContainerType handles POST /v1/container-types.
+func (h *Handler) CreateContainerType(c *gin.Context) {
+	var payload swagger.ContainerTypePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	containerType, err := h.ps.CreateContainerType(payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, containerType)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_ContainerTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500})
+	router := gin.Default()
+	router.GET("/v1/container-types", handler.ListContainerTypes)
+	router.POST("/v1/container-types", handler.CreateContainerType)
+	router.GET("/v1/container-types/:id", handler.GetContainerType)
+	router.PUT("/v1/container-types/:id", handler.UpdateContainerType)
+	router.DELETE("/v1/container-types/:id", handler.DeleteContainerType)
+	router.GET("/v1/calc", handler.CalcHandler)
+
+	w := send(router, "POST", "/v1/container-types", `{"name": "Box", "maxItems": 1000}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("Containers", func(t *testing.T) {
+		w := send(router, "GET", "/v1/calc?items=2250&containers=true", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var result swagger.CalcResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.NotNil(t, result.Containers)
+		containers := *result.Containers
+		require.Len(t, containers, 3)
+		assert.Equal(t, map[string]int{"500": 2}, containers[0].PacksUsed)
+		assert.Equal(t, map[string]int{"500": 2}, containers[1].PacksUsed)
+		assert.Equal(t, map[string]int{"250": 1}, containers[2].PacksUsed)
+	})
+
+	t.Run("Invalid containers value", func(t *testing.T) {
+		w := send(router, "GET", "/v1/calc?items=2250&containers=maybe", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Layout", func(t *testing.T) {
+		// The box has no dimensions and the migrated packs have none either.
+		w := send(router, "GET", "/v1/calc?items=2250&layout=true", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = send(router, "POST", "/v1/container-types", `{"name": "Carton", "dimensions": {"length": 60, "width": 40, "height": 40}}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		w = send(router, "GET", "/v1/calc?items=2250&layout=true", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "has no dimensions")
+		assert.Equal(t, http.StatusNoContent, send(router, "DELETE", "/v1/container-types/2", "").Code)
+	})
+
+	t.Run("Invalid container type", func(t *testing.T) {
+		w := send(router, "POST", "/v1/container-types", `{"name": "Crate"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		w := send(router, "PUT", "/v1/container-types/1", `{"name": "Small box", "maxItems": 200}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		w = send(router, "GET", "/v1/calc?items=2250&containers=true", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		assert.Equal(t, http.StatusNoContent, send(router, "DELETE", "/v1/container-types/1", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/container-types/1", "").Code)
+	})
+}
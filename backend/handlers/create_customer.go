@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// CreateCustomer handles POST /v1/customers.
+func (h *Handler) CreateCustomer(c *gin.Context) {
+	var payload swagger.CustomerPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	customer, err := h.ps.CreateCustomer(payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, customer)
+}
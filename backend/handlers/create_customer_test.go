@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_Customers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.GET("/v1/customers", handler.ListCustomers)
+	router.POST("/v1/customers", handler.CreateCustomer)
+	router.GET("/v1/customers/:id", handler.GetCustomer)
+	router.PUT("/v1/customers/:id", handler.UpdateCustomer)
+	router.DELETE("/v1/customers/:id", handler.DeleteCustomer)
+	router.GET("/v1/calc", handler.CalcHandler)
+	router.POST("/v1/orders", handler.CreateOrder)
+
+	require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/customers", `{"name": "Acme", "policy": {"forbiddenPackSizes": [250]}}`).Code)
+	require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/customers", `{"name": "Globex", "policy": {"maxOveragePercent": 10}}`).Code)
+
+	t.Run("Calculation applies the policy", func(t *testing.T) {
+		w := send(router, "GET", "/v1/calc?items=501", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var result swagger.CalcResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, map[string]int{"500": 1, "250": 1}, *result.PacksUsed)
+
+		w = send(router, "GET", "/v1/calc?items=501&customerId=1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var constrained swagger.CalcResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &constrained))
+		assert.Equal(t, map[string]int{"1000": 1}, *constrained.PacksUsed)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, send(router, "GET", "/v1/calc?items=501&customerId=2", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/calc?items=501&customerId=9", "").Code)
+	})
+
+	t.Run("Order creation applies the policy", func(t *testing.T) {
+		w := send(router, "POST", "/v1/orders", `{"items": 501, "customerId": "1"}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var order swagger.Order
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+		assert.Equal(t, "1", *order.CustomerId)
+		assert.Equal(t, map[string]int{"1000": 1}, *order.Result.PacksUsed)
+
+		assert.Equal(t, http.StatusNotFound, send(router, "POST", "/v1/orders", `{"items": 501, "customerId": "9"}`).Code)
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(router, "POST", "/v1/customers", `{"name": "Initech", "policy": {"maxPacks": 0}}`).Code)
+		assert.Equal(t, http.StatusBadRequest, send(router, "PUT", "/v1/customers/1", `{"name": "Acme", "policy": {"objective": "speed"}}`).Code)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, send(router, "PUT", "/v1/customers/2", `{"name": "Globex"}`).Code)
+		assert.Equal(t, http.StatusOK, send(router, "GET", "/v1/calc?items=501&customerId=2", "").Code)
+		assert.Equal(t, http.StatusNoContent, send(router, "DELETE", "/v1/customers/2", "").Code)
+		w := send(router, "GET", "/v1/customers", "")
+		var customers []swagger.Customer
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &customers))
+		require.Len(t, customers, 1)
+		assert.Equal(t, "Acme", customers[0].Name)
+	})
+}
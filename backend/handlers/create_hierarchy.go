@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// CreateHierarchy handles POST /v1/hierarchies.
+func (h *Handler) CreateHierarchy(c *gin.Context) {
+	var payload swagger.HierarchyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	hierarchy, err := h.ps.CreateHierarchy(payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, hierarchy)
+}
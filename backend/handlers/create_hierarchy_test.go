@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_Hierarchies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.GET("/v1/hierarchies", handler.ListHierarchies)
+	router.POST("/v1/hierarchies", handler.CreateHierarchy)
+	router.GET("/v1/hierarchies/:id", handler.GetHierarchy)
+	router.PUT("/v1/hierarchies/:id", handler.UpdateHierarchy)
+	router.DELETE("/v1/hierarchies/:id", handler.DeleteHierarchy)
+	router.GET("/v1/calc", handler.CalcHandler)
+
+	w := send(router, "POST", "/v1/hierarchies", `{"name": "Warehouse", "levels": [
+		{"name": "carton", "sizes": [5000]}, {"name": "pallet", "sizes": [20000]}]}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("Nested distribution", func(t *testing.T) {
+		w := send(router, "GET", "/v1/calc?items=45750&hierarchy=1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var result swagger.CalcResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.NotNil(t, result.Units)
+		var summary []string
+		for _, unit := range *result.Units {
+			summary = append(summary, unit.Level)
+			assert.Equal(t, unit.Level != "pack", unit.Contents != nil)
+		}
+		assert.Equal(t, []string{"pallet", "carton", "pack", "pack"}, summary)
+	})
+
+	t.Run("Invalid hierarchy", func(t *testing.T) {
+		w := send(router, "POST", "/v1/hierarchies", `{"name": "Flat", "levels": []}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		w := send(router, "PUT", "/v1/hierarchies/1", `{"name": "Cartons", "levels": [{"name": "carton", "sizes": [2000]}]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		w = send(router, "GET", "/v1/hierarchies", "")
+		var hierarchies []swagger.Hierarchy
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hierarchies))
+		require.Len(t, hierarchies, 1)
+		assert.Equal(t, "Cartons", hierarchies[0].Name)
+
+		assert.Equal(t, http.StatusNoContent, send(router, "DELETE", "/v1/hierarchies/1", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/hierarchies/1", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/calc?items=500&hierarchy=1", "").Code)
+	})
+}
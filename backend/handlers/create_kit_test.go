@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_Kits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.GET("/v1/kits", handler.ListKits)
+	router.POST("/v1/kits", handler.CreateKit)
+	router.GET("/v1/kits/:id", handler.GetKit)
+	router.PUT("/v1/kits/:id", handler.UpdateKit)
+	router.DELETE("/v1/kits/:id", handler.DeleteKit)
+	router.POST("/v1/calc/skus", handler.SkuCalcHandler)
+
+	require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/kits", `{"name": "Starter", "contents": {"A": 250, "B": 250}}`).Code)
+
+	t.Run("Multi-SKU order", func(t *testing.T) {
+		w := send(router, "POST", "/v1/calc/skus", `{"items": {"A": 750, "B": 250}}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		var result swagger.SkuResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, map[string]int{"1": 1}, result.KitsUsed)
+		require.Len(t, result.Lines, 2)
+		assert.Equal(t, map[string]int{"500": 1}, result.Lines[0].PacksUsed)
+		assert.Equal(t, 0, result.Overage)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(router, "POST", "/v1/kits", `{"name": "Empty", "contents": {}}`).Code)
+		assert.Equal(t, http.StatusBadRequest, send(router, "POST", "/v1/calc/skus", `{"items": {}}`).Code)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, send(router, "PUT", "/v1/kits/1", `{"name": "Starter", "contents": {"A": 500}}`).Code)
+		w := send(router, "GET", "/v1/kits", "")
+		var kits []swagger.Kit
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &kits))
+		require.Len(t, kits, 1)
+		assert.Equal(t, map[string]int{"A": 500}, kits[0].Contents)
+
+		assert.Equal(t, http.StatusNoContent, send(router, "DELETE", "/v1/kits/1", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/kits/1", "").Code)
+	})
+}
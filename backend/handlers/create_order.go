@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+// CreateOrder handles POST /v1/orders.
+// It expects a JSON payload like: { "items": 12001 } and stores the order
+// together with its calculated pack distribution. An optional "customerId"
+// packs the order under the policy of that customer, and "attributes" such as
+// {"channel": "wholesale"} are matched by the packing rules. With a "quoteId"
+// the order is placed from that quote and takes its items and options.
+func (h *Handler) CreateOrder(c *gin.Context) {
+	var payload swagger.OrderPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+	if payload.Items < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "items must be a non-negative integer"})
+		return
+	}
+
+	if payload.QuoteId != nil {
+		if payload.CustomerId != nil || payload.Attributes != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "customerId and attributes are taken from the quote"})
+			return
+		}
+		order, err := h.ps.AcceptQuote(*payload.QuoteId, payload.Items)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusCreated, order)
+		return
+	}
+
+	var opts services.CalcOptions
+	if payload.CustomerId != nil {
+		opts.Customer = *payload.CustomerId
+	}
+	if payload.Attributes != nil {
+		opts.Attributes = *payload.Attributes
+	}
+	order, err := h.ps.CreateOrder(payload.Items, opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, order)
+}
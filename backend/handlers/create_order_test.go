@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_Orders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.POST("/v1/orders", handler.CreateOrder)
+	router.GET("/v1/orders", handler.ListOrders)
+	router.GET("/v1/orders/:id", handler.GetOrder)
+
+	t.Run("Create and get", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/v1/orders", bytes.NewBufferString(`{"items": 501}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var created swagger.Order
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, 750, *created.Result.TotalItemsUsed)
+
+		req, _ = http.NewRequest("GET", "/v1/orders/"+created.Id, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		req, _ = http.NewRequest("GET", "/v1/orders", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var orders []swagger.Order
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &orders))
+		assert.Len(t, orders, 1)
+	})
+
+	t.Run("Unknown order", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/orders/999", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Negative items", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/v1/orders", bytes.NewBufferString(`{"items": -1}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
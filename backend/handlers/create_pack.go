@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+// CreatePack handles POST /v1/packs.
+// It expects a JSON payload like: { "size": 250, "sku": "BOX-250" }
+// With ?dryRun=true nothing is stored; the response compares how the sample
+// orders are packed before and after adding the pack.
+func (h *Handler) CreatePack(c *gin.Context) {
+	var payload swagger.PackPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+	if previewDryRun(c, func(opts services.PreviewOptions) (*swagger.PackSizesPreview, error) {
+		return h.ps.PreviewCreatePack(payload, opts)
+	}) {
+		return
+	}
+
+	pack, err := h.ps.CreatePack(payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, pack)
+}
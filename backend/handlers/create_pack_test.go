@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_Packs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500})
+	router := gin.Default()
+	router.GET("/v1/packs", handler.ListPacks)
+	router.POST("/v1/packs", handler.CreatePack)
+	router.GET("/v1/packs/:id", handler.GetPack)
+	router.PUT("/v1/packs/:id", handler.UpdatePack)
+	router.DELETE("/v1/packs/:id", handler.DeletePack)
+	router.POST("/v1/packs/:id/activate", handler.ActivatePack)
+	router.POST("/v1/packs/:id/deactivate", handler.DeactivatePack)
+	router.GET("/v1/calc", handler.CalcHandler)
+	router.GET("/v1/pack-sizes", handler.GetPackSizes)
+
+	t.Run("Create", func(t *testing.T) {
+		w := send(router, "POST", "/v1/packs", `{"size": 1000, "sku": "BOX-1000", "weight": 12.5}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var pack swagger.Pack
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pack))
+		assert.Equal(t, "3", pack.Id)
+		assert.True(t, pack.Active)
+
+		w = send(router, "GET", "/v1/pack-sizes", "")
+		assert.JSONEq(t, `{"pack_sizes": [250, 500, 1000]}`, w.Body.String())
+	})
+
+	t.Run("Duplicate size", func(t *testing.T) {
+		w := send(router, "POST", "/v1/packs", `{"size": 250}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Invalid size", func(t *testing.T) {
+		w := send(router, "POST", "/v1/packs", `{"size": -5}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		preview := func(method, path, body string) swagger.PackSizesPreview {
+			w := send(router, method, path, body)
+			require.Equal(t, http.StatusOK, w.Code)
+			var preview swagger.PackSizesPreview
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+			assert.True(t, preview.DryRun)
+			return preview
+		}
+		p := preview("POST", "/v1/packs?dryRun=true&samples=750", `{"size": 750}`)
+		assert.Equal(t, []int{750}, p.Added)
+		assert.True(t, p.Samples[0].ExactAfter)
+		p = preview("PUT", "/v1/packs/1?dryRun=true", `{"size": 300}`)
+		assert.Equal(t, []int{300}, p.Added)
+		assert.Equal(t, []int{250}, p.Removed)
+		p = preview("DELETE", "/v1/packs/1?dryRun=true", "")
+		assert.Equal(t, []int{250}, p.Removed)
+		p = preview("POST", "/v1/packs/2/deactivate?dryRun=true", "")
+		assert.Equal(t, []int{500}, p.Removed)
+		p = preview("POST", "/v1/packs/2/activate?dryRun=true", "")
+		assert.Empty(t, p.Added)
+
+		assert.Equal(t, http.StatusBadRequest, send(router, "DELETE", "/v1/packs/1?dryRun=maybe", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "DELETE", "/v1/packs/99?dryRun=true", "").Code)
+		assert.Equal(t, http.StatusConflict, send(router, "POST", "/v1/packs?dryRun=true", `{"size": 500}`).Code)
+
+		// Nothing was stored.
+		w := send(router, "GET", "/v1/pack-sizes", "")
+		assert.JSONEq(t, `{"pack_sizes": [250, 500, 1000]}`, w.Body.String())
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		w := send(router, "PUT", "/v1/packs/1", `{"size": 250, "active": false}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		w = send(router, "GET", "/v1/pack-sizes", "")
+		assert.JSONEq(t, `{"pack_sizes": [500, 1000]}`, w.Body.String())
+
+		w = send(router, "DELETE", "/v1/packs/1", "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = send(router, "GET", "/v1/packs/1", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = send(router, "GET", "/v1/packs", "")
+		var packs []swagger.Pack
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &packs))
+		assert.Len(t, packs, 2)
+	})
+	t.Run("Deactivate and schedule", func(t *testing.T) {
+		w := send(router, "POST", "/v1/packs/2/deactivate", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		w = send(router, "GET", "/v1/calc?items=501", "")
+		assert.JSONEq(t, `{"itemsOrdered": 501, "totalItemsUsed": 1000, "packsUsed": {"1000": 1}, "packs": [{"packId": "3", "size": 1000, "count": 1}], "shippingUnits": 1, "totalWeight": 12.5}`, w.Body.String())
+
+		w = send(router, "POST", "/v1/packs", `{"size": 600, "effectiveFrom": "2030-01-01T00:00:00Z"}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		w = send(router, "GET", "/v1/calc?items=501&at=2030-06-01T00:00:00Z", "")
+		assert.Contains(t, w.Body.String(), `"packsUsed":{"600":1}`)
+
+		w = send(router, "GET", "/v1/calc?items=501&at=tomorrow", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = send(router, "POST", "/v1/packs/2/activate", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// CreateQuote handles POST /v1/quotes.
+// It expects a JSON payload like: { "items": 12001, "objective": "price" } and
+// stores the priced distribution until the quote expires.
+func (h *Handler) CreateQuote(c *gin.Context) {
+	var req swagger.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	quote, err := h.ps.CreateQuote(req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, quote)
+}
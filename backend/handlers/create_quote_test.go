@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_Quotes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, nil)
+	router := gin.Default()
+	router.POST("/v1/packs", handler.CreatePack)
+	router.POST("/v1/quotes", handler.CreateQuote)
+	router.GET("/v1/quotes/:id", handler.GetQuote)
+	router.POST("/v1/customers", handler.CreateCustomer)
+	router.PUT("/v1/packs/:id", handler.UpdatePack)
+	router.POST("/v1/orders", handler.CreateOrder)
+	for _, body := range []string{
+		`{"size": 250, "price": 4, "currency": "EUR"}`,
+		`{"size": 500, "price": 9, "currency": "EUR"}`,
+	} {
+		require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/packs", body).Code)
+	}
+
+	t.Run("Create and get", func(t *testing.T) {
+		w := send(router, "POST", "/v1/quotes", `{"items": 501, "objective": "price", "validDays": 30}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var quote swagger.Quote
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quote))
+		assert.Equal(t, 12.0, quote.Total)
+		assert.Equal(t, map[string]int{"250": 3}, *quote.Result.PacksUsed)
+
+		w = send(router, "GET", "/v1/quotes/"+quote.Id, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Customer policy", func(t *testing.T) {
+		w := send(router, "POST", "/v1/customers", `{"name": "Acme", "policy": {"forbiddenPackSizes": [250]}}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		w = send(router, "POST", "/v1/quotes", `{"items": 501, "objective": "price", "customerId": "1", "attributes": {"channel": "web"}}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var quote swagger.Quote
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quote))
+		assert.Equal(t, map[string]int{"500": 2}, *quote.Result.PacksUsed)
+		assert.Equal(t, "1", *quote.CustomerId)
+		assert.Equal(t, map[string]string{"channel": "web"}, *quote.Attributes)
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		w := send(router, "POST", "/v1/quotes", `{"items": 501, "customerId": "1", "attributes": {"channel": "web"}}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var quote swagger.Quote
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quote))
+		require.Equal(t, map[string]int{"500": 2}, *quote.Result.PacksUsed)
+		// The order honours the quote after the catalogue changes.
+		w = send(router, "PUT", "/v1/packs/2", `{"size": 500, "price": 20, "currency": "EUR", "active": false}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = send(router, "POST", "/v1/orders", `{"items": 500, "quoteId": "`+quote.Id+`"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = send(router, "POST", "/v1/orders", `{"items": 501, "quoteId": "`+quote.Id+`", "customerId": "1"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = send(router, "POST", "/v1/orders", `{"items": 0, "quoteId": "99"}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = send(router, "POST", "/v1/orders", `{"items": 501, "quoteId": "`+quote.Id+`"}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var order swagger.Order
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+		assert.Equal(t, quote.Id, *order.QuoteId)
+		assert.Equal(t, "1", *order.CustomerId)
+		assert.Equal(t, map[string]string{"channel": "web"}, *order.Attributes)
+		assert.Equal(t, map[string]int{"500": 2}, *order.Result.PacksUsed)
+		assert.Equal(t, quote.Total, *order.Total)
+		assert.Equal(t, "EUR", *order.Currency)
+
+		w = send(router, "GET", "/v1/quotes/"+quote.Id, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &quote))
+		assert.Equal(t, order.Id, *quote.OrderId)
+		w = send(router, "POST", "/v1/orders", `{"items": 0, "quoteId": "`+quote.Id+`"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Unknown customer", func(t *testing.T) {
+		w := send(router, "POST", "/v1/quotes", `{"items": 501, "customerId": "99"}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Unknown quote", func(t *testing.T) {
+		w := send(router, "GET", "/v1/quotes/99", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Invalid objective", func(t *testing.T) {
+		w := send(router, "POST", "/v1/quotes", `{"items": 501, "objective": "speed"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_RateCards(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, nil)
+	router := gin.Default()
+	router.POST("/v1/packs", handler.CreatePack)
+	router.GET("/v1/rate-cards", handler.ListRateCards)
+	router.POST("/v1/rate-cards", handler.CreateRateCard)
+	router.GET("/v1/rate-cards/:id", handler.GetRateCard)
+	router.PUT("/v1/rate-cards/:id", handler.UpdateRateCard)
+	router.DELETE("/v1/rate-cards/:id", handler.DeleteRateCard)
+	router.GET("/v1/calc", handler.CalcHandler)
+	for _, body := range []string{
+		`{"size": 250, "weight": 3, "cost": 0.5}`,
+		`{"size": 500, "weight": 5, "cost": 0.8}`,
+	} {
+		require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/packs", body).Code)
+	}
+
+	card := `{"carrier": "Parcelly", "currency": "EUR", "dimDivisor": 5000, "parcelFee": 1,
+		"brackets": [{"maxWeight": 5, "price": 4}, {"maxWeight": 10, "price": 6}]}`
+	w := send(router, "POST", "/v1/rate-cards", card)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("Shipping objective", func(t *testing.T) {
+		w := send(router, "GET", "/v1/calc?items=501&objective=shipping", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var result swagger.CalcResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.NotNil(t, result.Shipping)
+		assert.Equal(t, "1", result.Shipping.RateCardId)
+		assert.Equal(t, 8.3, result.Shipping.TotalCost)
+	})
+
+	t.Run("Invalid card", func(t *testing.T) {
+		w := send(router, "POST", "/v1/rate-cards", `{"carrier": "Parcelly", "currency": "EUR", "dimDivisor": 0, "brackets": []}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		w := send(router, "PUT", "/v1/rate-cards/1", `{"carrier": "Boxit", "currency": "EUR", "dimDivisor": 4000,
+			"brackets": [{"maxWeight": 20, "price": 9}]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		w = send(router, "GET", "/v1/rate-cards", "")
+		var cards []swagger.RateCard
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cards))
+		require.Len(t, cards, 1)
+		assert.Equal(t, "Boxit", cards[0].Carrier)
+
+		assert.Equal(t, http.StatusNoContent, send(router, "DELETE", "/v1/rate-cards/1", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/rate-cards/1", "").Code)
+	})
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// CreateReturn handles POST /v1/orders/:id/returns.
+// It expects a JSON payload like:
+// { "packs": [ { "size": 500, "count": 1 }, { "size": 250, "count": 1, "opened": true, "items": 120 } ] }
+// and answers with the order, whose history records the return.
+func (h *Handler) CreateReturn(c *gin.Context) {
+	var payload swagger.ReturnPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	order, err := h.ps.ReturnPacks(c.Param("id"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, order)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_CreateReturn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.POST("/v1/orders", handler.CreateOrder)
+	router.POST("/v1/orders/:id/returns", handler.CreateReturn)
+	router.GET("/v1/loose-stock", handler.GetLooseStock)
+	router.POST("/v1/warehouses", handler.CreateWarehouse)
+	router.GET("/v1/warehouses/:id", handler.GetWarehouse)
+	router.GET("/v1/fulfilment", handler.FulfilmentHandler)
+
+	require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/warehouses", `{"name": "Main", "stock": {"1000": 0, "500": 3}}`).Code)
+	// 1001 ships 1000 + 250 and puts 249 items into the loose-item pool.
+	w := send(router, "POST", "/v1/orders", `{"items": 1001}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var order swagger.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	require.Len(t, *order.History, 1)
+	assert.Equal(t, swagger.Created, (*order.History)[0].Type)
+
+	fulfil := func(items string) swagger.FulfilmentPlan {
+		w := send(router, "GET", "/v1/fulfilment?items="+items, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var plan swagger.FulfilmentPlan
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+		require.Len(t, plan.Shipments, 1)
+		return plan
+	}
+	assert.Equal(t, map[string]int{"500": 2}, *fulfil("1000").Shipments[0].Result.PacksUsed)
+
+	t.Run("Restock and open", func(t *testing.T) {
+		w := send(router, "POST", "/v1/orders/"+order.Id+"/returns",
+			`{"packs": [{"size": 1000, "count": 1}, {"size": 250, "count": 1, "opened": true, "items": 100}]}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var returned swagger.Order
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &returned))
+		require.Len(t, *returned.History, 2)
+		event := (*returned.History)[1]
+		assert.Equal(t, swagger.Returned, event.Type)
+		assert.Equal(t, map[string]int{"1000": 1, "250": 1}, *event.PacksReturned)
+		assert.Equal(t, map[string]int{"1000": 1}, *event.PacksRestocked)
+		assert.Equal(t, 100, *event.LooseItemsReturned)
+
+		assert.Equal(t, "1", *event.WarehouseId)
+
+		var warehouse swagger.Warehouse
+		require.NoError(t, json.Unmarshal(send(router, "GET", "/v1/warehouses/1", "").Body.Bytes(), &warehouse))
+		assert.Equal(t, map[string]int{"1000": 1, "500": 3}, *warehouse.Stock)
+		assert.JSONEq(t, `{"items": 349}`, send(router, "GET", "/v1/loose-stock", "").Body.String())
+	})
+
+	t.Run("Next order uses the returned pack", func(t *testing.T) {
+		assert.Equal(t, map[string]int{"1000": 1}, *fulfil("1000").Shipments[0].Result.PacksUsed)
+	})
+
+	t.Run("Validated against the shipment", func(t *testing.T) {
+		for _, body := range []string{
+			`{"packs": []}`,
+			`{"packs": [{"size": 500, "count": 1}]}`,
+			`{"packs": [{"size": 1000, "count": 1}]}`,
+			`{"packs": [{"size": 250, "count": 0}]}`,
+			`{"packs": [{"size": 250, "count": 1, "items": 10}]}`,
+		} {
+			assert.Equal(t, http.StatusBadRequest, send(router, "POST", "/v1/orders/"+order.Id+"/returns", body).Code, body)
+		}
+		assert.Equal(t, http.StatusNotFound, send(router, "POST", "/v1/orders/999/returns", `{"packs": [{"size": 250, "count": 1}]}`).Code)
+	})
+
+	t.Run("Warehouse", func(t *testing.T) {
+		w := send(router, "POST", "/v1/orders", `{"items": 3000}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var order swagger.Order
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+		require.Contains(t, *order.Result.PacksUsed, "1000")
+
+		body := `{"packs": [{"size": 1000, "count": 1}], "warehouseId": "99"}`
+		assert.Equal(t, http.StatusNotFound, send(router, "POST", "/v1/orders/"+order.Id+"/returns", body).Code)
+		// With several warehouses the one to restock must be named.
+		require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/warehouses", `{"name": "Overflow"}`).Code)
+		body = `{"packs": [{"size": 1000, "count": 1}]}`
+		assert.Equal(t, http.StatusBadRequest, send(router, "POST", "/v1/orders/"+order.Id+"/returns", body).Code)
+		body = `{"packs": [{"size": 1000, "count": 1}], "warehouseId": "1"}`
+		assert.Equal(t, http.StatusCreated, send(router, "POST", "/v1/orders/"+order.Id+"/returns", body).Code)
+	})
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// CreateRuleSet handles POST /v1/rule-sets.
+// It expects a JSON payload like:
+// { "rules": [{ "name": "wholesale", "when": { "attributes": { "channel": "wholesale" } },
+// "then": { "forbiddenPackSizes": [250] } }] }
+// and stores it as the next version, which becomes the active rule set.
+func (h *Handler) CreateRuleSet(c *gin.Context) {
+	var payload swagger.RuleSetPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	set, err := h.ps.CreateRuleSet(payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, set)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_Warehouses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.GET("/v1/warehouses", handler.ListWarehouses)
+	router.POST("/v1/warehouses", handler.CreateWarehouse)
+	router.GET("/v1/warehouses/:id", handler.GetWarehouse)
+	router.PUT("/v1/warehouses/:id", handler.UpdateWarehouse)
+	router.DELETE("/v1/warehouses/:id", handler.DeleteWarehouse)
+	router.GET("/v1/fulfilment", handler.FulfilmentHandler)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, send(router, "GET", "/v1/fulfilment?items=100", "").Code)
+	require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/warehouses",
+		`{"name": "North", "stock": {"250": 2, "500": 0, "1000": 0}}`).Code)
+	require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/warehouses",
+		`{"name": "South", "packSizes": [300, 600], "stock": {"300": 1, "600": 1}}`).Code)
+
+	t.Run("Split sourcing", func(t *testing.T) {
+		w := send(router, "GET", "/v1/fulfilment?items=1100", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var plan swagger.FulfilmentPlan
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plan))
+		require.Len(t, plan.Shipments, 2)
+		assert.Equal(t, "1", plan.Shipments[0].WarehouseId)
+		assert.Equal(t, "2", plan.Shipments[1].WarehouseId)
+		assert.Equal(t, 1100, plan.TotalItemsUsed)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, send(router, "GET", "/v1/fulfilment?items=x", "").Code)
+		assert.Equal(t, http.StatusBadRequest, send(router, "POST", "/v1/warehouses", `{"name": ""}`).Code)
+	})
+
+	t.Run("Update and delete", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, send(router, "PUT", "/v1/warehouses/2", `{"name": "South"}`).Code)
+		var plan swagger.FulfilmentPlan
+		require.NoError(t, json.Unmarshal(send(router, "GET", "/v1/fulfilment?items=1100", "").Body.Bytes(), &plan))
+		require.Len(t, plan.Shipments, 1)
+		assert.Equal(t, "2", plan.Shipments[0].WarehouseId)
+
+		assert.Equal(t, http.StatusNoContent, send(router, "DELETE", "/v1/warehouses/2", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/warehouses/2", "").Code)
+		w := send(router, "GET", "/v1/warehouses", "")
+		var warehouses []swagger.Warehouse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &warehouses))
+		assert.Len(t, warehouses, 1)
+	})
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteContainerType handles DELETE /v1/container-types/{id}.
+func (h *Handler) DeleteContainerType(c *gin.Context) {
+	if err := h.ps.DeleteContainerType(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteCustomer handles DELETE /v1/customers/{id}.
+func (h *Handler) DeleteCustomer(c *gin.Context) {
+	if err := h.ps.DeleteCustomer(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
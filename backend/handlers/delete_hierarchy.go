@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteHierarchy handles DELETE /v1/hierarchies/{id}.
+func (h *Handler) DeleteHierarchy(c *gin.Context) {
+	if err := h.ps.DeleteHierarchy(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
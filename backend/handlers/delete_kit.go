@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteKit handles DELETE /v1/kits/{id}.
+func (h *Handler) DeleteKit(c *gin.Context) {
+	if err := h.ps.DeleteKit(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
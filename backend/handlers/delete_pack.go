@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+// DeletePack handles DELETE /v1/packs/{id}.
+// With ?dryRun=true nothing is deleted and the response previews the
+// removal, as for CreatePack.
+func (h *Handler) DeletePack(c *gin.Context) {
+	if previewDryRun(c, func(opts services.PreviewOptions) (*swagger.PackSizesPreview, error) {
+		return h.ps.PreviewDeletePack(c.Param("id"), opts)
+	}) {
+		return
+	}
+	if err := h.ps.DeletePack(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -1,12 +1,17 @@
 package handlers
 
 import (
-	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
 )
 
 // DeletePackSizeHandler handles DELETE /pack-sizes/{size}
+// With ?dryRun=true nothing is deleted; the response compares how the sample
+// orders are packed before and after the removal.
 func (h *Handler) DeletePackSizeHandler(c *gin.Context) {
 	size, err := strconv.Atoi(c.Param("size"))
 	if err != nil {
@@ -14,6 +19,12 @@ func (h *Handler) DeletePackSizeHandler(c *gin.Context) {
 		return
 	}
 
+	if previewDryRun(c, func(opts services.PreviewOptions) (*swagger.PackSizesPreview, error) {
+		return h.ps.PreviewDeletePackSize(size, opts)
+	}) {
+		return
+	}
+
 	err = h.ps.DeletePackSizeHandler(size)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -1 +1,56 @@
 package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+func TestHandler_DeletePackSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &dummyRepo{packSizes: []int{250, 500, 1000}}
+	handler := &Handler{ps: services.NewPackService(repo)}
+	router := gin.Default()
+	router.DELETE("/v1/pack-sizes/:size", handler.DeletePackSizeHandler)
+
+	t.Run("Invalid size", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "/v1/pack-sizes/abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "/v1/pack-sizes/250?dryRun=true&samples=750", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var preview swagger.PackSizesPreview
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+		assert.Equal(t, []int{500, 1000}, preview.After)
+		assert.True(t, preview.Samples[0].ExactFillLost)
+	})
+
+	t.Run("Invalid dry run", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "/v1/pack-sizes/250?dryRun=yes", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []int{250, 500, 1000}, repo.packSizes)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "/v1/pack-sizes/250", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteQuantityRule handles DELETE /v1/quantity-rules/{sku}.
+func (h *Handler) DeleteQuantityRule(c *gin.Context) {
+	if err := h.ps.DeleteQuantityRule(c.Param("sku")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteRateCard handles DELETE /v1/rate-cards/{id}.
+func (h *Handler) DeleteRateCard(c *gin.Context) {
+	if err := h.ps.DeleteRateCard(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteWarehouse handles DELETE /v1/warehouses/{id}.
+func (h *Handler) DeleteWarehouse(c *gin.Context) {
+	if err := h.ps.DeleteWarehouse(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// isDryRun reports whether the request asks for a preview via ?dryRun=true.
+// It writes a 400 response and returns ok false when the value is malformed.
+func isDryRun(c *gin.Context) (dryRun, ok bool) {
+	v := c.Query("dryRun")
+	if v == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(v)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'dryRun' value"})
+		return false, false
+	}
+	return dryRun, true
+}
+
+// previewOptions reads the sample orders and availability caps of a dry run.
+// It writes a 400 response and returns false when they are malformed.
+func previewOptions(c *gin.Context) (services.PreviewOptions, bool) {
+	var opts services.PreviewOptions
+	var err error
+	if opts.Samples, err = utils.ParseIntList(c.Query("samples")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'samples' value: " + err.Error()})
+		return opts, false
+	}
+	if opts.Availability, err = utils.ParseIntPairs(c.Query("availability")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'availability' value: " + err.Error()})
+		return opts, false
+	}
+	return opts, true
+}
+
+// previewDryRun answers a request with ?dryRun=true with the preview built by
+// preview from its sample orders and availability caps. It returns false,
+// without writing a response, when the request is not a dry run.
+func previewDryRun(c *gin.Context, preview func(services.PreviewOptions) (*swagger.PackSizesPreview, error)) bool {
+	dryRun, ok := isDryRun(c)
+	if !ok {
+		return true
+	}
+	if !dryRun {
+		return false
+	}
+	opts, ok := previewOptions(c)
+	if !ok {
+		return true
+	}
+	result, err := preview(opts)
+	if err != nil {
+		respondError(c, err)
+		return true
+	}
+	c.JSON(http.StatusOK, result)
+	return true
+}
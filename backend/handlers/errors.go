@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+)
+
+// respondError maps a service error to the matching HTTP status.
+func respondError(c *gin.Context, err error) {
+	var validationErr *services.ValidationError
+	switch {
+	case errors.As(err, &validationErr) && len(validationErr.Fields) > 0:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "fields": validationErr.Fields})
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOrderNotFound), errors.Is(err, services.ErrPackNotFound),
+		errors.Is(err, services.ErrQuoteNotFound), errors.Is(err, services.ErrRateCardNotFound),
+		errors.Is(err, services.ErrContainerTypeNotFound), errors.Is(err, services.ErrHierarchyNotFound),
+		errors.Is(err, services.ErrWarehouseNotFound), errors.Is(err, services.ErrKitNotFound),
+		errors.Is(err, services.ErrQuantityRuleNotFound), errors.Is(err, services.ErrCustomerNotFound),
+		errors.Is(err, services.ErrRuleSetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrDuplicatePackSize), errors.Is(err, services.ErrQuoteAccepted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrShipmentLimits), errors.Is(err, services.ErrNoCarrier),
+		errors.Is(err, services.ErrNoContainer), errors.Is(err, services.ErrNoWarehouse),
+		errors.Is(err, services.ErrUncoverable), errors.Is(err, services.ErrPolicy):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrQuoteExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotSupported):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
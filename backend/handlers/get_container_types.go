@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetContainerType handles GET /v1/container-types/{id}.
+func (h *Handler) GetContainerType(c *gin.Context) {
+	containerType, err := h.ps.GetContainerType(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, containerType)
+}
+
+// ListContainerTypes handles GET /v1/container-types.
+func (h *Handler) ListContainerTypes(c *gin.Context) {
+	types, err := h.ps.ListContainerTypes()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, types)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCustomer handles GET /v1/customers/{id}.
+func (h *Handler) GetCustomer(c *gin.Context) {
+	customer, err := h.ps.GetCustomer(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, customer)
+}
+
+// ListCustomers handles GET /v1/customers.
+func (h *Handler) ListCustomers(c *gin.Context) {
+	customers, err := h.ps.ListCustomers()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, customers)
+}
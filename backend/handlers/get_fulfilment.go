@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FulfilmentHandler handles GET /v1/fulfilment?items=X.
+// It sources the order from the stored warehouses and returns a distribution
+// per warehouse that ships part of it.
+func (h *Handler) FulfilmentHandler(c *gin.Context) {
+	items, err := strconv.Atoi(c.Query("items"))
+	if err != nil || items < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'items' value"})
+		return
+	}
+	// TODO: get from config
+	const maxOrder = 1000000000000
+	if items > maxOrder {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("order %d exceeds maximum allowed value of %d", items, maxOrder)})
+		return
+	}
+
+	plan, err := h.ps.PlanFulfilment(items)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
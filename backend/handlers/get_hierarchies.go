@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetHierarchy handles GET /v1/hierarchies/{id}.
+func (h *Handler) GetHierarchy(c *gin.Context) {
+	hierarchy, err := h.ps.GetHierarchy(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hierarchy)
+}
+
+// ListHierarchies handles GET /v1/hierarchies.
+func (h *Handler) ListHierarchies(c *gin.Context) {
+	hierarchies, err := h.ps.ListHierarchies()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hierarchies)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetKit handles GET /v1/kits/{id}.
+func (h *Handler) GetKit(c *gin.Context) {
+	kit, err := h.ps.GetKit(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, kit)
+}
+
+// ListKits handles GET /v1/kits.
+func (h *Handler) ListKits(c *gin.Context) {
+	kits, err := h.ps.ListKits()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, kits)
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLooseStock handles GET /v1/loose-stock.
+func (h *Handler) GetLooseStock(c *gin.Context) {
+	stock, err := h.ps.GetLooseStock()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stock)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_LooseStock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.POST("/v1/orders", handler.CreateOrder)
+	router.GET("/v1/loose-stock", handler.GetLooseStock)
+	router.PUT("/v1/loose-stock", handler.UpdateLooseStock)
+	looseStock := func() int {
+		w := send(router, "GET", "/v1/loose-stock", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var stock swagger.LooseStock
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stock))
+		return stock.Items
+	}
+	createOrder := func(body string) swagger.Order {
+		w := send(router, "POST", "/v1/orders", body)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var order swagger.Order
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+		return order
+	}
+
+	t.Run("Overage goes to the pool", func(t *testing.T) {
+		order := createOrder(`{"items": 501}`)
+		assert.Nil(t, order.Result.LooseItemsUsed)
+		assert.Equal(t, 249, looseStock())
+	})
+
+	t.Run("Later orders draw from the pool first", func(t *testing.T) {
+		order := createOrder(`{"items": 449}`)
+		assert.Equal(t, 249, *order.Result.LooseItemsUsed)
+		assert.Equal(t, 449, *order.Result.ItemsOrdered)
+		assert.Equal(t, 499, *order.Result.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"250": 1}, *order.Result.PacksUsed)
+		assert.Equal(t, 50, looseStock())
+
+		order = createOrder(`{"items": 30}`)
+		assert.Equal(t, 30, *order.Result.LooseItemsUsed)
+		assert.Equal(t, map[string]int{}, *order.Result.PacksUsed)
+		assert.Equal(t, 20, looseStock())
+	})
+
+	t.Run("Stock count", func(t *testing.T) {
+		w := send(router, "PUT", "/v1/loose-stock", `{"items": 120}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 120, looseStock())
+		assert.Equal(t, http.StatusBadRequest, send(router, "PUT", "/v1/loose-stock", `{"items": -1}`).Code)
+	})
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOrder handles GET /v1/orders/{id}.
+func (h *Handler) GetOrder(c *gin.Context) {
+	order, err := h.ps.GetOrder(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// ListOrders handles GET /v1/orders.
+func (h *Handler) ListOrders(c *gin.Context) {
+	orders, err := h.ps.ListOrders()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, orders)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/utils"
+)
+
+// AnalyzePackSizes handles GET /v1/pack-sizes/analysis?sizes=250,500&from=1&to=10000.
+// Without sizes the configured pack set is analysed.
+func (h *Handler) AnalyzePackSizes(c *gin.Context) {
+	sizes, err := utils.ParseIntList(c.Query("sizes"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'sizes' value: " + err.Error()})
+		return
+	}
+	from, err := strconv.Atoi(c.DefaultQuery("from", "1"))
+	if err != nil || from < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' value"})
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "10000"))
+	if err != nil || to < from || to > services.MaxRangeQuantity {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' value (must be between 'from' and " + strconv.Itoa(services.MaxRangeQuantity) + ")"})
+		return
+	}
+
+	analysis, err := h.ps.AnalyzePackSizes(sizes, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, analysis)
+}
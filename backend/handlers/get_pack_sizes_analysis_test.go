@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+func TestHandler_AnalyzePackSizes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &dummyRepo{packSizes: []int{250, 500, 1000}}
+	handler := &Handler{ps: services.NewPackService(repo)}
+	router := gin.Default()
+	router.GET("/v1/pack-sizes/analysis", handler.AnalyzePackSizes)
+
+	t.Run("Proposed sizes", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/pack-sizes/analysis?sizes=3,5&to=20", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp swagger.PackSetAnalysis
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 7, *resp.FrobeniusNumber)
+	})
+
+	t.Run("Invalid sizes", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/pack-sizes/analysis?sizes=3,-5", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Sizes too large", func(t *testing.T) {
+		for _, sizes := range []string{"1000000000", "3," + strings.Repeat("7,", services.MaxProposedSizes) + "5"} {
+			req, _ := http.NewRequest("GET", "/v1/pack-sizes/analysis?sizes="+sizes, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code, sizes[:min(len(sizes), 20)])
+		}
+	})
+
+	t.Run("Range too large", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/pack-sizes/analysis?to=100000000", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
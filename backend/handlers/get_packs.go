@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPack handles GET /v1/packs/{id}.
+func (h *Handler) GetPack(c *gin.Context) {
+	pack, err := h.ps.GetPack(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pack)
+}
+
+// ListPacks handles GET /v1/packs.
+func (h *Handler) ListPacks(c *gin.Context) {
+	packs, err := h.ps.ListPacks()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, packs)
+}
@@ -1,14 +1,37 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
+	"ship_line/utils"
 )
 
-// CalcHandler handles GET /v1/calc?items=X.
+// CalcHandler handles GET /v1/calc?items=X[&mode=exact|backorder][&objective=price|shipping].
+// In exact mode an order that cannot be packed without overage is answered with
+// 422 and the nearest feasible quantities below and above it. Backorder mode
+// takes an optional tolerance and penalty, and every mode accepts per-size
+// availability caps such as availability=250:10,500:3. An RFC 3339 at=
+// timestamp calculates with the packs in effect at that time, and maxWeight
+// and maxVolume limit the shipment (422 when no distribution fits).
+// containers=true splits the packs into the stored container types and adds a
+// manifest per container, layout=true arranges them in the container types with
+// dimensions and returns the position of every pack, and hierarchy=ID nests the packs into the levels of a
+// packaging hierarchy. pallet=ID plans pallet loads on a container type; the
+// plan is then also available as a CSV or plain-text manifest through the
+// Accept header. break=true lets the solver open a pack and ship part of it
+// loose, weighing breakCost per opened pack and looseCost per loose item
+// against the overage. sku=SKU enforces the quantity rule of the product and
+// answers a violation with 400 and the offending fields. customerId=ID applies
+// the packing policy of a customer; 422 when no distribution satisfies it.
+// The active packing rules are applied as well, matching the order attributes
+// given as attributes=channel:wholesale.
 func (h *Handler) CalcHandler(c *gin.Context) {
 	itemsStr := c.Query("items")
 	if itemsStr == "" {
@@ -28,9 +51,128 @@ func (h *Handler) CalcHandler(c *gin.Context) {
 		return
 	}
 
-	result, err := h.ps.CalculatePacks(items)
+	var opts services.CalcOptions
+	switch mode := c.Query("mode"); mode {
+	case "", "default":
+	case string(services.ModeExact), string(services.ModeBackorder):
+		opts.Mode = services.CalcMode(mode)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'mode' value"})
+		return
+	}
+	switch objective := c.Query("objective"); objective {
+	case "", string(swagger.Packs):
+	case string(swagger.Price):
+		opts.Objective = services.ObjectivePrice
+	case string(swagger.Shipping):
+		opts.Objective = services.ObjectiveShipping
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'objective' value"})
+		return
+	}
+	if v := c.Query("tolerance"); v != "" {
+		opts.ShortTolerance, err = strconv.Atoi(v)
+		if err != nil || opts.ShortTolerance < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'tolerance' value"})
+			return
+		}
+	}
+	if v := c.Query("penalty"); v != "" {
+		opts.ShortPenalty, err = strconv.ParseFloat(v, 64)
+		if err != nil || opts.ShortPenalty < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'penalty' value"})
+			return
+		}
+	}
+	opts.Availability, err = utils.ParseIntPairs(c.Query("availability"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'availability' value: " + err.Error()})
+		return
+	}
+
+	for name, limit := range map[string]*float64{"maxWeight": &opts.MaxWeight, "maxVolume": &opts.MaxVolume} {
+		if v := c.Query(name); v != "" {
+			*limit, err = strconv.ParseFloat(v, 64)
+			if err != nil || *limit <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid '%s' value", name)})
+				return
+			}
+		}
+	}
+	if v := c.Query("at"); v != "" {
+		opts.At, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'at' value: expected an RFC 3339 timestamp"})
+			return
+		}
+	}
+
+	if v := c.Query("containers"); v != "" {
+		opts.Containers, err = strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'containers' value"})
+			return
+		}
+	}
+
+	if v := c.Query("layout"); v != "" {
+		opts.Layout, err = strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'layout' value"})
+			return
+		}
+	}
+	if v := c.Query("break"); v != "" {
+		opts.BreakPacks, err = strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'break' value"})
+			return
+		}
+	}
+	for name, cost := range map[string]*float64{"breakCost": &opts.BreakCost, "looseCost": &opts.LooseItemCost} {
+		if v := c.Query(name); v != "" {
+			*cost, err = strconv.ParseFloat(v, 64)
+			if err != nil || *cost < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid '%s' value", name)})
+				return
+			}
+		}
+	}
+	opts.Hierarchy = c.Query("hierarchy")
+	opts.Sku = c.Query("sku")
+	opts.Customer = c.Query("customerId")
+	opts.Attributes, err = utils.ParsePairs(c.Query("attributes"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'attributes' value: " + err.Error()})
+		return
+	}
+	opts.Pallet = c.Query("pallet")
+	format := gin.MIMEJSON
+	if opts.Pallet != "" {
+		format = c.NegotiateFormat(gin.MIMEJSON, "text/csv", gin.MIMEPlain)
+		if format == "" {
+			c.JSON(http.StatusNotAcceptable, gin.H{"error": "supported formats are application/json, text/csv and text/plain"})
+			return
+		}
+	}
+
+	result, err := h.ps.CalculatePacksWithOptions(items, opts)
+	var fillErr *services.ExactFillError
+	if errors.As(err, &fillErr) {
+		c.JSON(http.StatusUnprocessableEntity, swagger.ExactFillError{
+			Error:        utils.Ptr(fillErr.Error()),
+			ItemsOrdered: utils.Ptr(fillErr.Order),
+			NearestBelow: fillErr.Below,
+			NearestAbove: fillErr.Above,
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if format != gin.MIMEJSON {
+		writePalletManifest(c, format, result.Pallets)
 		return
 	}
 	c.JSON(http.StatusOK, result)
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// flushEvery is how many rows are written between flushes of a streamed export.
+const flushEvery = 1000
+
+// CalcRangeHandler handles GET /v1/calc/range?from=1&to=50000&step=1.
+// It streams the distribution of every quantity in the range as CSV or JSON,
+// chosen through the Accept header (JSON by default).
+func (h *Handler) CalcRangeHandler(c *gin.Context) {
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' value"})
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' value"})
+		return
+	}
+	step, err := strconv.Atoi(c.DefaultQuery("step", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'step' value"})
+		return
+	}
+
+	format := c.NegotiateFormat(gin.MIMEJSON, "text/csv")
+	if format == "" {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": "supported formats are application/json and text/csv"})
+		return
+	}
+
+	calc, err := h.ps.CalculateRange(from, to, step)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	rows := 0
+	flush := func() {
+		if rows++; rows%flushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+	if format == "text/csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="distribution.csv"`)
+		c.Status(http.StatusOK)
+		w := csv.NewWriter(c.Writer)
+		header := []string{"items_ordered", "total_items_used", "overage"}
+		for _, size := range calc.PackSizes {
+			header = append(header, "packs_"+strconv.Itoa(size))
+		}
+		_ = w.Write(header)
+		_ = calc.Each(func(result *swagger.CalcResult) error {
+			row := []string{
+				strconv.Itoa(*result.ItemsOrdered),
+				strconv.Itoa(*result.TotalItemsUsed),
+				strconv.Itoa(*result.TotalItemsUsed - *result.ItemsOrdered),
+			}
+			for _, size := range calc.PackSizes {
+				row = append(row, strconv.Itoa((*result.PacksUsed)[strconv.Itoa(size)]))
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+			w.Flush()
+			flush()
+			return w.Error()
+		})
+		w.Flush()
+		return
+	}
+
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+	_, _ = c.Writer.WriteString("[")
+	first := true
+	_ = calc.Each(func(result *swagger.CalcResult) error {
+		if !first {
+			if _, err := c.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+		first = false
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+		flush()
+		return nil
+	})
+	_, _ = c.Writer.WriteString("]")
+}
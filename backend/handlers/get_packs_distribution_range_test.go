@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+func TestHandler_CalcRangeHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &dummyRepo{packSizes: []int{250, 500}}
+	handler := &Handler{ps: services.NewPackService(repo)}
+	router := gin.Default()
+	router.GET("/v1/calc/range", handler.CalcRangeHandler)
+
+	t.Run("JSON", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc/range?from=250&to=750&step=250", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var results []swagger.CalcResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+		require.Len(t, results, 3)
+		assert.Equal(t, map[string]int{"500": 1, "250": 1}, *results[2].PacksUsed)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc/range?from=1&to=501&step=250", nil)
+		req.Header.Set("Accept", "text/csv")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "items_ordered,total_items_used,overage,packs_250,packs_500\n"+
+			"1,250,249,1,0\n"+
+			"251,500,249,0,1\n"+
+			"501,750,249,1,1\n", w.Body.String())
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv"))
+	})
+
+	t.Run("Range too large", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc/range?from=1&to=5000000", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc/range?from=1&to=10", nil)
+		req.Header.Set("Accept", "application/xml")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"ship_line/services"
+	"ship_line/swagger"
 )
 
 type dummyRepoForHandler struct {
@@ -76,4 +77,58 @@ func TestHandler_CalcHandler(t *testing.T) {
 		assert.Equal(t, 1, int(packsUsed["500"].(float64)))
 		assert.Equal(t, 1, int(packsUsed["250"].(float64)))
 	})
+	t.Run("Exact mode with exact match", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc?items=1500&mode=exact", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"totalItemsUsed":1500`)
+	})
+
+	t.Run("Exact mode without exact match", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc?items=501&mode=exact", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var resp swagger.ExactFillError
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, 500, *resp.NearestBelow.TotalItemsUsed)
+		assert.Equal(t, 750, *resp.NearestAbove.TotalItemsUsed)
+	})
+
+	t.Run("Invalid mode", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc?items=501&mode=cheap", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Backorder mode", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc?items=501&mode=backorder&tolerance=10", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"itemsBackordered":1`)
+	})
+
+	t.Run("Invalid availability", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc?items=501&availability=250", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Breaking packs", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/calc?items=260&break=true&breakCost=5&looseCost=0.5", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"packsOpened":[{"itemsShipped":10,"itemsToLooseStock":240,"size":250}]`)
+
+		req, _ = http.NewRequest("GET", "/v1/calc?items=260&break=true&looseCost=-1", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
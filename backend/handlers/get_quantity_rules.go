@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetQuantityRule handles GET /v1/quantity-rules/{sku}.
+func (h *Handler) GetQuantityRule(c *gin.Context) {
+	rule, err := h.ps.GetQuantityRule(c.Param("sku"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// ListQuantityRules handles GET /v1/quantity-rules.
+// Frontends use it to validate order quantities before submitting them.
+func (h *Handler) ListQuantityRules(c *gin.Context) {
+	rules, err := h.ps.ListQuantityRules()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetQuote handles GET /v1/quotes/{id}.
+// Expired quotes are answered with 410 Gone.
+func (h *Handler) GetQuote(c *gin.Context) {
+	quote, err := h.ps.GetQuote(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRateCard handles GET /v1/rate-cards/{id}.
+func (h *Handler) GetRateCard(c *gin.Context) {
+	card, err := h.ps.GetRateCard(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, card)
+}
+
+// ListRateCards handles GET /v1/rate-cards.
+func (h *Handler) ListRateCards(c *gin.Context) {
+	cards, err := h.ps.ListRateCards()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cards)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRuleSet handles GET /v1/rule-sets/{version}.
+func (h *Handler) GetRuleSet(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	set, err := h.ps.GetRuleSet(version)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, set)
+}
+
+// ListRuleSets handles GET /v1/rule-sets.
+// The last version listed is the active one.
+func (h *Handler) ListRuleSets(c *gin.Context) {
+	sets, err := h.ps.ListRuleSets()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sets)
+}
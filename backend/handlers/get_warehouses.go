@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetWarehouse handles GET /v1/warehouses/{id}.
+func (h *Handler) GetWarehouse(c *gin.Context) {
+	warehouse, err := h.ps.GetWarehouse(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, warehouse)
+}
+
+// ListWarehouses handles GET /v1/warehouses.
+func (h *Handler) ListWarehouses(c *gin.Context) {
+	warehouses, err := h.ps.ListWarehouses()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, warehouses)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"ship_line/db/bolt"
+	"ship_line/services"
+)
+
+// newBoltHandler returns a Handler backed by a temporary Bolt database.
+func newBoltHandler(t *testing.T, packSizes []int) *Handler {
+	t.Helper()
+	storage, err := bolt.NewBoltStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { storage.Close() })
+	require.NoError(t, storage.SetPackSizes(packSizes))
+	return &Handler{ps: services.NewPackService(storage)}
+}
+
+// jsonRequest returns a request to path with body as its JSON payload.
+func jsonRequest(method, path, body string) *http.Request {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// send serves a JSON request by router and returns the recorded response.
+func send(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, jsonRequest(method, path, body))
+	return w
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// writePalletManifest writes plan as a printable manifest for warehouses
+// without a WMS: CSV with one row per layer for format text/csv, or plain text
+// otherwise.
+func writePalletManifest(c *gin.Context, format string, plan *swagger.PalletPlan) {
+	formatFloat := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	if format == "text/csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="pallets.csv"`)
+		c.Status(http.StatusOK)
+		w := csv.NewWriter(c.Writer)
+		_ = w.Write([]string{"pallet", "layer", "z", "pack_id", "size", "packs", "pallet_height", "pallet_weight"})
+		for i, load := range plan.Pallets {
+			weight := ""
+			if load.Weight != nil {
+				weight = formatFloat(*load.Weight)
+			}
+			for j, layer := range load.Layers {
+				_ = w.Write([]string{
+					strconv.Itoa(i + 1), strconv.Itoa(j + 1), formatFloat(layer.Z),
+					layer.PackId, strconv.Itoa(layer.Size), strconv.Itoa(layer.Packs),
+					formatFloat(load.Height), weight,
+				})
+			}
+		}
+		w.Flush()
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pallet plan: %d × %s (container type %s)\n", len(plan.Pallets), plan.Name, plan.ContainerTypeId)
+	for _, pattern := range plan.Patterns {
+		var blocks []string
+		for _, block := range pattern.Blocks {
+			blocks = append(blocks, fmt.Sprintf("%d×%d of %s×%s cm at (%s, %s)", block.Columns, block.Rows,
+				formatFloat(block.Length), formatFloat(block.Width), formatFloat(block.X), formatFloat(block.Y)))
+		}
+		fmt.Fprintf(&b, "Layer pattern for pack %s (size %d): %d per layer, %s\n",
+			pattern.PackId, pattern.Size, pattern.PacksPerLayer, strings.Join(blocks, " + "))
+	}
+	for i, load := range plan.Pallets {
+		fmt.Fprintf(&b, "\nPallet %d of %d: height %s cm", i+1, len(plan.Pallets), formatFloat(load.Height))
+		if load.Weight != nil {
+			fmt.Fprintf(&b, ", weight %s kg", formatFloat(*load.Weight))
+		}
+		b.WriteString("\n")
+		for j, layer := range load.Layers {
+			fmt.Fprintf(&b, "  Layer %d at %s cm: %d × pack %s (size %d)\n",
+				j+1, formatFloat(layer.Z), layer.Packs, layer.PackId, layer.Size)
+		}
+	}
+	c.String(http.StatusOK, b.String())
+}
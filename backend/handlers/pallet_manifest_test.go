@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_PalletManifest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, nil)
+	router := gin.Default()
+	router.POST("/v1/packs", handler.CreatePack)
+	router.POST("/v1/container-types", handler.CreateContainerType)
+	router.GET("/v1/calc", handler.CalcHandler)
+
+	manifest := func(accept string) *httptest.ResponseRecorder {
+		req := jsonRequest("GET", "/v1/calc?items=20000&pallet=1", "")
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	w := send(router, "POST", "/v1/packs", `{"size": 500, "weight": 10, "dimensions": {"length": 40, "width": 30, "height": 25}}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = send(router, "POST", "/v1/container-types", `{"name": "Euro pallet", "maxWeight": 500, "tareWeight": 25,
+		"dimensions": {"length": 120, "width": 80, "height": 100}}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	t.Run("JSON", func(t *testing.T) {
+		w := send(router, "GET", "/v1/calc?items=20000&pallet=1", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var result swagger.CalcResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.NotNil(t, result.Pallets)
+		assert.Len(t, result.Pallets.Pallets, 2)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		w := manifest("text/csv")
+		require.Equal(t, http.StatusOK, w.Code)
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 6)
+		assert.Equal(t, "pallet,layer,z,pack_id,size,packs,pallet_height,pallet_weight", lines[0])
+		assert.Equal(t, "1,1,0,1,500,8,100,345", lines[1])
+		assert.Equal(t, "2,1,0,1,500,8,25,105", lines[5])
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		w := manifest("text/plain")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Pallet 1 of 2: height 100 cm, weight 345 kg")
+		assert.Contains(t, w.Body.String(), "8 per layer, 4×2 of 30×40 cm at (0, 0)")
+	})
+
+	t.Run("Unknown pallet", func(t *testing.T) {
+		w := send(router, "GET", "/v1/calc?items=20000&pallet=9", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
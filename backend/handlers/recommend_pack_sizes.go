@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/utils"
+)
+
+// RecommendPackSizes handles POST /v1/pack-sizes/recommend?k=3&min=100&max=5000.
+// The order quantities are read as CSV from the "orders" field of a multipart
+// upload, or from the request body otherwise.
+func (h *Handler) RecommendPackSizes(c *gin.Context) {
+	var opts services.RecommendOptions
+	var err error
+	if opts.K, err = strconv.Atoi(c.Query("k")); err != nil || opts.K < 1 || opts.K > services.MaxRecommendSizes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'k' value"})
+		return
+	}
+	if opts.Min, err = strconv.Atoi(c.Query("min")); err != nil || opts.Min < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'min' value"})
+		return
+	}
+	if opts.Max, err = strconv.Atoi(c.Query("max")); err != nil || opts.Max < opts.Min {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'max' value"})
+		return
+	}
+	if opts.PackWeight, err = strconv.ParseFloat(c.DefaultQuery("packWeight", "1"), 64); err != nil || opts.PackWeight < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'packWeight' value"})
+		return
+	}
+
+	orders, err := readOrdersCSV(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid orders CSV: " + err.Error()})
+		return
+	}
+
+	recommendation, err := h.ps.RecommendPackSizes(orders, opts)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, recommendation)
+}
+
+// readOrdersCSV reads order quantities from a multipart "orders" file or from
+// the raw request body.
+func readOrdersCSV(c *gin.Context) ([]int, error) {
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("orders")
+		if err != nil {
+			return nil, err
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		body = f
+	}
+	return utils.ParseQuantitiesCSV(body)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+func TestHandler_RecommendPackSizes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &dummyRepo{packSizes: []int{250, 500}}
+	handler := &Handler{ps: services.NewPackService(repo)}
+	router := gin.Default()
+	router.POST("/v1/pack-sizes/recommend", handler.RecommendPackSizes)
+
+	t.Run("CSV body with header", func(t *testing.T) {
+		body := strings.NewReader("order_id,quantity\n1,300\n2,600\n3,600\n")
+		req, _ := http.NewRequest("POST", "/v1/pack-sizes/recommend?k=2&min=100&max=700", body)
+		req.Header.Set("Content-Type", "text/csv")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp swagger.PackSetRecommendation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []int{300, 600}, resp.PackSizes)
+		assert.Equal(t, 3, resp.Orders)
+	})
+
+	t.Run("Multipart upload", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		part, err := writer.CreateFormFile("orders", "orders.csv")
+		require.NoError(t, err)
+		_, _ = part.Write([]byte("300\n600\n"))
+		require.NoError(t, writer.Close())
+
+		req, _ := http.NewRequest("POST", "/v1/pack-sizes/recommend?k=1&min=100&max=700", &buf)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid CSV", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/v1/pack-sizes/recommend?k=1&min=100&max=700", strings.NewReader("300\nabc\n"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Max too large", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/v1/pack-sizes/recommend?k=1&min=100&max=1000000000", strings.NewReader("300\n"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Missing k", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/v1/pack-sizes/recommend?min=100&max=700", strings.NewReader("300\n"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -30,7 +30,7 @@ func SetupRouter(ps *services.PackService) *Handler {
 	router.Use(cors.New(cors.Config{
 		// TODO: move to config
 		AllowOrigins:     []string{allowOrigins},
-		AllowMethods:     []string{http.MethodGet, http.MethodPut, http.MethodOptions, http.MethodPost, http.MethodDelete},
+		AllowMethods:     []string{http.MethodGet, http.MethodPut, http.MethodOptions, http.MethodPost, http.MethodDelete, http.MethodPatch},
 		AllowHeaders:     []string{"Content-Type"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -44,12 +44,89 @@ func SetupRouter(ps *services.PackService) *Handler {
 
 	// Define the route for pack calculations.
 	router.GET("/v1/calc", handler.CalcHandler)
+	// Define the route to export distributions across a range of orders.
+	router.GET("/v1/calc/range", handler.CalcRangeHandler)
 	// Define the route to update pack sizes.
 	router.PUT("/v1/pack-sizes", handler.UpdatePackSizes)
 	// Define the route to retrieve pack sizes.
 	router.GET("/v1/pack-sizes", handler.GetPackSizes)
+	// Define the route to analyse the current or a proposed pack set.
+	router.GET("/v1/pack-sizes/analysis", handler.AnalyzePackSizes)
+	// Define the route to recommend pack sizes from historical orders.
+	router.POST("/v1/pack-sizes/recommend", handler.RecommendPackSizes)
 	// Define the route to delete a specific pack size.
 	router.DELETE("/v1/pack-sizes/:size", handler.DeletePackSizeHandler)
+	// Define the routes to manage the pack catalogue.
+	router.GET("/v1/packs", handler.ListPacks)
+	router.POST("/v1/packs", handler.CreatePack)
+	router.GET("/v1/packs/:id", handler.GetPack)
+	router.PUT("/v1/packs/:id", handler.UpdatePack)
+	router.DELETE("/v1/packs/:id", handler.DeletePack)
+	router.POST("/v1/packs/:id/activate", handler.ActivatePack)
+	router.POST("/v1/packs/:id/deactivate", handler.DeactivatePack)
+	// Define the routes to manage carrier rate cards.
+	router.GET("/v1/rate-cards", handler.ListRateCards)
+	router.POST("/v1/rate-cards", handler.CreateRateCard)
+	router.GET("/v1/rate-cards/:id", handler.GetRateCard)
+	router.PUT("/v1/rate-cards/:id", handler.UpdateRateCard)
+	router.DELETE("/v1/rate-cards/:id", handler.DeleteRateCard)
+	// Define the routes to manage container types.
+	router.GET("/v1/container-types", handler.ListContainerTypes)
+	router.POST("/v1/container-types", handler.CreateContainerType)
+	router.GET("/v1/container-types/:id", handler.GetContainerType)
+	router.PUT("/v1/container-types/:id", handler.UpdateContainerType)
+	router.DELETE("/v1/container-types/:id", handler.DeleteContainerType)
+	// Define the routes to manage packaging hierarchies.
+	router.GET("/v1/hierarchies", handler.ListHierarchies)
+	router.POST("/v1/hierarchies", handler.CreateHierarchy)
+	router.GET("/v1/hierarchies/:id", handler.GetHierarchy)
+	router.PUT("/v1/hierarchies/:id", handler.UpdateHierarchy)
+	router.DELETE("/v1/hierarchies/:id", handler.DeleteHierarchy)
+	// Define the routes to manage warehouses and source orders from them.
+	router.GET("/v1/warehouses", handler.ListWarehouses)
+	router.POST("/v1/warehouses", handler.CreateWarehouse)
+	router.GET("/v1/warehouses/:id", handler.GetWarehouse)
+	router.PUT("/v1/warehouses/:id", handler.UpdateWarehouse)
+	router.DELETE("/v1/warehouses/:id", handler.DeleteWarehouse)
+	router.GET("/v1/fulfilment", handler.FulfilmentHandler)
+	// Define the routes to manage kits and distribute multi-SKU orders.
+	router.GET("/v1/kits", handler.ListKits)
+	router.POST("/v1/kits", handler.CreateKit)
+	router.GET("/v1/kits/:id", handler.GetKit)
+	router.PUT("/v1/kits/:id", handler.UpdateKit)
+	router.DELETE("/v1/kits/:id", handler.DeleteKit)
+	// Define the routes to manage customers and their packing policies.
+	router.GET("/v1/customers", handler.ListCustomers)
+	router.POST("/v1/customers", handler.CreateCustomer)
+	router.GET("/v1/customers/:id", handler.GetCustomer)
+	router.PUT("/v1/customers/:id", handler.UpdateCustomer)
+	router.DELETE("/v1/customers/:id", handler.DeleteCustomer)
+	// Define the routes to version and test the packing rules.
+	router.GET("/v1/rule-sets", handler.ListRuleSets)
+	router.POST("/v1/rule-sets", handler.CreateRuleSet)
+	router.GET("/v1/rule-sets/:version", handler.GetRuleSet)
+	router.POST("/v1/rule-sets/test", handler.TestRuleSet)
+	// Define the routes to manage order quantity rules per product.
+	router.GET("/v1/quantity-rules", handler.ListQuantityRules)
+	router.GET("/v1/quantity-rules/:sku", handler.GetQuantityRule)
+	router.PUT("/v1/quantity-rules/:sku", handler.SetQuantityRule)
+	router.DELETE("/v1/quantity-rules/:sku", handler.DeleteQuantityRule)
+	router.POST("/v1/calc/skus", handler.SkuCalcHandler)
+	// Define the routes to inspect and correct the loose-item pool.
+	router.GET("/v1/loose-stock", handler.GetLooseStock)
+	router.PUT("/v1/loose-stock", handler.UpdateLooseStock)
+	// Define the routes to create and retrieve price quotes.
+	router.POST("/v1/quotes", handler.CreateQuote)
+	router.GET("/v1/quotes/:id", handler.GetQuote)
+	// Define the routes to create, retrieve and amend orders.
+	router.POST("/v1/orders", handler.CreateOrder)
+	router.GET("/v1/orders", handler.ListOrders)
+	router.GET("/v1/orders/:id", handler.GetOrder)
+	router.PATCH("/v1/orders/:id", handler.AmendOrder)
+	// Define the route to book returned packs against an order.
+	router.POST("/v1/orders/:id/returns", handler.CreateReturn)
+	// Define the route to replay orders against a candidate pack set.
+	router.POST("/v1/simulations", handler.RunSimulation)
 
 	return handler
 }
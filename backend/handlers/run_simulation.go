@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// RunSimulation handles POST /v1/simulations.
+// A JSON body selects the order sample through "source" (stored, csv or
+// synthetic). A multipart upload carries the candidate sizes in the "packSizes"
+// form field and the CSV in the "orders" file field instead.
+func (h *Handler) RunSimulation(c *gin.Context) {
+	var req swagger.SimulationRequest
+	var csvOrders []int
+	var err error
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		req.Source = swagger.SimulationRequestSourceCsv
+		if req.PackSizes, err = utils.ParseIntList(c.PostForm("packSizes")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'packSizes' value: " + err.Error()})
+			return
+		}
+		if csvOrders, err = readOrdersCSV(c); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid orders CSV: " + err.Error()})
+			return
+		}
+	} else {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+			return
+		}
+		if req.Source == swagger.SimulationRequestSourceCsv {
+			if req.Csv == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "csv source requires a csv field"})
+				return
+			}
+			if csvOrders, err = utils.ParseQuantitiesCSV(strings.NewReader(*req.Csv)); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid orders CSV: " + err.Error()})
+				return
+			}
+		}
+	}
+
+	result, err := h.ps.Simulate(req, csvOrders)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
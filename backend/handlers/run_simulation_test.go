@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_RunSimulation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.POST("/v1/orders", handler.CreateOrder)
+	router.POST("/v1/simulations", handler.RunSimulation)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Stored orders", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, post("/v1/orders", `{"items": 300}`).Code)
+		require.Equal(t, http.StatusCreated, post("/v1/orders", `{"items": 600}`).Code)
+
+		w := post("/v1/simulations", `{"packSizes": [300, 600], "source": "stored"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp swagger.SimulationResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 2, resp.Orders)
+		assert.Equal(t, 0, resp.Candidate.TotalOverage)
+	})
+
+	t.Run("Inline CSV", func(t *testing.T) {
+		w := post("/v1/simulations", `{"packSizes": [300], "source": "csv", "csv": "quantity\n300\n"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Missing CSV", func(t *testing.T) {
+		w := post("/v1/simulations", `{"packSizes": [300], "source": "csv"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid synthetic distribution", func(t *testing.T) {
+		w := post("/v1/simulations", `{"packSizes": [300], "source": "synthetic", "synthetic": {"distribution": "uniform", "count": 10}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
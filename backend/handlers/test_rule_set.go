@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// TestRuleSet handles POST /v1/rule-sets/test.
+// It calculates sample orders under a draft rule set, a stored version or the
+// active rule set without storing anything, and reports the matched rules, the
+// resulting constraints and the distribution or error of every sample.
+func (h *Handler) TestRuleSet(c *gin.Context) {
+	var payload swagger.RuleSetTest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	report, err := h.ps.TestRuleSet(payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_RuleSets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.GET("/v1/rule-sets", handler.ListRuleSets)
+	router.POST("/v1/rule-sets", handler.CreateRuleSet)
+	router.GET("/v1/rule-sets/:version", handler.GetRuleSet)
+	router.POST("/v1/rule-sets/test", handler.TestRuleSet)
+	router.GET("/v1/calc", handler.CalcHandler)
+	router.POST("/v1/orders", handler.CreateOrder)
+	packsUsed := func(w *httptest.ResponseRecorder) map[string]int {
+		require.Equal(t, http.StatusOK, w.Code)
+		var result swagger.CalcResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return *result.PacksUsed
+	}
+
+	wholesale := `{"rules": [{"name": "wholesale", "when": {"attributes": {"channel": "wholesale"}}, "then": {"forbiddenPackSizes": [250]}}]}`
+	require.Equal(t, http.StatusCreated, send(router, "POST", "/v1/rule-sets", `{"rules": []}`).Code)
+	w := send(router, "POST", "/v1/rule-sets", wholesale)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var set swagger.RuleSet
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &set))
+	assert.Equal(t, 2, set.Version)
+
+	t.Run("Versions", func(t *testing.T) {
+		var sets []swagger.RuleSet
+		require.NoError(t, json.Unmarshal(send(router, "GET", "/v1/rule-sets", "").Body.Bytes(), &sets))
+		require.Len(t, sets, 2)
+		assert.Empty(t, sets[0].Rules)
+		assert.Equal(t, http.StatusOK, send(router, "GET", "/v1/rule-sets/1", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/rule-sets/3", "").Code)
+		assert.Equal(t, http.StatusBadRequest, send(router, "GET", "/v1/rule-sets/latest", "").Code)
+	})
+
+	t.Run("Active rules apply", func(t *testing.T) {
+		assert.Equal(t, map[string]int{"500": 1, "250": 1}, packsUsed(send(router, "GET", "/v1/calc?items=501", "")))
+		assert.Equal(t, map[string]int{"1000": 1}, packsUsed(send(router, "GET", "/v1/calc?items=501&attributes=channel:wholesale", "")))
+		assert.Equal(t, http.StatusBadRequest, send(router, "GET", "/v1/calc?items=501&attributes=wholesale", "").Code)
+
+		w := send(router, "POST", "/v1/orders", `{"items": 501, "attributes": {"channel": "wholesale"}}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		var order swagger.Order
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+		assert.Equal(t, map[string]int{"1000": 1}, *order.Result.PacksUsed)
+	})
+
+	t.Run("Test endpoint", func(t *testing.T) {
+		w := send(router, "POST", "/v1/rule-sets/test", `{"version": 2, "orders": [{"items": 501}, {"items": 501, "attributes": {"channel": "wholesale"}}]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		var report swagger.RuleSetTestResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, 2, *report.Version)
+		require.Len(t, report.Results, 2)
+		assert.Empty(t, report.Results[0].MatchedRules)
+		assert.Equal(t, []string{"wholesale"}, report.Results[1].MatchedRules)
+		assert.Equal(t, []int{250}, *report.Results[1].Policy.ForbiddenPackSizes)
+		assert.Equal(t, map[string]int{"1000": 1}, *report.Results[1].Result.PacksUsed)
+
+		w = send(router, "POST", "/v1/rule-sets/test", `{"rules": [{"name": "tight", "then": {"maxOveragePercent": 1}}], "orders": [{"items": 501}]}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		report = swagger.RuleSetTestResult{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Nil(t, report.Version)
+		assert.NotNil(t, report.Results[0].Error)
+
+		// A draft is never stored.
+		var sets []swagger.RuleSet
+		require.NoError(t, json.Unmarshal(send(router, "GET", "/v1/rule-sets", "").Body.Bytes(), &sets))
+		assert.Len(t, sets, 2)
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		w := send(router, "POST", "/v1/rule-sets", `{"rules": [{"name": "", "then": {}}]}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var body swagger.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.NotNil(t, body.Fields)
+		assert.Equal(t, "rules.0.name", (*body.Fields)[0].Field)
+		assert.Equal(t, http.StatusBadRequest, send(router, "POST", "/v1/rule-sets/test", `{"orders": []}`).Code)
+	})
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// UpdateContainerType handles PUT /v1/container-types/{id}.
+// The payload replaces every field of the stored container type.
+func (h *Handler) UpdateContainerType(c *gin.Context) {
+	var payload swagger.ContainerTypePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	containerType, err := h.ps.UpdateContainerType(c.Param("id"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, containerType)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// UpdateCustomer handles PUT /v1/customers/{id}.
+// The payload replaces every field of the stored customer.
+func (h *Handler) UpdateCustomer(c *gin.Context) {
+	var payload swagger.CustomerPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	customer, err := h.ps.UpdateCustomer(c.Param("id"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, customer)
+}
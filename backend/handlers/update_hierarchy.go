@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// UpdateHierarchy handles PUT /v1/hierarchies/{id}.
+// The payload replaces every field of the stored packaging hierarchy.
+func (h *Handler) UpdateHierarchy(c *gin.Context) {
+	var payload swagger.HierarchyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	hierarchy, err := h.ps.UpdateHierarchy(c.Param("id"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hierarchy)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// UpdateKit handles PUT /v1/kits/{id}.
+// The payload replaces every field of the stored kit.
+func (h *Handler) UpdateKit(c *gin.Context) {
+	var payload swagger.KitPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	kit, err := h.ps.UpdateKit(c.Param("id"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, kit)
+}
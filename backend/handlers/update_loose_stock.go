@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// UpdateLooseStock handles PUT /v1/loose-stock.
+// It expects a JSON payload like: { "items": 120 } and replaces the number of
+// loose items, for example after a stock count.
+func (h *Handler) UpdateLooseStock(c *gin.Context) {
+	var payload swagger.LooseStock
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	stock, err := h.ps.SetLooseStock(payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stock)
+}
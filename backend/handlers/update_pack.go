@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
+)
+
+// UpdatePack handles PUT /v1/packs/{id}.
+// The payload replaces every field of the stored pack. With ?dryRun=true
+// nothing is stored and the response previews the change, as for CreatePack.
+func (h *Handler) UpdatePack(c *gin.Context) {
+	var payload swagger.PackPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+	if previewDryRun(c, func(opts services.PreviewOptions) (*swagger.PackSizesPreview, error) {
+		return h.ps.PreviewUpdatePack(c.Param("id"), payload, opts)
+	}) {
+		return
+	}
+
+	pack, err := h.ps.UpdatePack(c.Param("id"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pack)
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"ship_line/services"
+	"ship_line/swagger"
 )
 
 // PackSizesPayload defines the expected JSON body for updating pack sizes.
@@ -13,6 +15,8 @@ type PackSizesPayload struct {
 
 // UpdatePackSizes is a Gin handler for updating pack sizes.
 // It expects a JSON payload like: { "pack_sizes": [250,500,1000] }
+// With ?dryRun=true nothing is stored; the response compares how the sample
+// orders (?samples=501,12001) are packed before and after the change.
 func (h *Handler) UpdatePackSizes(c *gin.Context) {
 	var payload PackSizesPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
@@ -30,6 +34,12 @@ func (h *Handler) UpdatePackSizes(c *gin.Context) {
 		}
 	}
 
+	if previewDryRun(c, func(opts services.PreviewOptions) (*swagger.PackSizesPreview, error) {
+		return h.ps.PreviewUpdatePackSizes(payload.PackSizes, opts)
+	}) {
+		return
+	}
+
 	err := h.ps.UpdatePackSizes(payload.PackSizes)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
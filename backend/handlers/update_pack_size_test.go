@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -28,6 +29,7 @@ func (d *dummyRepo) SetPackSizes(sizes []int) error {
 }
 
 func (d *dummyRepo) DeletePackSize(size int) error {
+	d.packSizes = slices.DeleteFunc(d.packSizes, func(s int) bool { return s == size })
 	return nil
 }
 
@@ -128,4 +130,25 @@ func TestHandler_UpdatePackSizes(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, newSizes, sizes)
 	})
+	t.Run("Dry run", func(t *testing.T) {
+		payload := PackSizesPayload{PackSizes: []int{700}}
+		data, err := json.Marshal(payload)
+		require.NoError(t, err)
+		req, err := http.NewRequest("PUT", "/v1/pack-sizes?dryRun=true&samples=700", bytes.NewBuffer(data))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp map[string]interface{}
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		require.NoError(t, err)
+		assert.Equal(t, true, resp["dryRun"])
+
+		// The repository must not have been touched.
+		sizes, err := repo.GetPackSizes()
+		require.NoError(t, err)
+		assert.NotContains(t, sizes, 700)
+	})
 }
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// SetQuantityRule handles PUT /v1/quantity-rules/{sku}.
+// The payload creates or replaces the quantity rule of the product; invalid
+// fields are answered with 400 and a field error each.
+func (h *Handler) SetQuantityRule(c *gin.Context) {
+	var payload swagger.QuantityRulePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	rule, err := h.ps.SetQuantityRule(c.Param("sku"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
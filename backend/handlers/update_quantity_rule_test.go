@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestHandler_QuantityRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newBoltHandler(t, []int{250, 500, 1000})
+	router := gin.Default()
+	router.GET("/v1/quantity-rules", handler.ListQuantityRules)
+	router.GET("/v1/quantity-rules/:sku", handler.GetQuantityRule)
+	router.PUT("/v1/quantity-rules/:sku", handler.SetQuantityRule)
+	router.DELETE("/v1/quantity-rules/:sku", handler.DeleteQuantityRule)
+	router.GET("/v1/calc", handler.CalcHandler)
+	router.POST("/v1/calc/skus", handler.SkuCalcHandler)
+	fieldErrors := func(w *httptest.ResponseRecorder) []swagger.FieldError {
+		var body swagger.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.NotNil(t, body.Fields)
+		return *body.Fields
+	}
+
+	require.Equal(t, http.StatusOK, send(router, "PUT", "/v1/quantity-rules/A",
+		`{"minOrder": 250, "maxOrder": 5000, "multiple": 50, "blockedRanges": [{"from": 1000, "to": 1200}]}`).Code)
+
+	t.Run("Rules are exposed", func(t *testing.T) {
+		w := send(router, "GET", "/v1/quantity-rules", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var rules []swagger.QuantityRule
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rules))
+		require.Len(t, rules, 1)
+		assert.Equal(t, "A", rules[0].Sku)
+		assert.Equal(t, 50, *rules[0].Multiple)
+		assert.Equal(t, http.StatusNotFound, send(router, "GET", "/v1/quantity-rules/B", "").Code)
+	})
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		w := send(router, "PUT", "/v1/quantity-rules/B", `{"minOrder": 10, "maxOrder": 5, "multiple": 0}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []swagger.FieldError{
+			{Field: "maxOrder", Message: "must not be below minOrder"},
+			{Field: "multiple", Message: "must be positive"},
+		}, fieldErrors(w))
+	})
+
+	t.Run("Calculation enforces the rule", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, send(router, "GET", "/v1/calc?items=500&sku=A", "").Code)
+		assert.Equal(t, http.StatusOK, send(router, "GET", "/v1/calc?items=120", "").Code)
+
+		w := send(router, "GET", "/v1/calc?items=1110&sku=A", "")
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []swagger.FieldError{
+			{Field: "items", Message: "must be a multiple of 50"},
+			{Field: "items", Message: "must not be between 1000 and 1200"},
+		}, fieldErrors(w))
+
+		w = send(router, "POST", "/v1/calc/skus", `{"items": {"A": 100, "B": 100}}`)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []swagger.FieldError{{Field: "items.A", Message: "must be at least 250"}}, fieldErrors(w))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, send(router, "DELETE", "/v1/quantity-rules/A", "").Code)
+		assert.Equal(t, http.StatusNotFound, send(router, "DELETE", "/v1/quantity-rules/A", "").Code)
+		assert.Equal(t, http.StatusOK, send(router, "GET", "/v1/calc?items=1110&sku=A", "").Code)
+	})
+}
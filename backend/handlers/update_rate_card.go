@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// UpdateRateCard handles PUT /v1/rate-cards/{id}.
+// The payload replaces every field of the stored rate card.
+func (h *Handler) UpdateRateCard(c *gin.Context) {
+	var payload swagger.RateCardPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	card, err := h.ps.UpdateRateCard(c.Param("id"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, card)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"ship_line/swagger"
+)
+
+// UpdateWarehouse handles PUT /v1/warehouses/{id}.
+// The payload replaces every field of the stored warehouse.
+func (h *Handler) UpdateWarehouse(c *gin.Context) {
+	var payload swagger.WarehousePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	warehouse, err := h.ps.UpdateWarehouse(c.Param("id"), payload)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, warehouse)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// AmendOrder changes the quantity of a stored order. The new distribution
+// ships the same total as a fresh calculation for items, but is assembled
+// from the packs already stored so that as few packs as possible have to be
+// added or removed; ties go to fewer packs overall. The amendment lists those
+// changes next to the updated order and is recorded in its history.
+//
+// Loose items the order took from the pool are kept, except those no longer
+// needed, which go back to the pool. The pool is also adjusted by the change
+// in overage, as far as its stock allows.
+func (ps *PackService) AmendOrder(id string, items int) (*swagger.OrderAmendment, error) {
+	repo, err := ps.orderRepo()
+	if err != nil {
+		return nil, err
+	}
+	order, err := ps.GetOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	previous, previousItems := order.Result, order.ItemsOrdered
+	current := map[int]int{}
+	if previous.PacksUsed != nil {
+		if current, err = utils.ParseMapKeys(*previous.PacksUsed); err != nil {
+			return nil, fmt.Errorf("failed to read stored packs: %w", err)
+		}
+	}
+
+	loose := 0
+	if previous.LooseItemsUsed != nil {
+		loose = min(*previous.LooseItemsUsed, items)
+	}
+	// The amendment is packed with the customer, attributes and objective of
+	// the order, and packs are only added in the sizes their policy allows.
+	opts := orderOptions(order)
+	fresh, bySize, err := ps.calculate(items-loose, opts)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := ps.resolvePolicy(items-loose, opts)
+	if err != nil {
+		return nil, err
+	}
+	packSizes, _, err := ps.loadPackSet(time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pack sizes: %w", err)
+	}
+	freshPacks, err := utils.ParseMapKeys(*fresh.PacksUsed)
+	if err != nil {
+		return nil, err
+	}
+	added, removed := repackDelta(current, freshPacks, sortedDesc(withoutExcluded(packSizes, policy.ExcludedSizes)))
+	packs := applyDelta(current, added, removed)
+	if checkPacks(items-loose, packs, policy) != nil {
+		// Keeping stored packs broke the policy; ship the fresh distribution.
+		added, removed = packDelta(current, freshPacks)
+		packs = freshPacks
+	}
+	result := &swagger.CalcResult{
+		ItemsOrdered:   utils.Ptr(items),
+		TotalItemsUsed: utils.Ptr(*fresh.TotalItemsUsed + loose),
+		PacksUsed:      utils.Ptr(utils.ConvertMapKeys(packs)),
+	}
+	if loose > 0 {
+		result.LooseItemsUsed = utils.Ptr(loose)
+	}
+	order.ItemsOrdered = items
+	order.Result = *describeResult(result, bySize)
+	order.History = utils.Ptr(append(history(order), swagger.OrderEvent{
+		At:           time.Now().UTC(),
+		Type:         swagger.Amended,
+		ItemsOrdered: utils.Ptr(items),
+		PacksAdded:   utils.Ptr(utils.ConvertMapKeys(added)),
+		PacksRemoved: utils.Ptr(utils.ConvertMapKeys(removed)),
+	}))
+	if err := repo.UpdateOrder(order); err != nil {
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	if pool, _ := ps.looseStockRepo(); pool != nil {
+		returned := 0
+		if previous.LooseItemsUsed != nil {
+			returned = *previous.LooseItemsUsed - loose
+		}
+		// The overage of the previous distribution went to the pool when the
+		// order was stored; only the difference is added or taken back.
+		change := returned + (*result.TotalItemsUsed - items) - (*previous.TotalItemsUsed - previousItems)
+		switch {
+		case change > 0:
+			err = pool.AddLooseStock(change)
+		case change < 0:
+			_, err = pool.TakeLooseStock(-change)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to adjust loose stock: %w", err)
+		}
+	}
+
+	return &swagger.OrderAmendment{
+		Order:                *order,
+		PreviousItemsOrdered: previousItems,
+		PacksAdded:           utils.ConvertMapKeys(added),
+		PacksRemoved:         utils.ConvertMapKeys(removed),
+	}, nil
+}
+
+// repackDelta finds the packs to add to and remove from current so that the
+// result holds as many items as target, changing as few packs as possible.
+// Packs are added in packSizes, sorted in descending order, and removed from
+// current only.
+//
+// Every total r that can be removed from current is tabulated together with
+// every total that can be added; a removal r then needs an addition of
+// target - held + r. Large changes are first reduced with the largest packs, so
+// that the tables stay within dpThreshold items of the change. When the tables
+// find no combination, the delta between current and target is returned.
+func repackDelta(current, target map[int]int, packSizes []int) (added, removed map[int]int) {
+	added, removed = map[int]int{}, map[int]int{}
+	held, want := 0, 0
+	for size, count := range current {
+		held += size * count
+	}
+	for size, count := range target {
+		want += size * count
+	}
+	diff := want - held
+
+	caps := utils.CopyMap(current)
+	var heldSizes []int
+	for size := range caps {
+		heldSizes = append(heldSizes, size)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(heldSizes)))
+	if diff > dpThreshold && len(packSizes) > 0 {
+		count := (diff - dpThreshold) / packSizes[0]
+		added[packSizes[0]] = count
+		diff -= count * packSizes[0]
+	}
+	for _, size := range heldSizes {
+		if -diff <= dpThreshold {
+			break
+		}
+		count := min(caps[size], (-diff-dpThreshold)/size)
+		if count > 0 {
+			caps[size] -= count
+			removed[size] = count
+			diff += count * size
+		}
+	}
+
+	removable := 0
+	for size, count := range caps {
+		removable += size * count
+	}
+	limit := min(removable, max(-diff, 0)+dpThreshold)
+	removals := newBoundedPackTable(limit, heldSizes, caps)
+	additions := newPackTable(max(diff+limit, 0), packSizes)
+
+	best, bestChanges, bestPacks := -1, math.MaxInt, math.MaxInt
+	for r := max(-diff, 0); r <= limit; r++ {
+		if !removals.reachable(r) || !additions.reachable(diff+r) {
+			continue
+		}
+		changes := removals.packs[r] + additions.packs[diff+r]
+		packs := additions.packs[diff+r] - removals.packs[r]
+		if changes < bestChanges || (changes == bestChanges && packs < bestPacks) {
+			best, bestChanges, bestPacks = r, changes, packs
+		}
+	}
+	if best == -1 {
+		return packDelta(current, target)
+	}
+	for size, count := range removals.distribution(best) {
+		removed[size] += count
+	}
+	for size, count := range additions.distribution(diff + best) {
+		added[size] += count
+	}
+	return added, removed
+}
+
+// packDelta returns the packs to add to and remove from current to turn it
+// into target.
+func packDelta(current, target map[int]int) (added, removed map[int]int) {
+	added, removed = map[int]int{}, map[int]int{}
+	for size, count := range target {
+		if count > current[size] {
+			added[size] = count - current[size]
+		}
+	}
+	for size, count := range current {
+		if count > target[size] {
+			removed[size] = count - target[size]
+		}
+	}
+	return added, removed
+}
+
+// applyDelta returns current with added packs added and removed packs taken
+// out.
+func applyDelta(current, added, removed map[int]int) map[int]int {
+	packs := utils.CopyMap(current)
+	for size, count := range removed {
+		packs[size] -= count
+		if packs[size] == 0 {
+			delete(packs, size)
+		}
+	}
+	for size, count := range added {
+		packs[size] += count
+	}
+	return packs
+}
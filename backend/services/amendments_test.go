@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepackDelta(t *testing.T) {
+	sizes := []int{1000, 500, 250}
+
+	t.Run("AddsOnePack", func(t *testing.T) {
+		// 501 -> 1001: a fresh calculation picks 1000+250, but adding a
+		// 500-pack to the stored 500+250 reaches the same 1250 items.
+		added, removed := repackDelta(map[int]int{500: 1, 250: 1}, map[int]int{1000: 1, 250: 1}, sizes)
+		assert.Equal(t, map[int]int{500: 1}, added)
+		assert.Empty(t, removed)
+	})
+
+	t.Run("RemovesOnePack", func(t *testing.T) {
+		added, removed := repackDelta(map[int]int{1000: 1, 500: 1, 250: 1}, map[int]int{1000: 1}, sizes)
+		assert.Empty(t, added)
+		assert.Equal(t, map[int]int{500: 1, 250: 1}, removed)
+	})
+
+	t.Run("SwapsWhenNeeded", func(t *testing.T) {
+		added, removed := repackDelta(map[int]int{250: 1}, map[int]int{500: 1}, []int{500, 250})
+		assert.Equal(t, map[int]int{250: 1}, added)
+		assert.Empty(t, removed)
+
+		added, removed = repackDelta(map[int]int{500: 1}, map[int]int{250: 1}, []int{500, 250})
+		assert.Equal(t, map[int]int{250: 1}, added)
+		assert.Equal(t, map[int]int{500: 1}, removed)
+	})
+
+	t.Run("TiesGoToFewerPacks", func(t *testing.T) {
+		// Adding 750 as 500+250 and swapping the 250 for a 1000 both take two
+		// changes; the swap leaves one pack instead of three.
+		added, removed := repackDelta(map[int]int{250: 1}, map[int]int{1000: 1}, sizes)
+		assert.Equal(t, map[int]int{1000: 1}, added)
+		assert.Equal(t, map[int]int{250: 1}, removed)
+	})
+
+	t.Run("LargeChange", func(t *testing.T) {
+		added, removed := repackDelta(map[int]int{250: 1}, map[int]int{1000: 1000, 250: 1}, sizes)
+		assert.Equal(t, map[int]int{1000: 1000}, added)
+		assert.Empty(t, removed)
+
+		added, removed = repackDelta(map[int]int{1000: 1000, 250: 1}, map[int]int{250: 1}, sizes)
+		assert.Empty(t, added)
+		assert.Equal(t, map[int]int{1000: 1000}, removed)
+	})
+}
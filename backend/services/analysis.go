@@ -0,0 +1,242 @@
+package services
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// MaxRangeQuantity is the largest order quantity accepted by range-based
+// operations. It matches the DP window so results agree with CalculatePacks.
+const MaxRangeQuantity = dpThreshold
+
+// MaxProposedSizes is the largest number of sizes a proposed pack set may hold.
+const MaxProposedSizes = 100
+
+// checkProposedSizes validates a proposed pack set. The tables built for it
+// grow with its sizes, so every size must be between 1 and MaxRangeQuantity
+// and at most MaxProposedSizes sizes may be proposed.
+func checkProposedSizes(packSizes []int) error {
+	if len(packSizes) > MaxProposedSizes {
+		return invalid(fmt.Sprintf("at most %d pack sizes may be proposed", MaxProposedSizes))
+	}
+	for _, size := range packSizes {
+		if size <= 0 || size > MaxRangeQuantity {
+			return invalid(fmt.Sprintf("invalid pack size: %d (must be between 1 and %d)", size, MaxRangeQuantity))
+		}
+	}
+	return nil
+}
+
+// rangeSolver answers CalculatePacks for every quantity up to a limit from one
+// DP pass: next[q] is the smallest reachable total >= q.
+type rangeSolver struct {
+	table *packTable
+	next  []int
+}
+
+// newRangeSolver tabulates all quantities up to to. packSizes must be sorted in
+// descending order. Any multiple of the smallest pack is reachable, so totals up
+// to to + smallest pack are enough.
+func newRangeSolver(to int, packSizes []int) *rangeSolver {
+	limit := to + packSizes[len(packSizes)-1]
+	table := newPackTable(limit, packSizes)
+	next := make([]int, limit+1)
+	next[limit] = limit
+	for s := limit - 1; s >= 0; s-- {
+		next[s] = next[s+1]
+		if table.reachable(s) {
+			next[s] = s
+		}
+	}
+	return &rangeSolver{table: table, next: next}
+}
+
+// solve returns the total and distribution CalculatePacks would choose for q.
+func (r *rangeSolver) solve(q int) (int, map[int]int) {
+	if q == 0 {
+		return 0, map[int]int{}
+	}
+	total := r.next[q]
+	return total, r.table.distribution(total)
+}
+
+// result wraps solve in a swagger.CalcResult.
+func (r *rangeSolver) result(q int) *swagger.CalcResult {
+	total, packs := r.solve(q)
+	packsUsed := utils.ConvertMapKeys(packs)
+	return &swagger.CalcResult{
+		ItemsOrdered:   utils.Ptr(q),
+		TotalItemsUsed: utils.Ptr(total),
+		PacksUsed:      &packsUsed,
+	}
+}
+
+// packSetVersion identifies a pack set by its distinct sizes in ascending order,
+// so equal sets share cached results however they were submitted.
+func packSetVersion(packSizes []int) string {
+	sorted := sortedDesc(packSizes)
+	parts := make([]string, 0, len(sorted))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if i < len(sorted)-1 && sorted[i] == sorted[i+1] {
+			continue
+		}
+		parts = append(parts, strconv.Itoa(sorted[i]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// AnalyzePackSizes reports which order quantities a pack set can fill exactly
+// and how much overage and how many packs the quantities in [from, to] need.
+// When proposed is empty the configured pack sizes are analysed. The latest
+// maxCachedAnalyses results are cached per pack-set version and range.
+func (ps *PackService) AnalyzePackSizes(proposed []int, from, to int) (*swagger.PackSetAnalysis, error) {
+	if from < 1 || to < from || to > MaxRangeQuantity {
+		return nil, invalid(fmt.Sprintf("invalid range %d-%d (must satisfy 1 <= from <= to <= %d)", from, to, MaxRangeQuantity))
+	}
+	if err := checkProposedSizes(proposed); err != nil {
+		return nil, err
+	}
+	packSizes := proposed
+	if len(packSizes) == 0 {
+		var err error
+		packSizes, _, err = ps.loadPackSet(time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pack sizes: %w", err)
+		}
+		if len(packSizes) == 0 {
+			return nil, errors.New("no pack sizes configured")
+		}
+	}
+
+	version := packSetVersion(packSizes)
+	key := fmt.Sprintf("%s|%d-%d", version, from, to)
+	if cached := ps.cachedAnalysis(key); cached != nil {
+		return cached, nil
+	}
+
+	analysis := analyzePackSet(sortedDesc(packSizes), from, to)
+	analysis.Version = version
+	ps.cacheAnalysis(key, analysis)
+	return analysis, nil
+}
+
+// maxCachedAnalyses is the number of pack-set analyses AnalyzePackSizes keeps;
+// the least recently used one is evicted first.
+const maxCachedAnalyses = 64
+
+// cachedAnalysis returns the cached analysis for key, or nil if there is none.
+func (ps *PackService) cachedAnalysis(key string) *swagger.PackSetAnalysis {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	element, ok := ps.analyses[key]
+	if !ok {
+		return nil
+	}
+	ps.analysisOrder.MoveToFront(element)
+	return element.Value.(analysisEntry).analysis
+}
+
+// cacheAnalysis caches analysis for key, evicting the least recently used
+// analysis when the cache is full.
+func (ps *PackService) cacheAnalysis(key string, analysis *swagger.PackSetAnalysis) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.analyses == nil {
+		ps.analyses = make(map[string]*list.Element)
+		ps.analysisOrder = list.New()
+	}
+	if element, ok := ps.analyses[key]; ok {
+		ps.analysisOrder.MoveToFront(element)
+		return
+	}
+	ps.analyses[key] = ps.analysisOrder.PushFront(analysisEntry{key: key, analysis: analysis})
+	if ps.analysisOrder.Len() > maxCachedAnalyses {
+		oldest := ps.analysisOrder.Back()
+		ps.analysisOrder.Remove(oldest)
+		delete(ps.analyses, oldest.Value.(analysisEntry).key)
+	}
+}
+
+// analysisEntry is an entry of the analysis cache.
+type analysisEntry struct {
+	key      string
+	analysis *swagger.PackSetAnalysis
+}
+
+// analyzePackSet computes a PackSetAnalysis. packSizes must be sorted in
+// descending order.
+func analyzePackSet(packSizes []int, from, to int) *swagger.PackSetAnalysis {
+	divisor := 0
+	for _, size := range packSizes {
+		divisor = gcd(divisor, size)
+	}
+	analysis := &swagger.PackSetAnalysis{
+		PackSizes: sortedAsc(packSizes),
+		Gcd:       divisor,
+		Range:     swagger.QuantityRange{From: from, To: to},
+	}
+
+	// The Frobenius number and the gap count are only finite when the sizes
+	// are coprime; otherwise every quantity that is not a multiple of the GCD
+	// is out of reach.
+	if divisor == 1 {
+		table := residueTable(packSizes)
+		smallest := len(table)
+		frobenius, gaps := -1, 0
+		for r, minTotal := range table {
+			frobenius = max(frobenius, minTotal-smallest)
+			gaps += (minTotal - r) / smallest
+		}
+		analysis.FrobeniusNumber = utils.Ptr(frobenius)
+		analysis.NonRepresentableCount = utils.Ptr(gaps)
+	}
+
+	solver := newRangeSolver(to, packSizes)
+	worstOverage := swagger.QuantityStat{}
+	worstPacks := swagger.QuantityStat{}
+	exact := 0
+	for q := from; q <= to; q++ {
+		total, packs := solver.solve(q)
+		if total == q {
+			exact++
+		}
+		if overage := total - q; q == from || overage > worstOverage.Value {
+			worstOverage = swagger.QuantityStat{ItemsOrdered: q, Value: overage}
+		}
+		count := 0
+		for _, n := range packs {
+			count += n
+		}
+		if q == from || count > worstPacks.Value {
+			worstPacks = swagger.QuantityStat{ItemsOrdered: q, Value: count}
+		}
+	}
+	analysis.ExactlyFillable = exact
+	analysis.WorstOverage = worstOverage
+	analysis.WorstPackCount = worstPacks
+	return analysis
+}
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// sortedAsc returns a copy of packSizes sorted in ascending order.
+func sortedAsc(packSizes []int) []int {
+	sorted := sortedDesc(packSizes)
+	for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+		sorted[i], sorted[j] = sorted[j], sorted[i]
+	}
+	return sorted
+}
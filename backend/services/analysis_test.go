@@ -0,0 +1,92 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestRangeSolverMatchesCalculatePacks(t *testing.T) {
+	sizes := []int{5000, 2000, 1000, 500, 250}
+	solver := newRangeSolver(12000, sizes)
+	for q := 1; q <= 12000; q += 37 {
+		want, err := calculatePacks(q, sortedDesc(sizes))
+		require.NoError(t, err)
+		got := solver.result(q)
+		assert.Equal(t, *want.TotalItemsUsed, *got.TotalItemsUsed, "q=%d", q)
+		assert.Equal(t, *want.PacksUsed, *got.PacksUsed, "q=%d", q)
+	}
+}
+
+func TestAnalyzePackSizes(t *testing.T) {
+	ps := NewPackService(&mockPackRepo{sizes: []int{250, 500, 1000}})
+
+	t.Run("CoprimeProposal", func(t *testing.T) {
+		analysis, err := ps.AnalyzePackSizes([]int{5, 3}, 1, 20)
+		require.NoError(t, err)
+		assert.Equal(t, "3,5", analysis.Version)
+		assert.Equal(t, 1, analysis.Gcd)
+		assert.Equal(t, 7, *analysis.FrobeniusNumber)
+		assert.Equal(t, 4, *analysis.NonRepresentableCount)
+		assert.Equal(t, 16, analysis.ExactlyFillable)
+		assert.Equal(t, 1, analysis.WorstOverage.ItemsOrdered)
+		assert.Equal(t, 2, analysis.WorstOverage.Value)
+	})
+
+	t.Run("ConfiguredSet", func(t *testing.T) {
+		analysis, err := ps.AnalyzePackSizes(nil, 1, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, 250, analysis.Gcd)
+		assert.Nil(t, analysis.FrobeniusNumber)
+		assert.Equal(t, 249, analysis.WorstOverage.Value)
+	})
+
+	t.Run("ActiveCatalogue", func(t *testing.T) {
+		ps := NewPackService(&mockCatalog{})
+		future := time.Now().Add(time.Hour)
+		for _, payload := range []swagger.PackPayload{{Size: 250}, {Size: 100, EffectiveFrom: &future}} {
+			_, err := ps.CreatePack(payload)
+			require.NoError(t, err)
+		}
+		analysis, err := ps.AnalyzePackSizes(nil, 1, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, []int{250}, analysis.PackSizes)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		first, err := ps.AnalyzePackSizes([]int{3, 5}, 1, 20)
+		require.NoError(t, err)
+		second, err := ps.AnalyzePackSizes([]int{5, 3, 3}, 1, 20)
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+	})
+
+	t.Run("CacheBounded", func(t *testing.T) {
+		first, err := ps.AnalyzePackSizes([]int{3, 5}, 1, 20)
+		require.NoError(t, err)
+		for to := 21; to <= 20+maxCachedAnalyses; to++ {
+			_, err := ps.AnalyzePackSizes([]int{3, 5}, 1, to)
+			require.NoError(t, err)
+		}
+		assert.Len(t, ps.analyses, maxCachedAnalyses)
+		again, err := ps.AnalyzePackSizes([]int{3, 5}, 1, 20)
+		require.NoError(t, err)
+		assert.NotSame(t, first, again)
+		assert.Equal(t, first, again)
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		_, err := ps.AnalyzePackSizes(nil, 10, 5)
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.AnalyzePackSizes([]int{250, -1}, 1, 10)
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.AnalyzePackSizes([]int{250, MaxRangeQuantity + 1}, 1, 10)
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.AnalyzePackSizes(make([]int, MaxProposedSizes+1), 1, 10)
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"math"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// chooseBreak decides whether to open a pack for a remainder of rest items,
+// given the total the whole-pack policy settled on (-1 when it found none).
+// Whole packs are worth opening when the break cost plus the handling cost of
+// the loose items is below the cost of that total: its overage at 1 per item,
+// or its shortfall at ShortPenalty per item. It returns the total of whole
+// packs to ship and the size of the pack to open, or ok false.
+//
+// The loose items always come from a single pack: with at least as many loose
+// items as a pack size, shipping that pack whole would be cheaper. The largest
+// whole totals are tried first, as they need the fewest loose items, and the
+// smallest pack that still has stock and holds the loose items is opened, so
+// that the least goes to the loose-item pool.
+func chooseBreak(rest, total int, sizes []int, caps map[int]int, table *packTable, opts CalcOptions) (whole, open int, ok bool) {
+	best := math.Inf(1)
+	switch penalty := opts.ShortPenalty; {
+	case total >= rest:
+		best = float64(total - rest)
+	case total >= 0:
+		if penalty == 0 {
+			penalty = 1
+		}
+		best = float64(rest-total) * penalty
+	}
+
+	for whole = rest - 1; whole >= 0; whole-- {
+		loose := rest - whole
+		if loose >= sizes[0] || opts.BreakCost+float64(loose)*opts.LooseItemCost >= best {
+			return 0, 0, false
+		}
+		if !table.reachable(whole) {
+			continue
+		}
+		packs := table.distribution(whole)
+		for i := len(sizes) - 1; i >= 0; i-- {
+			size := sizes[i]
+			if available, capped := caps[size]; size > loose && (!capped || available > packs[size]) {
+				return whole, size, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// brokenResult builds the result of shipping the whole packs of the total
+// whole, the peeled packs and the items of an opened pack of size open that
+// make up the rest of the order.
+func brokenResult(order, rest, whole, open int, peeled map[int]int, table *packTable) *swagger.CalcResult {
+	packsUsed := utils.ConvertMapKeys(withPeeled(table.distribution(whole), peeled))
+	loose := rest - whole
+	return &swagger.CalcResult{
+		ItemsOrdered:     utils.Ptr(order),
+		TotalItemsUsed:   utils.Ptr(order),
+		PacksUsed:        &packsUsed,
+		ItemsBackordered: utils.Ptr(0),
+		PacksOpened: &[]swagger.OpenedPack{{
+			Size:              open,
+			ItemsShipped:      loose,
+			ItemsToLooseStock: open - loose,
+		}},
+	}
+}
+
+// withOpenedRefs adds the catalogue IDs of the opened packs to result.
+func withOpenedRefs(result *swagger.CalcResult, bySize map[int]swagger.Pack) *swagger.CalcResult {
+	if bySize == nil || result == nil || result.PacksOpened == nil {
+		return result
+	}
+	for i, opened := range *result.PacksOpened {
+		if pack, ok := bySize[opened.Size]; ok {
+			(*result.PacksOpened)[i].PackId = utils.Ptr(pack.Id)
+		}
+	}
+	return result
+}
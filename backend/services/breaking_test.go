@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+func TestCalculatePacks_BreakPacks(t *testing.T) {
+	ps := NewPackService(&mockPackRepo{sizes: []int{250, 500}})
+
+	t.Run("OpeningBeatsOverage", func(t *testing.T) {
+		// 637 rounds up to 750, 113 items of overage. Opening a 250-pack for
+		// the last 137 items costs 10 + 137*0.5.
+		res, err := ps.CalculatePacksWithOptions(637, CalcOptions{BreakPacks: true, BreakCost: 10, LooseItemCost: 0.5})
+		require.NoError(t, err)
+		assert.Equal(t, 637, *res.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"500": 1}, *res.PacksUsed)
+		assert.Equal(t, []swagger.OpenedPack{{Size: 250, ItemsShipped: 137, ItemsToLooseStock: 113}}, *res.PacksOpened)
+	})
+
+	t.Run("OverageBeatsOpening", func(t *testing.T) {
+		res, err := ps.CalculatePacksWithOptions(637, CalcOptions{BreakPacks: true, BreakCost: 10, LooseItemCost: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 750, *res.TotalItemsUsed)
+		assert.Nil(t, res.PacksOpened)
+	})
+
+	t.Run("ExactMode", func(t *testing.T) {
+		// Exact mode has no overage to trade, so any pack that can be opened
+		// turns an impossible order into an exact one.
+		res, err := ps.CalculatePacksWithOptions(260, CalcOptions{Mode: ModeExact, BreakPacks: true, BreakCost: 50, LooseItemCost: 2})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"250": 1}, *res.PacksUsed)
+		assert.Equal(t, []swagger.OpenedPack{{Size: 250, ItemsShipped: 10, ItemsToLooseStock: 240}}, *res.PacksOpened)
+
+		res, err = ps.CalculatePacksWithOptions(750, CalcOptions{Mode: ModeExact, BreakPacks: true})
+		require.NoError(t, err)
+		assert.Nil(t, res.PacksOpened)
+	})
+
+	t.Run("OpenedPackNeedsStock", func(t *testing.T) {
+		// Without a spare 250-pack, the loose items come from a 500-pack.
+		res, err := ps.CalculatePacksWithOptions(260, CalcOptions{BreakPacks: true, Availability: map[int]int{250: 1}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"250": 1}, *res.PacksUsed)
+		assert.Equal(t, []swagger.OpenedPack{{Size: 500, ItemsShipped: 10, ItemsToLooseStock: 490}}, *res.PacksOpened)
+	})
+
+	t.Run("LargeOrder", func(t *testing.T) {
+		res, err := ps.CalculatePacksWithOptions(1000001, CalcOptions{BreakPacks: true, BreakCost: 5})
+		require.NoError(t, err)
+		assert.Equal(t, 1000001, *res.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"500": 2000}, *res.PacksUsed)
+		assert.Equal(t, []swagger.OpenedPack{{Size: 250, ItemsShipped: 1, ItemsToLooseStock: 249}}, *res.PacksOpened)
+	})
+
+	t.Run("RejectedWithPriceObjective", func(t *testing.T) {
+		_, err := ps.CalculatePacksWithOptions(637, CalcOptions{BreakPacks: true, Objective: ObjectivePrice})
+		var validation *ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// errNoStock is returned when the availability caps leave no pack to ship.
+var errNoStock = errors.New("no packs available to ship")
+
+// calculatePacksConstrained handles the calculations that need more than the
+// plain round-up solvers: availability caps, exact fills under caps, the
+// backorder policy, pack breaking and pack count caps. packSizes must be sorted
+// in descending order.
+//
+// Orders above dpThreshold are first reduced with the largest pack sizes (as
+// far as their caps allow) so that the remainder fits the DP window. Every total
+// up to remainder + largest pack is then tabulated, which is enough to find both
+// the best overfill and the best short shipment.
+func calculatePacksConstrained(order int, packSizes []int, opts CalcOptions) (*swagger.CalcResult, error) {
+	caps := utils.CopyMap(opts.Availability)
+	var sizes []int
+	capacity, unlimited := 0, false
+	for _, size := range packSizes {
+		available, capped := caps[size]
+		switch {
+		case !capped:
+			unlimited = true
+		case available <= 0:
+			continue
+		default:
+			capacity += available * size
+		}
+		sizes = append(sizes, size)
+	}
+	if len(sizes) == 0 {
+		return nil, errNoStock
+	}
+
+	// Peel packs off large orders, biggest first, until the remainder fits the
+	// DP window or the caps run out. Under a pack cap only the largest size is
+	// peeled, as smaller peeled packs could not be traded for fewer large ones.
+	peeled := map[int]int{}
+	peeledItems := 0
+	rest := order
+	for _, size := range sizes {
+		if rest <= dpThreshold || (opts.MaxPacks > 0 && size != sizes[0]) {
+			break
+		}
+		count := (rest - dpThreshold) / size
+		if available, capped := caps[size]; capped {
+			count = min(count, available)
+			caps[size] -= count
+		}
+		if count > 0 {
+			peeled[size] = count
+			peeledItems += count * size
+			rest -= count * size
+		}
+	}
+
+	limit := rest + sizes[0]
+	if !unlimited {
+		limit = min(limit, capacity-peeledItems)
+	}
+	var table *packTable
+	if len(caps) > 0 {
+		table = newBoundedPackTable(limit, sizes, caps)
+	} else {
+		table = newPackTable(limit, sizes)
+	}
+
+	var total int
+	switch opts.Mode {
+	case ModeExact:
+		total = -1
+		if table.reachable(rest) {
+			total = rest
+		}
+	case ModeBackorder:
+		total = chooseBackorderTotal(rest, table, opts)
+	default:
+		// A pack cap may cost overage; if nothing meets it, the usual total
+		// is returned for the caller to report.
+		total = -1
+		if opts.MaxPacks > 0 {
+			total = firstWithinPacks(rest, opts.MaxPacks-peeledPacks(peeled), table)
+		}
+		if total == -1 {
+			total = firstReachableFrom(rest, table)
+		}
+		if total == -1 {
+			total = lastReachableUpTo(rest, table)
+		}
+	}
+	if opts.BreakPacks {
+		if whole, open, ok := chooseBreak(rest, total, sizes, caps, table, opts); ok {
+			return brokenResult(order, rest, whole, open, peeled, table), nil
+		}
+	}
+	if opts.Mode == ModeExact && total == -1 {
+		return nil, constrainedFillError(order, rest, peeled, peeledItems, table)
+	}
+	if total <= 0 && peeledItems == 0 {
+		return nil, errNoStock
+	}
+
+	packs := withPeeled(table.distribution(max(total, 0)), peeled)
+	shipped := max(total, 0) + peeledItems
+	packsUsed := utils.ConvertMapKeys(packs)
+	return &swagger.CalcResult{
+		ItemsOrdered:     utils.Ptr(order),
+		TotalItemsUsed:   utils.Ptr(shipped),
+		PacksUsed:        &packsUsed,
+		ItemsBackordered: utils.Ptr(max(order-shipped, 0)),
+	}, nil
+}
+
+// chooseBackorderTotal picks the total to ship for the backorder policy. The
+// cheapest of the smallest overfill and every allowed short shipment wins, where
+// overage costs 1 per item and a shortfall costs ShortPenalty per item. Ties go
+// to the full shipment, then to fewer packs. When the stock cannot cover the
+// order, the largest shippable total is returned regardless of the tolerance.
+func chooseBackorderTotal(order int, table *packTable, opts CalcOptions) int {
+	penalty := opts.ShortPenalty
+	if penalty == 0 {
+		penalty = 1
+	}
+
+	best := firstReachableFrom(order, table)
+	if best == -1 {
+		return lastReachableUpTo(order, table)
+	}
+	bestCost := float64(best - order)
+	for s := order - 1; s > 0; s-- {
+		short := order - s
+		if opts.ShortTolerance > 0 && short > opts.ShortTolerance {
+			break
+		}
+		if float64(short)*penalty >= bestCost {
+			break
+		}
+		if !table.reachable(s) {
+			continue
+		}
+		cost := float64(short) * penalty
+		if cost < bestCost || (cost == bestCost && table.packs[s] < table.packs[best]) {
+			best, bestCost = s, cost
+		}
+	}
+	return best
+}
+
+// constrainedFillError builds the exact-fill error for a capped calculation.
+// The nearest totals are searched in the table only, offset by the packs that
+// were peeled off before tabulating.
+func constrainedFillError(order, rest int, peeled map[int]int, peeledItems int, table *packTable) error {
+	fillErr := &ExactFillError{Order: order}
+	if below := lastReachableUpTo(rest-1, table); below >= 0 && below+peeledItems > 0 {
+		fillErr.Below = exactResult(below+peeledItems, withPeeled(table.distribution(below), peeled))
+	}
+	if above := firstReachableFrom(rest+1, table); above > 0 {
+		fillErr.Above = exactResult(above+peeledItems, withPeeled(table.distribution(above), peeled))
+	}
+	return fillErr
+}
+
+// withPeeled adds the peeled pack counts to packs and returns it.
+func withPeeled(packs map[int]int, peeled map[int]int) map[int]int {
+	for size, count := range peeled {
+		packs[size] += count
+	}
+	return packs
+}
+
+// firstReachableFrom returns the smallest reachable total >= from, or -1.
+func firstReachableFrom(from int, table *packTable) int {
+	for s := max(from, 0); s < len(table.packs); s++ {
+		if table.reachable(s) {
+			return s
+		}
+	}
+	return -1
+}
+
+// firstWithinPacks returns the smallest total >= from that at most maxPacks
+// packs add up to, or -1. Totals beyond the table are never needed: dropping a
+// pack from a larger total leaves fewer packs and still covers from.
+func firstWithinPacks(from, maxPacks int, table *packTable) int {
+	for s := max(from, 0); s < len(table.packs); s++ {
+		if table.reachable(s) && table.packs[s] <= maxPacks {
+			return s
+		}
+	}
+	return -1
+}
+
+// peeledPacks counts the packs peeled off before tabulation.
+func peeledPacks(peeled map[int]int) int {
+	count := 0
+	for _, n := range peeled {
+		count += n
+	}
+	return count
+}
+
+// lastReachableUpTo returns the largest reachable total <= to, or -1.
+func lastReachableUpTo(to int, table *packTable) int {
+	for s := min(to, len(table.packs)-1); s >= 0; s-- {
+		if table.reachable(s) {
+			return s
+		}
+	}
+	return -1
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculatePacksConstrained(t *testing.T) {
+	sizes := []int{5000, 2000, 1000, 500, 250}
+
+	t.Run("AvailabilityCaps", func(t *testing.T) {
+		// Without 250-packs, 501 is best served by a single 1000-pack.
+		res, err := calculatePacksConstrained(501, sizes, CalcOptions{Availability: map[int]int{250: 0}})
+		require.NoError(t, err)
+		assert.Equal(t, 1000, *res.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"1000": 1}, *res.PacksUsed)
+		assert.Equal(t, 0, *res.ItemsBackordered)
+	})
+
+	t.Run("ShortageBecomesPartialShipment", func(t *testing.T) {
+		caps := map[int]int{5000: 0, 2000: 0, 1000: 0, 500: 1, 250: 1}
+		res, err := calculatePacksConstrained(1000, sizes, CalcOptions{Availability: caps})
+		require.NoError(t, err)
+		assert.Equal(t, 750, *res.TotalItemsUsed)
+		assert.Equal(t, 250, *res.ItemsBackordered)
+	})
+
+	t.Run("NoStock", func(t *testing.T) {
+		caps := map[int]int{5000: 0, 2000: 0, 1000: 0, 500: 0, 250: 0}
+		_, err := calculatePacksConstrained(1000, sizes, CalcOptions{Availability: caps})
+		assert.ErrorIs(t, err, errNoStock)
+	})
+
+	t.Run("BackorderCheaperThanOverage", func(t *testing.T) {
+		res, err := calculatePacksConstrained(501, sizes, CalcOptions{Mode: ModeBackorder})
+		require.NoError(t, err)
+		assert.Equal(t, 500, *res.TotalItemsUsed)
+		assert.Equal(t, 1, *res.ItemsBackordered)
+	})
+
+	t.Run("BackorderOutsideTolerance", func(t *testing.T) {
+		res, err := calculatePacksConstrained(740, sizes, CalcOptions{Mode: ModeBackorder, ShortTolerance: 100})
+		require.NoError(t, err)
+		assert.Equal(t, 750, *res.TotalItemsUsed)
+		assert.Equal(t, 0, *res.ItemsBackordered)
+	})
+
+	t.Run("BackorderPenalty", func(t *testing.T) {
+		res, err := calculatePacksConstrained(501, sizes, CalcOptions{Mode: ModeBackorder, ShortPenalty: 300})
+		require.NoError(t, err)
+		assert.Equal(t, 750, *res.TotalItemsUsed)
+	})
+
+	t.Run("LargeOrderWithCaps", func(t *testing.T) {
+		res, err := calculatePacksConstrained(1000251, sizes, CalcOptions{Availability: map[int]int{5000: 100}})
+		require.NoError(t, err)
+		assert.Equal(t, 1000500, *res.TotalItemsUsed)
+		assert.LessOrEqual(t, (*res.PacksUsed)["5000"], 100)
+	})
+
+	t.Run("ExactUnderCaps", func(t *testing.T) {
+		_, err := calculatePacksConstrained(750, sizes, CalcOptions{Mode: ModeExact, Availability: map[int]int{250: 0}})
+		var fillErr *ExactFillError
+		require.True(t, errors.As(err, &fillErr))
+		assert.Equal(t, 500, *fillErr.Below.TotalItemsUsed)
+		assert.Equal(t, 1000, *fillErr.Above.TotalItemsUsed)
+	})
+}
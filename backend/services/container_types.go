@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+
+	"ship_line/swagger"
+)
+
+// ErrContainerTypeNotFound is returned when a container type ID does not exist.
+var ErrContainerTypeNotFound = errors.New("container type not found")
+
+// ContainerTypeRepository is implemented by repositories that can also persist
+// container types.
+type ContainerTypeRepository interface {
+	ListContainerTypes() ([]swagger.ContainerType, error)
+	GetContainerType(id string) (*swagger.ContainerType, error)
+	CreateContainerType(containerType *swagger.ContainerType) error
+	UpdateContainerType(containerType *swagger.ContainerType) error
+	DeleteContainerType(id string) error
+}
+
+// containerTypeRepo returns the container type storage of the configured repository.
+func (ps *PackService) containerTypeRepo() (ContainerTypeRepository, error) {
+	repo, ok := ps.repo.(ContainerTypeRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// containerTypes returns the CRUD methods of the stored container types.
+func (ps *PackService) containerTypes() records[swagger.ContainerType, swagger.ContainerTypePayload] {
+	repo, err := ps.containerTypeRepo()
+	if err != nil {
+		return records[swagger.ContainerType, swagger.ContainerTypePayload]{err: err}
+	}
+	return records[swagger.ContainerType, swagger.ContainerTypePayload]{
+		noun:        "container type",
+		plural:      "container types",
+		notFound:    ErrContainerTypeNotFound,
+		fromPayload: containerTypeFromPayload,
+		id:          func(containerType *swagger.ContainerType) *string { return &containerType.Id },
+		list:        repo.ListContainerTypes,
+		get:         repo.GetContainerType,
+		create:      repo.CreateContainerType,
+		update:      repo.UpdateContainerType,
+		delete:      repo.DeleteContainerType,
+	}
+}
+
+// ListContainerTypes retrieves every container type.
+func (ps *PackService) ListContainerTypes() ([]swagger.ContainerType, error) {
+	return ps.containerTypes().listAll()
+}
+
+// GetContainerType retrieves a container type by ID.
+func (ps *PackService) GetContainerType(id string) (*swagger.ContainerType, error) {
+	return ps.containerTypes().getOne(id)
+}
+
+// CreateContainerType validates payload and stores it as a new container type.
+func (ps *PackService) CreateContainerType(payload swagger.ContainerTypePayload) (*swagger.ContainerType, error) {
+	return ps.containerTypes().createFrom(payload)
+}
+
+// UpdateContainerType replaces the container type with the given ID by payload.
+func (ps *PackService) UpdateContainerType(id string, payload swagger.ContainerTypePayload) (*swagger.ContainerType, error) {
+	return ps.containerTypes().updateFrom(id, payload)
+}
+
+// DeleteContainerType removes a container type.
+func (ps *PackService) DeleteContainerType(id string) error {
+	return ps.containerTypes().remove(id)
+}
+
+// containerTypeFromPayload validates payload and converts it into a
+// ContainerType without an ID.
+func containerTypeFromPayload(payload swagger.ContainerTypePayload) (*swagger.ContainerType, error) {
+	if payload.Name == "" {
+		return nil, invalid("name is required")
+	}
+	if payload.MaxItems == nil && payload.MaxWeight == nil && payload.MaxVolume == nil && payload.Dimensions == nil {
+		return nil, invalid("at least one of maxItems, maxWeight, maxVolume and dimensions is required")
+	}
+	if (payload.MaxItems != nil && *payload.MaxItems <= 0) ||
+		(payload.MaxWeight != nil && *payload.MaxWeight <= 0) ||
+		(payload.MaxVolume != nil && *payload.MaxVolume <= 0) {
+		return nil, invalid("maxItems, maxWeight and maxVolume must be positive")
+	}
+	if d := payload.Dimensions; d != nil && (d.Length <= 0 || d.Width <= 0 || d.Height <= 0) {
+		return nil, invalid("dimensions must be positive")
+	}
+	if payload.MaxFillRatio != nil && (*payload.MaxFillRatio <= 0 || *payload.MaxFillRatio > 1) {
+		return nil, invalid("maxFillRatio must be greater than 0 and at most 1")
+	}
+	if payload.TareWeight != nil && (*payload.TareWeight < 0 ||
+		(payload.MaxWeight != nil && *payload.TareWeight >= *payload.MaxWeight)) {
+		return nil, invalid("tareWeight must not be negative and must be below maxWeight")
+	}
+	if payload.Cost != nil && *payload.Cost < 0 {
+		return nil, invalid("cost must not be negative")
+	}
+	return &swagger.ContainerType{
+		Name:         payload.Name,
+		MaxItems:     payload.MaxItems,
+		MaxWeight:    payload.MaxWeight,
+		MaxVolume:    payload.MaxVolume,
+		Dimensions:   payload.Dimensions,
+		MaxFillRatio: payload.MaxFillRatio,
+		TareWeight:   payload.TareWeight,
+		Cost:         payload.Cost,
+	}, nil
+}
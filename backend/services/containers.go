@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// MaxContainers is the largest number of containers a distribution may be
+// split into.
+const MaxContainers = 10000
+
+// ErrNoContainer is returned when a pack of the distribution does not fit into
+// any of the stored container types.
+var ErrNoContainer = errors.New("no container type can hold this pack")
+
+// container is a container being filled by planContainers.
+type container struct {
+	kind   swagger.ContainerType
+	packs  map[int]int
+	items  int
+	weight float64
+	volume float64
+}
+
+// planContainers assigns the packs of result to containers of the stored
+// container types and returns a manifest per container.
+//
+// Packs are placed largest size first into the first open container with room
+// (first-fit decreasing). Identical packs are placed in bulk, so the work grows
+// with the number of containers rather than packs. A new container is opened
+// with the type that holds the most packs of the current size, and once all
+// packs are placed each container is switched to the cheapest type its
+// contents fit.
+func (ps *PackService) planContainers(result *swagger.CalcResult, bySize map[int]swagger.Pack) ([]swagger.ContainerManifest, error) {
+	repo, err := ps.containerTypeRepo()
+	if err != nil {
+		return nil, err
+	}
+	kinds, err := repo.ListContainerTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list container types: %w", err)
+	}
+	if len(kinds) == 0 {
+		return nil, invalid("no container types configured")
+	}
+	packs, err := utils.ParseMapKeys(*result.PacksUsed)
+	if err != nil {
+		return nil, err
+	}
+	sizes := make([]int, 0, len(packs))
+	for size, count := range packs {
+		if count > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+
+	var containers []*container
+	for _, size := range sizes {
+		pack, known := bySize[size]
+		if !known {
+			pack = swagger.Pack{Size: size}
+		}
+		remaining := packs[size]
+		for _, c := range containers {
+			if remaining == 0 {
+				break
+			}
+			n := min(remaining, c.room(c.kind, pack))
+			c.add(pack, n)
+			remaining -= n
+		}
+		for remaining > 0 {
+			empty := &container{packs: make(map[int]int)}
+			best, room := -1, 0
+			for i, kind := range kinds {
+				if r := empty.room(kind, pack); r > room || (r == room && r > 0 && cheaper(kind, kinds[best])) {
+					best, room = i, r
+				}
+			}
+			if best == -1 {
+				return nil, fmt.Errorf("%w: pack size %d", ErrNoContainer, size)
+			}
+			if len(containers) == MaxContainers {
+				return nil, invalid(fmt.Sprintf("the distribution needs more than %d containers", MaxContainers))
+			}
+			empty.kind = kinds[best]
+			n := min(remaining, room)
+			empty.add(pack, n)
+			remaining -= n
+			containers = append(containers, empty)
+		}
+	}
+
+	manifests := make([]swagger.ContainerManifest, 0, len(containers))
+	for _, c := range containers {
+		for _, kind := range kinds {
+			if cheaper(kind, c.kind) && c.holds(kind, bySize) {
+				c.kind = kind
+			}
+		}
+		manifests = append(manifests, c.manifest(bySize))
+	}
+	return manifests, nil
+}
+
+// room returns how many more packs of pack fit into c if it were of the given
+// type. Limits on weight or volume leave no room for packs without that data.
+func (c *container) room(kind swagger.ContainerType, pack swagger.Pack) int {
+	room := math.MaxInt
+	if kind.MaxItems != nil {
+		room = min(room, (*kind.MaxItems-c.items)/pack.Size)
+	}
+	if kind.MaxWeight != nil {
+		if pack.Weight == nil {
+			return 0
+		}
+		if *pack.Weight > 0 {
+			room = min(room, int((payloadLimit(kind)-c.weight+limitTolerance) / *pack.Weight))
+		}
+	}
+	if limit, ok := volumeLimit(kind); ok {
+		volume := packVolume(pack)
+		if volume == 0 {
+			return 0
+		}
+		room = min(room, int((limit-c.volume+limitTolerance)/volume))
+	}
+	return max(room, 0)
+}
+
+// holds reports whether the contents of c fit into a container of the given type.
+func (c *container) holds(kind swagger.ContainerType, bySize map[int]swagger.Pack) bool {
+	if kind.MaxItems != nil && c.items > *kind.MaxItems {
+		return false
+	}
+	limit, limited := volumeLimit(kind)
+	for size := range c.packs {
+		pack, ok := bySize[size]
+		if (kind.MaxWeight != nil && (!ok || pack.Weight == nil)) ||
+			(limited && (!ok || pack.Dimensions == nil)) {
+			return false
+		}
+	}
+	return (kind.MaxWeight == nil || c.weight <= payloadLimit(kind)+limitTolerance) &&
+		(!limited || c.volume <= limit+limitTolerance)
+}
+
+// volumeLimit returns the largest volume of packs a container of the given
+// type holds: MaxVolume or, for types with dimensions, the share of the inner
+// volume allowed by MaxFillRatio, whichever is smaller. ok is false when the
+// type has no volume limit.
+func volumeLimit(kind swagger.ContainerType) (limit float64, ok bool) {
+	limit = math.Inf(1)
+	if kind.MaxVolume != nil {
+		limit, ok = *kind.MaxVolume, true
+	}
+	if d := kind.Dimensions; d != nil {
+		limit, ok = min(limit, d.Length*d.Width*d.Height*fillRatio(kind)), true
+	}
+	return limit, ok
+}
+
+// payloadLimit returns the largest weight of packs a container of the given
+// type holds: MaxWeight less the tare weight. The type must have a MaxWeight.
+func payloadLimit(kind swagger.ContainerType) float64 {
+	if kind.TareWeight == nil {
+		return *kind.MaxWeight
+	}
+	return *kind.MaxWeight - *kind.TareWeight
+}
+
+// fillRatio returns the MaxFillRatio of a container type, 1 if unset.
+func fillRatio(kind swagger.ContainerType) float64 {
+	if kind.MaxFillRatio == nil {
+		return 1
+	}
+	return *kind.MaxFillRatio
+}
+
+// add puts count packs of pack into c.
+func (c *container) add(pack swagger.Pack, count int) {
+	if count == 0 {
+		return
+	}
+	c.packs[pack.Size] += count
+	c.items += pack.Size * count
+	if pack.Weight != nil {
+		c.weight += *pack.Weight * float64(count)
+	}
+	c.volume += packVolume(pack) * float64(count)
+}
+
+// manifest describes the contents of c. Weight and volume are only reported
+// when every pack in the container has the data.
+func (c *container) manifest(bySize map[int]swagger.Pack) swagger.ContainerManifest {
+	name := c.kind.Name
+	manifest := swagger.ContainerManifest{
+		ContainerTypeId: c.kind.Id,
+		Name:            &name,
+		Items:           c.items,
+		PacksUsed:       utils.ConvertMapKeys(c.packs),
+	}
+	weighed, measured := true, true
+	for size := range c.packs {
+		pack, ok := bySize[size]
+		weighed = weighed && ok && pack.Weight != nil
+		measured = measured && ok && pack.Dimensions != nil
+	}
+	if weighed {
+		manifest.Weight = utils.Ptr(math.Round(c.weight*1000) / 1000)
+	}
+	if measured {
+		manifest.Volume = utils.Ptr(math.Round(c.volume*1000) / 1000)
+	}
+	return manifest
+}
+
+// cheaper reports whether container type a costs less than b. Types without a
+// cost are free.
+func cheaper(a, b swagger.ContainerType) bool {
+	cost := func(kind swagger.ContainerType) float64 {
+		if kind.Cost == nil {
+			return 0
+		}
+		return *kind.Cost
+	}
+	return cost(a) < cost(b)
+}
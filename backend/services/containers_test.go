@@ -0,0 +1,101 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// mockContainerCatalog is a mockCatalog that also stores container types.
+type mockContainerCatalog struct {
+	mockCatalog
+	types mockRecords[swagger.ContainerType]
+}
+
+func (m *mockContainerCatalog) ListContainerTypes() ([]swagger.ContainerType, error) {
+	return m.types.list()
+}
+
+func (m *mockContainerCatalog) GetContainerType(id string) (*swagger.ContainerType, error) {
+	return m.types.get(id)
+}
+
+func (m *mockContainerCatalog) CreateContainerType(containerType *swagger.ContainerType) error {
+	return m.types.create(containerType, &containerType.Id)
+}
+
+func (m *mockContainerCatalog) UpdateContainerType(containerType *swagger.ContainerType) error {
+	return m.types.update(containerType.Id, containerType)
+}
+
+func (m *mockContainerCatalog) DeleteContainerType(id string) error {
+	return m.types.delete(id)
+}
+
+func TestPlanContainers(t *testing.T) {
+	repo := &mockContainerCatalog{}
+	ps := NewPackService(repo)
+	for _, payload := range []swagger.PackPayload{physicalPack(250, 3, 20), physicalPack(500, 5, 25)} {
+		_, err := ps.CreatePack(payload)
+		require.NoError(t, err)
+	}
+
+	t.Run("NoContainerTypes", func(t *testing.T) {
+		_, err := ps.CalculatePacksWithOptions(2250, CalcOptions{Containers: true})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	for _, payload := range []swagger.ContainerTypePayload{
+		{Name: "Box", MaxItems: utils.Ptr(1000), Cost: utils.Ptr(2.0)},
+		{Name: "Pallet", MaxWeight: utils.Ptr(20.0), Cost: utils.Ptr(10.0)},
+	} {
+		_, err := ps.CreateContainerType(payload)
+		require.NoError(t, err)
+	}
+
+	t.Run("FirstFitDecreasing", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(2250, CalcOptions{Containers: true})
+		require.NoError(t, err)
+		require.NotNil(t, result.Containers)
+		containers := *result.Containers
+		require.Len(t, containers, 2)
+
+		// Four 500-packs fill a pallet to its weight limit; the remaining
+		// 250-pack moves to the cheaper box.
+		assert.Equal(t, "2", containers[0].ContainerTypeId)
+		assert.Equal(t, map[string]int{"500": 4}, containers[0].PacksUsed)
+		assert.Equal(t, 2000, containers[0].Items)
+		assert.Equal(t, 20.0, *containers[0].Weight)
+		assert.Equal(t, 62500.0, *containers[0].Volume)
+		assert.Equal(t, "1", containers[1].ContainerTypeId)
+		assert.Equal(t, "Box", *containers[1].Name)
+		assert.Equal(t, map[string]int{"250": 1}, containers[1].PacksUsed)
+	})
+
+	t.Run("PackFitsNoContainer", func(t *testing.T) {
+		_, err := ps.UpdateContainerType("1", swagger.ContainerTypePayload{Name: "Box", MaxItems: utils.Ptr(400)})
+		require.NoError(t, err)
+		_, err = ps.UpdateContainerType("2", swagger.ContainerTypePayload{Name: "Pallet", MaxWeight: utils.Ptr(4.0)})
+		require.NoError(t, err)
+		_, err = ps.CalculatePacksWithOptions(2250, CalcOptions{Containers: true})
+		assert.ErrorIs(t, err, ErrNoContainer)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		for _, payload := range []swagger.ContainerTypePayload{
+			{Name: "Box"},
+			{MaxItems: utils.Ptr(10)},
+			{Name: "Box", MaxItems: utils.Ptr(0)},
+			{Name: "Box", MaxVolume: utils.Ptr(1000.0), Cost: utils.Ptr(-1.0)},
+		} {
+			_, err := ps.CreateContainerType(payload)
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		}
+		assert.ErrorIs(t, ps.DeleteContainerType("9"), ErrContainerTypeNotFound)
+	})
+}
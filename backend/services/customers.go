@@ -0,0 +1,104 @@
+package services
+
+import (
+	"errors"
+
+	"ship_line/swagger"
+)
+
+// ErrCustomerNotFound is returned when a customer ID does not exist.
+var ErrCustomerNotFound = errors.New("customer not found")
+
+// CustomerRepository is implemented by repositories that can also persist
+// customers and the packing policies of their contracts.
+type CustomerRepository interface {
+	ListCustomers() ([]swagger.Customer, error)
+	GetCustomer(id string) (*swagger.Customer, error)
+	CreateCustomer(customer *swagger.Customer) error
+	UpdateCustomer(customer *swagger.Customer) error
+	DeleteCustomer(id string) error
+}
+
+// customerRepo returns the customer storage of the configured repository.
+func (ps *PackService) customerRepo() (CustomerRepository, error) {
+	repo, ok := ps.repo.(CustomerRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// customers returns the CRUD methods of the stored customers.
+func (ps *PackService) customers() records[swagger.Customer, swagger.CustomerPayload] {
+	repo, err := ps.customerRepo()
+	if err != nil {
+		return records[swagger.Customer, swagger.CustomerPayload]{err: err}
+	}
+	return records[swagger.Customer, swagger.CustomerPayload]{
+		noun:        "customer",
+		plural:      "customers",
+		notFound:    ErrCustomerNotFound,
+		fromPayload: customerFromPayload,
+		id:          func(customer *swagger.Customer) *string { return &customer.Id },
+		list:        repo.ListCustomers,
+		get:         repo.GetCustomer,
+		create:      repo.CreateCustomer,
+		update:      repo.UpdateCustomer,
+		delete:      repo.DeleteCustomer,
+	}
+}
+
+// ListCustomers retrieves every customer.
+func (ps *PackService) ListCustomers() ([]swagger.Customer, error) {
+	return ps.customers().listAll()
+}
+
+// GetCustomer retrieves a customer by ID.
+func (ps *PackService) GetCustomer(id string) (*swagger.Customer, error) {
+	return ps.customers().getOne(id)
+}
+
+// CreateCustomer validates payload and stores it as a new customer.
+func (ps *PackService) CreateCustomer(payload swagger.CustomerPayload) (*swagger.Customer, error) {
+	return ps.customers().createFrom(payload)
+}
+
+// UpdateCustomer replaces the customer with the given ID by payload.
+func (ps *PackService) UpdateCustomer(id string, payload swagger.CustomerPayload) (*swagger.Customer, error) {
+	return ps.customers().updateFrom(id, payload)
+}
+
+// DeleteCustomer removes a customer.
+func (ps *PackService) DeleteCustomer(id string) error {
+	return ps.customers().remove(id)
+}
+
+// customerFromPayload validates payload and converts it into a Customer without
+// an ID.
+func customerFromPayload(payload swagger.CustomerPayload) (*swagger.Customer, error) {
+	if payload.Name == "" {
+		return nil, invalid("name is required")
+	}
+	customer := &swagger.Customer{Name: payload.Name}
+	if payload.Policy != nil {
+		policy, err := validPolicy(*payload.Policy)
+		if err != nil {
+			return nil, err
+		}
+		customer.Policy = policy
+	}
+	return customer, nil
+}
+
+// withCustomerPolicy returns opts constrained by the packing policy of the
+// customer opts.Customer.
+func (ps *PackService) withCustomerPolicy(opts CalcOptions) (CalcOptions, error) {
+	customer, err := ps.GetCustomer(opts.Customer)
+	if err != nil {
+		return opts, err
+	}
+	if customer.Policy != nil {
+		opts = withPolicy(opts, *customer.Policy)
+	}
+	return opts, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// mockCustomerRepo is a mockPackRepo that also stores customers.
+type mockCustomerRepo struct {
+	mockPackRepo
+	customers mockRecords[swagger.Customer]
+}
+
+func (m *mockCustomerRepo) ListCustomers() ([]swagger.Customer, error) {
+	return m.customers.list()
+}
+
+func (m *mockCustomerRepo) GetCustomer(id string) (*swagger.Customer, error) {
+	return m.customers.get(id)
+}
+
+func (m *mockCustomerRepo) CreateCustomer(customer *swagger.Customer) error {
+	return m.customers.create(customer, &customer.Id)
+}
+
+func (m *mockCustomerRepo) UpdateCustomer(customer *swagger.Customer) error {
+	return m.customers.update(customer.Id, customer)
+}
+
+func (m *mockCustomerRepo) DeleteCustomer(id string) error {
+	return m.customers.delete(id)
+}
+
+func TestCalculateWithCustomerPolicy(t *testing.T) {
+	ps := NewPackService(&mockCustomerRepo{mockPackRepo: mockPackRepo{sizes: []int{250, 500, 1000, 2000, 5000}}})
+	customer := func(policy swagger.PackingPolicy) string {
+		c, err := ps.CreateCustomer(swagger.CustomerPayload{Name: "Acme", Policy: &policy})
+		require.NoError(t, err)
+		return c.Id
+	}
+
+	tests := []struct {
+		name     string
+		policy   swagger.PackingPolicy
+		order    int
+		expected map[string]int
+	}{
+		{"NoPolicy", swagger.PackingPolicy{}, 12001, map[string]int{"5000": 2, "2000": 1, "250": 1}},
+		{"ForbiddenSize", swagger.PackingPolicy{ForbiddenPackSizes: &[]int{250}}, 12001, map[string]int{"5000": 2, "2000": 1, "500": 1}},
+		{"MaxPacks", swagger.PackingPolicy{MaxPacks: utils.Ptr(3)}, 12001, map[string]int{"5000": 3}},
+		{"MaxPacksLargeOrder", swagger.PackingPolicy{MaxPacks: utils.Ptr(41)}, 203001, map[string]int{"5000": 41}},
+		{"OverageWithinLimit", swagger.PackingPolicy{MaxOveragePercent: utils.Ptr(5.0)}, 12001, map[string]int{"5000": 2, "2000": 1, "250": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ps.CalculatePacksWithOptions(tt.order, CalcOptions{Customer: customer(tt.policy)})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *res.PacksUsed)
+		})
+	}
+
+	t.Run("Violations", func(t *testing.T) {
+		_, err := ps.CalculatePacksWithOptions(12001, CalcOptions{Customer: customer(swagger.PackingPolicy{MaxOveragePercent: utils.Ptr(1.0)})})
+		assert.ErrorIs(t, err, ErrPolicy)
+		_, err = ps.CalculatePacksWithOptions(12001, CalcOptions{Customer: customer(swagger.PackingPolicy{MaxPacks: utils.Ptr(2)})})
+		assert.ErrorIs(t, err, ErrPolicy)
+		_, err = ps.CalculatePacksWithOptions(1, CalcOptions{Customer: customer(swagger.PackingPolicy{ForbiddenPackSizes: &[]int{250, 500, 1000, 2000, 5000}})})
+		assert.ErrorIs(t, err, ErrPolicy)
+		_, err = ps.CalculatePacksWithOptions(1, CalcOptions{Customer: "missing"})
+		assert.ErrorIs(t, err, ErrCustomerNotFound)
+	})
+
+	t.Run("PreferredObjective", func(t *testing.T) {
+		id := customer(swagger.PackingPolicy{Objective: utils.Ptr(swagger.Price), MaxPacks: utils.Ptr(10)})
+		opts, err := ps.withCustomerPolicy(CalcOptions{Customer: id})
+		require.NoError(t, err)
+		assert.Equal(t, ObjectivePrice, opts.Objective)
+		opts, err = ps.withCustomerPolicy(CalcOptions{Customer: id, Objective: ObjectiveShipping, MaxPacks: 5})
+		require.NoError(t, err)
+		assert.Equal(t, ObjectiveShipping, opts.Objective)
+		assert.Equal(t, 5, opts.MaxPacks)
+	})
+
+	t.Run("InvalidPolicy", func(t *testing.T) {
+		_, err := ps.CreateCustomer(swagger.CustomerPayload{Name: "Acme", Policy: &swagger.PackingPolicy{MaxPacks: utils.Ptr(0)}})
+		var validation *ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+}
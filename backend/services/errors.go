@@ -0,0 +1,40 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"ship_line/swagger"
+)
+
+// ErrNotSupported is returned when the configured repository cannot store the
+// records a feature needs.
+var ErrNotSupported = errors.New("operation not supported by the configured repository")
+
+// ValidationError reports input rejected by the service layer. Handlers answer
+// it with 400 Bad Request. Fields lists the offending fields when the input
+// breaks field-level rules.
+type ValidationError struct {
+	Message string
+	Fields  []swagger.FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// invalid builds a *ValidationError.
+func invalid(message string) error {
+	return &ValidationError{Message: message}
+}
+
+// invalidFields builds a *ValidationError for field-level errors. The message
+// sums the errors up as "field: message" pairs.
+func invalidFields(fields []swagger.FieldError) error {
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return &ValidationError{Message: strings.Join(messages, "; "), Fields: fields}
+}
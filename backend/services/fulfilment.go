@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ErrNoWarehouse is returned when an order is sourced without any warehouse.
+var ErrNoWarehouse = errors.New("no warehouses configured")
+
+// stockedWarehouse is a warehouse prepared for sourcing: the service that
+// solves it, its stock as availability caps and the items that stock holds.
+type stockedWarehouse struct {
+	warehouse swagger.Warehouse
+	ps        *PackService
+	caps      map[int]int
+	// capacity is the number of items in stock, or -1 when a pack size of the
+	// warehouse has no stock limit.
+	capacity int
+}
+
+// sourcing is one way to split an order across warehouses.
+type sourcing struct {
+	shipments []swagger.WarehouseShipment
+	shipped   int
+	packs     int
+}
+
+// PlanFulfilment sources an order of items from the stored warehouses. Every
+// warehouse is solved by CalculatePacksWithOptions with its own pack sizes and
+// its stock as availability caps.
+//
+// A single warehouse that covers the order is preferred, the one with the least
+// overage and then the fewest packs. Otherwise the order is split across as
+// few warehouses as possible: all but one of them ship their whole stock and
+// the last one packs the rest, and the split with the least overage wins. The
+// warehouses shipping their whole stock are the ones with the most stock, so
+// every warehouse is tried as the last one with growing prefixes of the others
+// by stock, and the work grows with the square of the warehouse count at most.
+// When even all warehouses together cannot cover the order, they ship
+// everything they have and the rest is backordered.
+func (ps *PackService) PlanFulfilment(items int) (*swagger.FulfilmentPlan, error) {
+	warehouses, err := ps.ListWarehouses()
+	if err != nil {
+		return nil, err
+	}
+	if len(warehouses) == 0 {
+		return nil, ErrNoWarehouse
+	}
+	stocked := make([]stockedWarehouse, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		w, err := ps.stockWarehouse(warehouse)
+		if err != nil {
+			return nil, err
+		}
+		stocked = append(stocked, w)
+	}
+
+	plan := &swagger.FulfilmentPlan{ItemsOrdered: items, Shipments: []swagger.WarehouseShipment{}}
+	if items == 0 {
+		return plan, nil
+	}
+	byStock := bySizeOfStock(stocked)
+	var best *sourcing
+	for k := 1; k <= len(stocked) && (best == nil || best.shipped < items); k++ {
+		for last := range stocked {
+			subset := []int{last}
+			for _, i := range byStock {
+				if len(subset) == k {
+					break
+				}
+				if i != last {
+					subset = append(subset, i)
+				}
+			}
+			if len(subset) < k {
+				continue
+			}
+			slices.Sort(subset)
+			candidate, err := source(items, stocked, subset, last)
+			if err != nil {
+				return nil, err
+			}
+			if candidate != nil && candidate.better(best, items) {
+				best = candidate
+			}
+		}
+	}
+	if best == nil {
+		// No warehouse has any stock.
+		plan.ItemsBackordered = items
+		return plan, nil
+	}
+	plan.Shipments = best.shipments
+	plan.TotalItemsUsed = best.shipped
+	plan.ItemsBackordered = max(items-best.shipped, 0)
+	return plan, nil
+}
+
+// stockWarehouse prepares warehouse for sourcing.
+func (ps *PackService) stockWarehouse(warehouse swagger.Warehouse) (stockedWarehouse, error) {
+	w := stockedWarehouse{warehouse: warehouse, ps: ps.atWarehouse(warehouse)}
+	if warehouse.Stock != nil {
+		caps, err := utils.ParseMapKeys(*warehouse.Stock)
+		if err != nil {
+			return w, fmt.Errorf("failed to read stock of warehouse %s: %w", warehouse.Id, err)
+		}
+		w.caps = caps
+	}
+	sizes, _, err := w.ps.loadPackSet(time.Time{})
+	if err != nil {
+		return w, fmt.Errorf("failed to get pack sizes of warehouse %s: %w", warehouse.Id, err)
+	}
+	for _, size := range sizes {
+		count, capped := w.caps[size]
+		if !capped {
+			w.capacity = -1
+			break
+		}
+		w.capacity += count * size
+	}
+	return w, nil
+}
+
+// source ships the whole stock of every warehouse in subset but last, and the
+// rest of the order from last. It returns nil when the split makes no sense:
+// a warehouse without stock limits or stock, or nothing left for last.
+func source(items int, stocked []stockedWarehouse, subset []int, last int) (*sourcing, error) {
+	candidate := &sourcing{}
+	shipments := map[int]swagger.WarehouseShipment{}
+	rest := items
+	for _, i := range append(without(subset, last), last) {
+		w := stocked[i]
+		quantity := rest
+		if i != last {
+			if w.capacity <= 0 || w.capacity >= rest {
+				return nil, nil
+			}
+			quantity = w.capacity
+		}
+		result, err := w.ps.CalculatePacksWithOptions(quantity, CalcOptions{Availability: w.caps})
+		if errors.Is(err, errNoStock) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate packs for warehouse %s: %w", w.warehouse.Id, err)
+		}
+		shipped := *result.TotalItemsUsed
+		shipments[i] = swagger.WarehouseShipment{
+			WarehouseId: w.warehouse.Id,
+			Name:        w.warehouse.Name,
+			Result:      *result,
+		}
+		candidate.shipped += shipped
+		for _, count := range *result.PacksUsed {
+			candidate.packs += count
+		}
+		rest -= shipped
+	}
+	// List the shipments in the order of the warehouses.
+	for _, i := range subset {
+		candidate.shipments = append(candidate.shipments, shipments[i])
+	}
+	return candidate, nil
+}
+
+// better reports whether s sources an order of items better than best: it
+// covers more of the order, then has fewer shipments, less overage and fewer
+// packs. Ties keep best.
+func (s *sourcing) better(best *sourcing, items int) bool {
+	if best == nil {
+		return true
+	}
+	covered, bestCovered := min(s.shipped, items), min(best.shipped, items)
+	switch {
+	case covered != bestCovered:
+		return covered > bestCovered
+	case len(s.shipments) != len(best.shipments):
+		return len(s.shipments) < len(best.shipments)
+	case s.shipped != best.shipped:
+		return s.shipped < best.shipped
+	}
+	return s.packs < best.packs
+}
+
+// bySizeOfStock returns the indices of the warehouses with limited stock, the
+// ones holding the most items first.
+func bySizeOfStock(stocked []stockedWarehouse) []int {
+	var indices []int
+	for i, w := range stocked {
+		if w.capacity > 0 {
+			indices = append(indices, i)
+		}
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		return stocked[indices[a]].capacity > stocked[indices[b]].capacity
+	})
+	return indices
+}
+
+// without returns the elements of values other than v.
+func without(values []int, v int) []int {
+	var rest []int
+	for _, value := range values {
+		if value != v {
+			rest = append(rest, value)
+		}
+	}
+	return rest
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+// mockWarehouseRepo is a mockPackRepo that also stores warehouses.
+type mockWarehouseRepo struct {
+	mockPackRepo
+	warehouses mockRecords[swagger.Warehouse]
+}
+
+func (m *mockWarehouseRepo) ListWarehouses() ([]swagger.Warehouse, error) {
+	return m.warehouses.list()
+}
+
+func (m *mockWarehouseRepo) GetWarehouse(id string) (*swagger.Warehouse, error) {
+	return m.warehouses.get(id)
+}
+
+func (m *mockWarehouseRepo) CreateWarehouse(warehouse *swagger.Warehouse) error {
+	return m.warehouses.create(warehouse, &warehouse.Id)
+}
+
+func (m *mockWarehouseRepo) UpdateWarehouse(warehouse *swagger.Warehouse) error {
+	return m.warehouses.update(warehouse.Id, warehouse)
+}
+
+func (m *mockWarehouseRepo) DeleteWarehouse(id string) error {
+	return m.warehouses.delete(id)
+}
+
+func TestPlanFulfilment(t *testing.T) {
+	repo := &mockWarehouseRepo{mockPackRepo: mockPackRepo{sizes: []int{250, 500, 1000}}}
+	ps := NewPackService(repo)
+
+	_, err := ps.PlanFulfilment(100)
+	assert.ErrorIs(t, err, ErrNoWarehouse)
+
+	for _, payload := range []swagger.WarehousePayload{
+		{Name: "North", Stock: &map[string]int{"250": 2, "500": 0, "1000": 0}},
+		{Name: "South", PackSizes: &[]int{300, 600}, Stock: &map[string]int{"300": 1, "600": 1}},
+	} {
+		_, err := ps.CreateWarehouse(payload)
+		require.NoError(t, err)
+	}
+
+	t.Run("SingleWarehouse", func(t *testing.T) {
+		plan, err := ps.PlanFulfilment(400)
+		require.NoError(t, err)
+		require.Len(t, plan.Shipments, 1)
+		assert.Equal(t, "North", plan.Shipments[0].Name)
+		assert.Equal(t, 500, plan.TotalItemsUsed)
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		// North ships its whole stock; South packs the remaining 600 exactly.
+		plan, err := ps.PlanFulfilment(1100)
+		require.NoError(t, err)
+		require.Len(t, plan.Shipments, 2)
+		assert.Equal(t, map[string]int{"250": 2}, *plan.Shipments[0].Result.PacksUsed)
+		assert.Equal(t, map[string]int{"600": 1}, *plan.Shipments[1].Result.PacksUsed)
+		assert.Equal(t, 1100, plan.TotalItemsUsed)
+		assert.Zero(t, plan.ItemsBackordered)
+	})
+
+	t.Run("Shortage", func(t *testing.T) {
+		plan, err := ps.PlanFulfilment(2000)
+		require.NoError(t, err)
+		assert.Len(t, plan.Shipments, 2)
+		assert.Equal(t, 1400, plan.TotalItemsUsed)
+		assert.Equal(t, 600, plan.ItemsBackordered)
+	})
+
+	t.Run("UnlimitedStock", func(t *testing.T) {
+		_, err := ps.CreateWarehouse(swagger.WarehousePayload{Name: "Central"})
+		require.NoError(t, err)
+		plan, err := ps.PlanFulfilment(2000)
+		require.NoError(t, err)
+		require.Len(t, plan.Shipments, 1)
+		assert.Equal(t, "Central", plan.Shipments[0].Name)
+		assert.Equal(t, 2000, plan.TotalItemsUsed)
+	})
+
+	t.Run("ManyWarehouses", func(t *testing.T) {
+		for len(repo.warehouses.ids) < 60 {
+			_, err := ps.CreateWarehouse(swagger.WarehousePayload{Name: "Depot", PackSizes: &[]int{250}, Stock: &map[string]int{"250": 1}})
+			require.NoError(t, err)
+		}
+		require.NoError(t, ps.DeleteWarehouse("3"))
+		// North and South ship 1400 items; six depots cover the remaining 1500.
+		start := time.Now()
+		plan, err := ps.PlanFulfilment(2900)
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.Len(t, plan.Shipments, 8)
+		assert.Equal(t, 2900, plan.TotalItemsUsed)
+		assert.Zero(t, plan.ItemsBackordered)
+	})
+}
+
+func TestWarehouseFromPayload(t *testing.T) {
+	for _, payload := range []swagger.WarehousePayload{
+		{},
+		{Name: "North", PackSizes: &[]int{}},
+		{Name: "North", PackSizes: &[]int{250, 250}},
+		{Name: "North", Stock: &map[string]int{"250": -1}},
+		{Name: "North", PackSizes: &[]int{250}, Stock: &map[string]int{"500": 1}},
+	} {
+		_, err := warehouseFromPayload(payload)
+		var validation *ValidationError
+		assert.ErrorAs(t, err, &validation)
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"ship_line/swagger"
+)
+
+// ErrHierarchyNotFound is returned when a packaging hierarchy ID does not exist.
+var ErrHierarchyNotFound = errors.New("packaging hierarchy not found")
+
+// MaxUnitSize is the largest number of items a unit of a packaging level may hold.
+const MaxUnitSize = 1000000
+
+// HierarchyRepository is implemented by repositories that can also persist
+// packaging hierarchies.
+type HierarchyRepository interface {
+	ListHierarchies() ([]swagger.Hierarchy, error)
+	GetHierarchy(id string) (*swagger.Hierarchy, error)
+	CreateHierarchy(hierarchy *swagger.Hierarchy) error
+	UpdateHierarchy(hierarchy *swagger.Hierarchy) error
+	DeleteHierarchy(id string) error
+}
+
+// hierarchyRepo returns the packaging hierarchy storage of the configured repository.
+func (ps *PackService) hierarchyRepo() (HierarchyRepository, error) {
+	repo, ok := ps.repo.(HierarchyRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// hierarchies returns the CRUD methods of the stored packaging hierarchies.
+func (ps *PackService) hierarchies() records[swagger.Hierarchy, swagger.HierarchyPayload] {
+	repo, err := ps.hierarchyRepo()
+	if err != nil {
+		return records[swagger.Hierarchy, swagger.HierarchyPayload]{err: err}
+	}
+	return records[swagger.Hierarchy, swagger.HierarchyPayload]{
+		noun:        "packaging hierarchy",
+		plural:      "packaging hierarchies",
+		notFound:    ErrHierarchyNotFound,
+		fromPayload: hierarchyFromPayload,
+		id:          func(hierarchy *swagger.Hierarchy) *string { return &hierarchy.Id },
+		list:        repo.ListHierarchies,
+		get:         repo.GetHierarchy,
+		create:      repo.CreateHierarchy,
+		update:      repo.UpdateHierarchy,
+		delete:      repo.DeleteHierarchy,
+	}
+}
+
+// ListHierarchies retrieves every packaging hierarchy.
+func (ps *PackService) ListHierarchies() ([]swagger.Hierarchy, error) {
+	return ps.hierarchies().listAll()
+}
+
+// GetHierarchy retrieves a packaging hierarchy by ID.
+func (ps *PackService) GetHierarchy(id string) (*swagger.Hierarchy, error) {
+	return ps.hierarchies().getOne(id)
+}
+
+// CreateHierarchy validates payload and stores it as a new packaging hierarchy.
+func (ps *PackService) CreateHierarchy(payload swagger.HierarchyPayload) (*swagger.Hierarchy, error) {
+	return ps.hierarchies().createFrom(payload)
+}
+
+// UpdateHierarchy replaces the packaging hierarchy with the given ID by payload.
+func (ps *PackService) UpdateHierarchy(id string, payload swagger.HierarchyPayload) (*swagger.Hierarchy, error) {
+	return ps.hierarchies().updateFrom(id, payload)
+}
+
+// DeleteHierarchy removes a packaging hierarchy.
+func (ps *PackService) DeleteHierarchy(id string) error {
+	return ps.hierarchies().remove(id)
+}
+
+// hierarchyFromPayload validates payload and converts it into a Hierarchy
+// without an ID. The sizes of every level are stored in ascending order.
+func hierarchyFromPayload(payload swagger.HierarchyPayload) (*swagger.Hierarchy, error) {
+	if payload.Name == "" {
+		return nil, invalid("name is required")
+	}
+	if len(payload.Levels) == 0 {
+		return nil, invalid("at least one level is required")
+	}
+	names := map[string]bool{packLevel: true}
+	levels := make([]swagger.PackagingLevel, 0, len(payload.Levels))
+	for _, level := range payload.Levels {
+		if level.Name == "" || names[level.Name] {
+			return nil, invalid(fmt.Sprintf("level names must be unique, non-empty and not %q", packLevel))
+		}
+		names[level.Name] = true
+		if level.Objective != nil && *level.Objective != swagger.Consolidate && *level.Objective != swagger.FewestUnits {
+			return nil, invalid(fmt.Sprintf("level %s has an invalid objective", level.Name))
+		}
+		if len(level.Sizes) == 0 {
+			return nil, invalid(fmt.Sprintf("level %s needs at least one size", level.Name))
+		}
+		sizes := append([]int{}, level.Sizes...)
+		sort.Ints(sizes)
+		for i, size := range sizes {
+			if size <= 0 || size > MaxUnitSize || (i > 0 && size == sizes[i-1]) {
+				return nil, invalid(fmt.Sprintf("the sizes of level %s must be unique and between 1 and %d", level.Name, MaxUnitSize))
+			}
+		}
+		level.Sizes = sizes
+		levels = append(levels, level)
+	}
+	return &swagger.Hierarchy{Name: payload.Name, Levels: levels}, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ErrKitNotFound is returned when a kit ID does not exist.
+var ErrKitNotFound = errors.New("kit not found")
+
+// KitRepository is implemented by repositories that can also persist kits,
+// packs that hold items of several SKUs.
+type KitRepository interface {
+	ListKits() ([]swagger.Kit, error)
+	GetKit(id string) (*swagger.Kit, error)
+	CreateKit(kit *swagger.Kit) error
+	UpdateKit(kit *swagger.Kit) error
+	DeleteKit(id string) error
+}
+
+// kitRepo returns the kit storage of the configured repository.
+func (ps *PackService) kitRepo() (KitRepository, error) {
+	repo, ok := ps.repo.(KitRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// kits returns the CRUD methods of the stored kits.
+func (ps *PackService) kits() records[swagger.Kit, swagger.KitPayload] {
+	repo, err := ps.kitRepo()
+	if err != nil {
+		return records[swagger.Kit, swagger.KitPayload]{err: err}
+	}
+	return records[swagger.Kit, swagger.KitPayload]{
+		noun:        "kit",
+		plural:      "kits",
+		notFound:    ErrKitNotFound,
+		fromPayload: kitFromPayload,
+		id:          func(kit *swagger.Kit) *string { return &kit.Id },
+		list:        repo.ListKits,
+		get:         repo.GetKit,
+		create:      repo.CreateKit,
+		update:      repo.UpdateKit,
+		delete:      repo.DeleteKit,
+	}
+}
+
+// ListKits retrieves every kit.
+func (ps *PackService) ListKits() ([]swagger.Kit, error) {
+	return ps.kits().listAll()
+}
+
+// GetKit retrieves a kit by ID.
+func (ps *PackService) GetKit(id string) (*swagger.Kit, error) {
+	return ps.kits().getOne(id)
+}
+
+// CreateKit validates payload and stores it as a new kit.
+func (ps *PackService) CreateKit(payload swagger.KitPayload) (*swagger.Kit, error) {
+	return ps.kits().createFrom(payload)
+}
+
+// UpdateKit replaces the kit with the given ID by payload.
+func (ps *PackService) UpdateKit(id string, payload swagger.KitPayload) (*swagger.Kit, error) {
+	return ps.kits().updateFrom(id, payload)
+}
+
+// DeleteKit removes a kit.
+func (ps *PackService) DeleteKit(id string) error {
+	return ps.kits().remove(id)
+}
+
+// kitFromPayload validates payload and converts it into a Kit without an ID.
+func kitFromPayload(payload swagger.KitPayload) (*swagger.Kit, error) {
+	if payload.Name == "" {
+		return nil, invalid("name is required")
+	}
+	if len(payload.Contents) == 0 {
+		return nil, invalid("a kit must contain at least one SKU")
+	}
+	total := 0
+	for sku, count := range payload.Contents {
+		if sku == "" || count <= 0 {
+			return nil, invalid("kit contents need non-empty SKUs and positive counts")
+		}
+		total += count
+	}
+	if total > MaxUnitSize {
+		return nil, invalid(fmt.Sprintf("a kit may hold at most %d items", MaxUnitSize))
+	}
+	return &swagger.Kit{Name: payload.Name, Contents: utils.CopyMap(payload.Contents)}, nil
+}
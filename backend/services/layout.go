@@ -0,0 +1,274 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// MaxLayoutPacks is the largest number of packs a distribution may have to be
+// arranged in boxes.
+const MaxLayoutPacks = 500
+
+// box is a shipping box being filled by layoutPacks.
+type box struct {
+	kind       swagger.ContainerType
+	placements []swagger.Placement
+	// points are the candidate positions for the next pack, ordered bottom to
+	// top, back to front and left to right.
+	points  [][3]float64
+	items   int
+	weight  float64
+	volume  float64
+	weighed bool
+}
+
+// newBox returns an empty box of the given container type, which must have
+// dimensions.
+func newBox(kind swagger.ContainerType) *box {
+	return &box{kind: kind, points: [][3]float64{{0, 0, 0}}, weighed: true}
+}
+
+// layoutPacks arranges the packs of result in boxes of the stored container
+// types that have dimensions and returns the position of every pack.
+//
+// Packs are placed largest volume first with an extreme-point heuristic: each
+// box keeps the corners next to the packs already placed as candidate
+// positions, and a pack goes to the lowest, rearmost, leftmost candidate where
+// one of its allowed orientations fits without overlapping. Packs that fit no
+// open box open a new one of the largest type they fit, and finally every box
+// is re-arranged in the cheapest, then smallest, type that holds its packs.
+func (ps *PackService) layoutPacks(result *swagger.CalcResult, bySize map[int]swagger.Pack) ([]swagger.BoxLayout, error) {
+	if bySize == nil {
+		return nil, ErrNotSupported
+	}
+	repo, err := ps.containerTypeRepo()
+	if err != nil {
+		return nil, err
+	}
+	all, err := repo.ListContainerTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list container types: %w", err)
+	}
+	var kinds []swagger.ContainerType
+	for _, kind := range all {
+		if kind.Dimensions != nil {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, invalid("no container types with dimensions configured")
+	}
+
+	counts, err := utils.ParseMapKeys(*result.PacksUsed)
+	if err != nil {
+		return nil, err
+	}
+	var packs []swagger.Pack
+	for size, count := range counts {
+		pack := bySize[size]
+		if count > 0 && pack.Dimensions == nil {
+			return nil, invalid(fmt.Sprintf("pack %s (size %d) has no dimensions", pack.Id, size))
+		}
+		if len(packs)+count > MaxLayoutPacks {
+			return nil, invalid(fmt.Sprintf("a layout is limited to %d packs", MaxLayoutPacks))
+		}
+		for range count {
+			packs = append(packs, pack)
+		}
+	}
+	sort.SliceStable(packs, func(i, j int) bool {
+		if vi, vj := packVolume(packs[i]), packVolume(packs[j]); vi != vj {
+			return vi > vj
+		}
+		return packs[i].Size > packs[j].Size
+	})
+
+	var boxes []*box
+	var contents [][]swagger.Pack
+	for _, pack := range packs {
+		placed := false
+		for i, b := range boxes {
+			if b.place(pack) {
+				contents[i] = append(contents[i], pack)
+				placed = true
+				break
+			}
+		}
+		if placed {
+			continue
+		}
+		var best *box
+		for _, kind := range kinds {
+			b := newBox(kind)
+			if b.place(pack) && (best == nil || innerVolume(kind) > innerVolume(best.kind) ||
+				(innerVolume(kind) == innerVolume(best.kind) && cheaper(kind, best.kind))) {
+				best = b
+			}
+		}
+		if best == nil {
+			return nil, fmt.Errorf("%w: pack size %d", ErrNoContainer, pack.Size)
+		}
+		boxes = append(boxes, best)
+		contents = append(contents, []swagger.Pack{pack})
+	}
+
+	sort.SliceStable(kinds, func(i, j int) bool {
+		if cheaper(kinds[i], kinds[j]) || cheaper(kinds[j], kinds[i]) {
+			return cheaper(kinds[i], kinds[j])
+		}
+		return innerVolume(kinds[i]) < innerVolume(kinds[j])
+	})
+	layouts := make([]swagger.BoxLayout, 0, len(boxes))
+	for i, b := range boxes {
+		// The box's own type is among the candidates and always succeeds, as
+		// placing the same packs in the same order is deterministic.
+		for _, kind := range kinds {
+			if repacked, ok := arrange(kind, contents[i]); ok {
+				b = repacked
+				break
+			}
+		}
+		layouts = append(layouts, b.layout())
+	}
+	return layouts, nil
+}
+
+// arrange places packs in order into an empty box of the given type.
+func arrange(kind swagger.ContainerType, packs []swagger.Pack) (*box, bool) {
+	b := newBox(kind)
+	for _, pack := range packs {
+		if !b.place(pack) {
+			return nil, false
+		}
+	}
+	return b, true
+}
+
+// place puts pack at the first candidate position of b where it fits and
+// reports whether it did.
+func (b *box) place(pack swagger.Pack) bool {
+	kind := b.kind
+	volume := packVolume(pack)
+	if (kind.MaxItems != nil && b.items+pack.Size > *kind.MaxItems) ||
+		(kind.MaxWeight != nil && (pack.Weight == nil || b.weight+*pack.Weight > payloadLimit(kind)+limitTolerance)) ||
+		(kind.MaxVolume != nil && b.volume+volume > *kind.MaxVolume+limitTolerance) ||
+		b.volume+volume > innerVolume(kind)*fillRatio(kind)+limitTolerance {
+		return false
+	}
+	for i, point := range b.points {
+		for _, extent := range orientations(pack) {
+			candidate := swagger.Placement{
+				PackId: pack.Id, Size: pack.Size,
+				X: point[0], Y: point[1], Z: point[2],
+				Length: extent[0], Width: extent[1], Height: extent[2],
+			}
+			if !b.fits(candidate) {
+				continue
+			}
+			b.placements = append(b.placements, candidate)
+			b.items += pack.Size
+			b.volume += volume
+			if pack.Weight != nil {
+				b.weight += *pack.Weight
+			} else {
+				b.weighed = false
+			}
+			b.points = append(b.points[:i], b.points[i+1:]...)
+			b.addPoint(candidate.X+candidate.Length, candidate.Y, candidate.Z)
+			b.addPoint(candidate.X, candidate.Y+candidate.Width, candidate.Z)
+			b.addPoint(candidate.X, candidate.Y, candidate.Z+candidate.Height)
+			return true
+		}
+	}
+	return false
+}
+
+// fits reports whether p lies inside b and overlaps none of its placements.
+func (b *box) fits(p swagger.Placement) bool {
+	d := b.kind.Dimensions
+	if p.X+p.Length > d.Length+limitTolerance || p.Y+p.Width > d.Width+limitTolerance ||
+		p.Z+p.Height > d.Height+limitTolerance {
+		return false
+	}
+	for _, q := range b.placements {
+		if p.X < q.X+q.Length-limitTolerance && q.X < p.X+p.Length-limitTolerance &&
+			p.Y < q.Y+q.Width-limitTolerance && q.Y < p.Y+p.Width-limitTolerance &&
+			p.Z < q.Z+q.Height-limitTolerance && q.Z < p.Z+p.Height-limitTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// addPoint adds a candidate position to b, keeping the points ordered by z,
+// then y, then x, and dropping duplicates and points outside the box.
+func (b *box) addPoint(x, y, z float64) {
+	d := b.kind.Dimensions
+	if x >= d.Length-limitTolerance || y >= d.Width-limitTolerance || z >= d.Height-limitTolerance {
+		return
+	}
+	point := [3]float64{x, y, z}
+	i := sort.Search(len(b.points), func(i int) bool {
+		p := b.points[i]
+		if p[2] != z {
+			return p[2] > z
+		}
+		if p[1] != y {
+			return p[1] > y
+		}
+		return p[0] >= x
+	})
+	if i < len(b.points) && b.points[i] == point {
+		return
+	}
+	b.points = append(b.points, [3]float64{})
+	copy(b.points[i+1:], b.points[i:])
+	b.points[i] = point
+}
+
+// layout describes the contents of b.
+func (b *box) layout() swagger.BoxLayout {
+	layout := swagger.BoxLayout{
+		ContainerTypeId: b.kind.Id,
+		Name:            b.kind.Name,
+		Dimensions:      *b.kind.Dimensions,
+		FillRatio:       math.Round(b.volume/innerVolume(b.kind)*10000) / 10000,
+		Placements:      b.placements,
+	}
+	if b.weighed {
+		layout.Weight = utils.Ptr(math.Round(b.weight*1000) / 1000)
+	}
+	return layout
+}
+
+// orientations returns the extents along x, y and z that pack may take. Upright
+// packs keep their height vertical and may only turn around the vertical axis.
+func orientations(pack swagger.Pack) [][3]float64 {
+	l, w, h := pack.Dimensions.Length, pack.Dimensions.Width, pack.Dimensions.Height
+	candidates := [][3]float64{{l, w, h}, {w, l, h}}
+	if pack.Upright == nil || !*pack.Upright {
+		candidates = append(candidates, [3]float64{l, h, w}, [3]float64{h, l, w}, [3]float64{w, h, l}, [3]float64{h, w, l})
+	}
+	var unique [][3]float64
+	for _, candidate := range candidates {
+		seen := false
+		for _, other := range unique {
+			seen = seen || other == candidate
+		}
+		if !seen {
+			unique = append(unique, candidate)
+		}
+	}
+	return unique
+}
+
+// innerVolume returns the inner volume of a container type with dimensions in
+// cubic centimetres.
+func innerVolume(kind swagger.ContainerType) float64 {
+	d := kind.Dimensions
+	return d.Length * d.Width * d.Height
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+func TestLayoutPacks(t *testing.T) {
+	repo := &mockContainerCatalog{}
+	ps := NewPackService(repo)
+	_, err := ps.CreatePack(physicalPack(250, 3, 20))
+	require.NoError(t, err)
+	for _, payload := range []swagger.ContainerTypePayload{
+		{Name: "Small", Dimensions: &swagger.PackDimensions{Length: 40, Width: 40, Height: 20}, Cost: utils.Ptr(1.0)},
+		{Name: "Large", Dimensions: &swagger.PackDimensions{Length: 40, Width: 40, Height: 40}, Cost: utils.Ptr(3.0)},
+	} {
+		_, err := ps.CreateContainerType(payload)
+		require.NoError(t, err)
+	}
+
+	t.Run("FullBox", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(2000, CalcOptions{Layout: true})
+		require.NoError(t, err)
+		require.Len(t, *result.Layout, 1)
+		layout := (*result.Layout)[0]
+		assert.Equal(t, "Large", layout.Name)
+		assert.Equal(t, 1.0, layout.FillRatio)
+		assert.Equal(t, 24.0, *layout.Weight)
+		require.Len(t, layout.Placements, 8)
+		assert.Equal(t, swagger.Placement{PackId: "1", Size: 250, Length: 20, Width: 20, Height: 20}, layout.Placements[0])
+	})
+
+	t.Run("CheapestBoxThatHoldsThePacks", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(1000, CalcOptions{Layout: true})
+		require.NoError(t, err)
+		require.Len(t, *result.Layout, 1)
+		layout := (*result.Layout)[0]
+		assert.Equal(t, "Small", layout.Name)
+		for _, placement := range layout.Placements {
+			assert.Zero(t, placement.Z)
+		}
+	})
+
+	t.Run("FillRatio", func(t *testing.T) {
+		_, err := ps.UpdateContainerType("2", swagger.ContainerTypePayload{
+			Name:         "Large",
+			Dimensions:   &swagger.PackDimensions{Length: 40, Width: 40, Height: 40},
+			MaxFillRatio: utils.Ptr(0.5),
+			Cost:         utils.Ptr(3.0),
+		})
+		require.NoError(t, err)
+		result, err := ps.CalculatePacksWithOptions(2000, CalcOptions{Layout: true})
+		require.NoError(t, err)
+		assert.Len(t, *result.Layout, 2)
+	})
+
+	t.Run("Orientation", func(t *testing.T) {
+		tall := swagger.PackPayload{Size: 500, Dimensions: &swagger.PackDimensions{Length: 10, Width: 10, Height: 30}}
+		_, err := ps.UpdatePack("1", tall)
+		require.NoError(t, err)
+		result, err := ps.CalculatePacksWithOptions(500, CalcOptions{Layout: true})
+		require.NoError(t, err)
+		placement := (*result.Layout)[0].Placements[0]
+		assert.Equal(t, 10.0, placement.Height)
+		assert.Nil(t, (*result.Layout)[0].Weight)
+
+		tall.Upright = utils.Ptr(true)
+		tall.Dimensions.Height = 50
+		_, err = ps.UpdatePack("1", tall)
+		require.NoError(t, err)
+		_, err = ps.CalculatePacksWithOptions(500, CalcOptions{Layout: true})
+		assert.ErrorIs(t, err, ErrNoContainer)
+	})
+}
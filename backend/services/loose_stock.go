@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+
+	"ship_line/swagger"
+)
+
+// LooseStockRepository is implemented by repositories that track the items
+// left over from opened packs, so that later orders can use them.
+type LooseStockRepository interface {
+	GetLooseStock() (int, error)
+	SetLooseStock(items int) error
+	// TakeLooseStock atomically removes up to limit items and returns how
+	// many were taken.
+	TakeLooseStock(limit int) (int, error)
+	AddLooseStock(items int) error
+}
+
+// looseStockRepo returns the loose-item pool of the configured repository.
+func (ps *PackService) looseStockRepo() (LooseStockRepository, error) {
+	repo, ok := ps.repo.(LooseStockRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// GetLooseStock returns the loose-item pool.
+func (ps *PackService) GetLooseStock() (*swagger.LooseStock, error) {
+	repo, err := ps.looseStockRepo()
+	if err != nil {
+		return nil, err
+	}
+	items, err := repo.GetLooseStock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loose stock: %w", err)
+	}
+	return &swagger.LooseStock{Items: items}, nil
+}
+
+// SetLooseStock replaces the loose-item pool, for example after a stock count.
+func (ps *PackService) SetLooseStock(stock swagger.LooseStock) (*swagger.LooseStock, error) {
+	repo, err := ps.looseStockRepo()
+	if err != nil {
+		return nil, err
+	}
+	if stock.Items < 0 {
+		return nil, invalid("items must not be negative")
+	}
+	if err := repo.SetLooseStock(stock.Items); err != nil {
+		return nil, fmt.Errorf("failed to store loose stock: %w", err)
+	}
+	return &stock, nil
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// packLevel is the name of the innermost packaging level, the packs themselves.
+const packLevel = "pack"
+
+// levelTable is a packTable for one level of a packaging hierarchy. For every
+// total up to a limit it holds the handling units needed to pack it exactly
+// with this level and the ones below (unreachable if impossible), the items
+// left to the levels below (loose), and the size of the last unit of this
+// level on that path. last is 0 where the total is handed to the level below.
+type levelTable struct {
+	units []int
+	loose []int
+	last  []int
+}
+
+// newLevelTable fills the levelTable of a level with the given unit sizes on
+// top of the table of the level below. Consolidate prefers fewer loose items
+// and then fewer units; FewestUnits prefers fewer units and then fewer loose
+// items.
+func newLevelTable(lower *levelTable, sizes []int, objective swagger.LevelObjective) *levelTable {
+	limit := len(lower.units) - 1
+	t := &levelTable{
+		units: make([]int, limit+1),
+		loose: make([]int, limit+1),
+		last:  make([]int, limit+1),
+	}
+	better := func(loose, units, s int) bool {
+		if objective == swagger.FewestUnits {
+			return units < t.units[s] || (units == t.units[s] && loose < t.loose[s])
+		}
+		return loose < t.loose[s] || (loose == t.loose[s] && units < t.units[s])
+	}
+	for s := 0; s <= limit; s++ {
+		t.units[s], t.loose[s] = lower.units[s], s
+		if t.units[s] == unreachable {
+			t.loose[s] = unreachable
+		}
+		for _, size := range sizes {
+			if s < size || t.units[s-size] == unreachable {
+				continue
+			}
+			if better(t.loose[s-size], t.units[s-size]+1, s) {
+				t.units[s], t.loose[s], t.last[s] = t.units[s-size]+1, t.loose[s-size], size
+			}
+		}
+	}
+	return t
+}
+
+// nestUnits splits the items of result into the levels of the packaging
+// hierarchy with the given ID, for example 3 pallets + 2 cartons + 1 pack, and
+// returns the units together with the pack counts they amount to.
+//
+// The total shipped is the one chosen by the solver; only its composition
+// changes. Every level is solved with a DP over the totals up to the order on
+// top of the table of the level below, starting with the fewest-packs table
+// of calculatePacksDP. Totals above dpThreshold are first reduced with the
+// largest unit of the outermost level, as solveExact does with packs.
+func (ps *PackService) nestUnits(id string, result *swagger.CalcResult, packSizes []int) ([]swagger.PackagingUnit, map[int]int, error) {
+	hierarchy, err := ps.GetHierarchy(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	sizes := sortedDesc(packSizes)
+	total := *result.TotalItemsUsed
+	top := hierarchy.Levels[len(hierarchy.Levels)-1]
+	largest := top.Sizes[len(top.Sizes)-1]
+	table := residueTable(sizes)
+	peeled := 0
+	if total > dpThreshold {
+		peeled = (total - dpThreshold + largest - 1) / largest
+	}
+	rest := total - peeled*largest
+	for peeled > 0 && !representable(rest, table) {
+		peeled--
+		rest += largest
+	}
+
+	limit := rest
+	for _, level := range hierarchy.Levels {
+		limit = max(limit, level.Sizes[len(level.Sizes)-1])
+	}
+	packs := newPackTable(limit, sizes)
+	tables := []*levelTable{{units: packs.packs, loose: make([]int, limit+1), last: packs.last}}
+	names := []string{packLevel}
+	for _, level := range hierarchy.Levels {
+		lower := tables[len(tables)-1]
+		for _, size := range level.Sizes {
+			if lower.units[size] == unreachable {
+				return nil, nil, invalid(fmt.Sprintf("a %s of %d items cannot be filled exactly with the levels below", level.Name, size))
+			}
+		}
+		objective := swagger.Consolidate
+		if level.Objective != nil {
+			objective = *level.Objective
+		}
+		tables = append(tables, newLevelTable(lower, sortedDesc(level.Sizes), objective))
+		names = append(names, level.Name)
+	}
+
+	n := &nester{tables: tables, names: names, packs: make(map[[2]int]map[int]int)}
+	counts := n.split(len(tables)-1, rest)
+	if peeled > 0 {
+		counts[[2]int{len(tables) - 1, largest}] += peeled
+	}
+	units := n.units(counts)
+	used := make(map[int]int)
+	for key, count := range counts {
+		for size, packCount := range n.packsIn(key[0], key[1]) {
+			used[size] += packCount * count
+		}
+	}
+	return units, used, nil
+}
+
+// inStock returns the pack sizes whose availability is not exhausted.
+func inStock(packSizes []int, availability map[int]int) []int {
+	sizes := make([]int, 0, len(packSizes))
+	for _, size := range packSizes {
+		if available, capped := availability[size]; !capped || available > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
+
+// nester rebuilds nested distributions from the level tables of a hierarchy.
+// Units are keyed by their level index (0 for packs) and size.
+type nester struct {
+	tables []*levelTable
+	names  []string
+	packs  map[[2]int]map[int]int
+}
+
+// split returns the units that pack the total s exactly at the given level and
+// the levels below it.
+func (n *nester) split(level, s int) map[[2]int]int {
+	counts := make(map[[2]int]int)
+	for ; level >= 0; level-- {
+		for ; s > 0 && n.tables[level].last[s] != 0; s -= n.tables[level].last[s] {
+			counts[[2]int{level, n.tables[level].last[s]}]++
+		}
+	}
+	return counts
+}
+
+// units converts counts into PackagingUnits, outermost level and largest size
+// first, with the contents of every unit above the packs.
+func (n *nester) units(counts map[[2]int]int) []swagger.PackagingUnit {
+	keys := make([][2]int, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] > keys[j][0]
+		}
+		return keys[i][1] > keys[j][1]
+	})
+	units := make([]swagger.PackagingUnit, 0, len(keys))
+	for _, key := range keys {
+		unit := swagger.PackagingUnit{Level: n.names[key[0]], Size: key[1], Count: counts[key]}
+		if key[0] > 0 {
+			unit.Contents = utils.Ptr(n.units(n.split(key[0]-1, key[1])))
+		}
+		units = append(units, unit)
+	}
+	return units
+}
+
+// packsIn returns the packs that make up one unit of the given level and size.
+func (n *nester) packsIn(level, size int) map[int]int {
+	if level == 0 {
+		return map[int]int{size: 1}
+	}
+	key := [2]int{level, size}
+	if packs, ok := n.packs[key]; ok {
+		return packs
+	}
+	packs := make(map[int]int)
+	for inner, count := range n.split(level-1, size) {
+		for packSize, packCount := range n.packsIn(inner[0], inner[1]) {
+			packs[packSize] += packCount * count
+		}
+	}
+	n.packs[key] = packs
+	return packs
+}
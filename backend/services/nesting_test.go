@@ -0,0 +1,138 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// mockHierarchyRepo is a mockPackRepo that also stores packaging hierarchies.
+type mockHierarchyRepo struct {
+	mockPackRepo
+	hierarchies mockRecords[swagger.Hierarchy]
+}
+
+func (m *mockHierarchyRepo) ListHierarchies() ([]swagger.Hierarchy, error) {
+	return m.hierarchies.list()
+}
+
+func (m *mockHierarchyRepo) GetHierarchy(id string) (*swagger.Hierarchy, error) {
+	return m.hierarchies.get(id)
+}
+
+func (m *mockHierarchyRepo) CreateHierarchy(hierarchy *swagger.Hierarchy) error {
+	return m.hierarchies.create(hierarchy, &hierarchy.Id)
+}
+
+func (m *mockHierarchyRepo) UpdateHierarchy(hierarchy *swagger.Hierarchy) error {
+	return m.hierarchies.update(hierarchy.Id, hierarchy)
+}
+
+func (m *mockHierarchyRepo) DeleteHierarchy(id string) error {
+	return m.hierarchies.delete(id)
+}
+
+func TestNestUnits(t *testing.T) {
+	repo := &mockHierarchyRepo{mockPackRepo: mockPackRepo{sizes: []int{250, 500, 1000}}}
+	ps := NewPackService(repo)
+	_, err := ps.CreateHierarchy(swagger.HierarchyPayload{
+		Name: "Warehouse",
+		Levels: []swagger.PackagingLevel{
+			{Name: "carton", Sizes: []int{5000, 2000}},
+			{Name: "pallet", Sizes: []int{20000}},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("Consolidate", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(47251, CalcOptions{Hierarchy: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, 47500, *result.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"1000": 47, "500": 1}, *result.PacksUsed)
+
+		carton := func(packs int) []swagger.PackagingUnit {
+			return []swagger.PackagingUnit{{Level: packLevel, Size: 1000, Count: packs}}
+		}
+		assert.Equal(t, []swagger.PackagingUnit{
+			{Level: "pallet", Size: 20000, Count: 2, Contents: &[]swagger.PackagingUnit{
+				{Level: "carton", Size: 5000, Count: 4, Contents: utils.Ptr(carton(5))},
+			}},
+			{Level: "carton", Size: 5000, Count: 1, Contents: utils.Ptr(carton(5))},
+			{Level: "carton", Size: 2000, Count: 1, Contents: utils.Ptr(carton(2))},
+			{Level: packLevel, Size: 500, Count: 1},
+		}, *result.Units)
+	})
+
+	t.Run("FewestUnits", func(t *testing.T) {
+		repo.sizes = []int{250, 1000}
+		for _, objective := range []swagger.LevelObjective{swagger.Consolidate, swagger.FewestUnits} {
+			_, err := ps.UpdateHierarchy("1", swagger.HierarchyPayload{
+				Name:   "Cartons",
+				Levels: []swagger.PackagingLevel{{Name: "carton", Sizes: []int{750}, Objective: utils.Ptr(objective)}},
+			})
+			require.NoError(t, err)
+			result, err := ps.CalculatePacksWithOptions(1000, CalcOptions{Hierarchy: "1"})
+			require.NoError(t, err)
+			if objective == swagger.Consolidate {
+				assert.Equal(t, map[string]int{"250": 4}, *result.PacksUsed)
+				assert.Len(t, *result.Units, 2)
+			} else {
+				assert.Equal(t, map[string]int{"1000": 1}, *result.PacksUsed)
+				assert.Len(t, *result.Units, 1)
+			}
+		}
+	})
+
+	t.Run("Constraints", func(t *testing.T) {
+		// A 750 carton holds a 500 and a 250, so nesting 1000 items ships
+		// three packs where the solver chose a single 1000.
+		repo.sizes = []int{250, 500, 1000}
+		_, err := ps.UpdateHierarchy("1", swagger.HierarchyPayload{
+			Name:   "Cartons",
+			Levels: []swagger.PackagingLevel{{Name: "carton", Sizes: []int{750}}},
+		})
+		require.NoError(t, err)
+		result, err := ps.CalculatePacksWithOptions(1000, CalcOptions{Hierarchy: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"250": 2, "500": 1}, *result.PacksUsed)
+
+		_, err = ps.CalculatePacksWithOptions(1000, CalcOptions{Hierarchy: "1", Availability: map[int]int{250: 1}})
+		assert.ErrorIs(t, err, ErrPolicy)
+		_, err = ps.CalculatePacksWithOptions(1000, CalcOptions{Hierarchy: "1", MaxPacks: 1})
+		assert.ErrorIs(t, err, ErrPolicy)
+
+		// Sizes out of stock are not nested into, so the carton cannot be filled.
+		_, err = ps.CalculatePacksWithOptions(1000, CalcOptions{Hierarchy: "1", Availability: map[int]int{250: 0}})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("UnfillableUnit", func(t *testing.T) {
+		_, err := ps.UpdateHierarchy("1", swagger.HierarchyPayload{
+			Name:   "Cartons",
+			Levels: []swagger.PackagingLevel{{Name: "carton", Sizes: []int{300}}},
+		})
+		require.NoError(t, err)
+		_, err = ps.CalculatePacksWithOptions(1000, CalcOptions{Hierarchy: "1"})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		for _, payload := range []swagger.HierarchyPayload{
+			{Name: "Empty"},
+			{Name: "Packs", Levels: []swagger.PackagingLevel{{Name: packLevel, Sizes: []int{10}}}},
+			{Name: "Twice", Levels: []swagger.PackagingLevel{{Name: "carton", Sizes: []int{10, 10}}}},
+			{Name: "Objective", Levels: []swagger.PackagingLevel{{Name: "carton", Sizes: []int{10}, Objective: utils.Ptr(swagger.LevelObjective("cheap"))}}},
+		} {
+			_, err := ps.CreateHierarchy(payload)
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		}
+		_, err := ps.CalculatePacksWithOptions(1000, CalcOptions{Hierarchy: "9"})
+		assert.ErrorIs(t, err, ErrHierarchyNotFound)
+	})
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ErrOrderNotFound is returned when an order ID does not exist.
+var ErrOrderNotFound = errors.New("order not found")
+
+// OrderRepository is implemented by repositories that can also persist orders.
+type OrderRepository interface {
+	CreateOrder(order *swagger.Order) error
+	UpdateOrder(order *swagger.Order) error
+	GetOrder(id string) (*swagger.Order, error)
+	ListOrders() ([]swagger.Order, error)
+}
+
+// orderRepo returns the order storage of the configured repository.
+func (ps *PackService) orderRepo() (OrderRepository, error) {
+	repo, ok := ps.repo.(OrderRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// CreateOrder calculates the pack distribution for an order and stores both.
+// Only the customer, attributes and objective of opts are used; the customer
+// and attributes select the customer policy and packing rules the order is
+// packed under.
+//
+// With a loose-item pool, the order is first filled from the pool and only the
+// rest is packed; the overage of the opened packs is then added to the pool.
+func (ps *PackService) CreateOrder(items int, opts CalcOptions) (*swagger.Order, error) {
+	repo, err := ps.orderRepo()
+	if err != nil {
+		return nil, err
+	}
+	opts = CalcOptions{Customer: opts.Customer, Attributes: opts.Attributes, Objective: opts.Objective}
+	if opts.Customer != "" {
+		// Check the customer before touching the loose-item pool.
+		if _, err := ps.GetCustomer(opts.Customer); err != nil {
+			return nil, err
+		}
+	}
+	pool, _ := ps.looseStockRepo()
+	loose := 0
+	if pool != nil && items > 0 {
+		if loose, err = pool.TakeLooseStock(items); err != nil {
+			return nil, fmt.Errorf("failed to take loose stock: %w", err)
+		}
+	}
+	order, err := ps.storeOrder(repo, items, loose, opts)
+	if err != nil {
+		if loose > 0 {
+			// Put the loose items back, as the order was not placed.
+			_ = pool.AddLooseStock(loose)
+		}
+		return nil, err
+	}
+	if overage := *order.Result.TotalItemsUsed - items; pool != nil && overage > 0 {
+		if err := pool.AddLooseStock(overage); err != nil {
+			return nil, fmt.Errorf("failed to add overage to loose stock: %w", err)
+		}
+	}
+	return order, nil
+}
+
+// storeOrder packs the part of an order of items not covered by loose items
+// and stores the order.
+func (ps *PackService) storeOrder(repo OrderRepository, items, loose int, opts CalcOptions) (*swagger.Order, error) {
+	result, err := ps.CalculatePacksWithOptions(items-loose, opts)
+	if err != nil {
+		return nil, err
+	}
+	if loose > 0 {
+		result.ItemsOrdered = utils.Ptr(items)
+		result.TotalItemsUsed = utils.Ptr(*result.TotalItemsUsed + loose)
+		result.LooseItemsUsed = utils.Ptr(loose)
+	}
+	order := newOrder(items, *result, opts)
+	if err := repo.CreateOrder(order); err != nil {
+		return nil, fmt.Errorf("failed to store order: %w", err)
+	}
+	return order, nil
+}
+
+// newOrder returns an unsaved order of items shipped as result, recording the
+// options it was packed with.
+func newOrder(items int, result swagger.CalcResult, opts CalcOptions) *swagger.Order {
+	now := time.Now().UTC()
+	order := &swagger.Order{
+		ItemsOrdered: items,
+		CreatedAt:    now,
+		Result:       result,
+		History:      &[]swagger.OrderEvent{{At: now, Type: swagger.Created, ItemsOrdered: utils.Ptr(items)}},
+	}
+	if opts.Objective != ObjectivePacks {
+		order.Objective = utils.Ptr(swagger.Objective(opts.Objective))
+	}
+	if opts.Customer != "" {
+		order.CustomerId = utils.Ptr(opts.Customer)
+	}
+	if len(opts.Attributes) > 0 {
+		order.Attributes = utils.Ptr(opts.Attributes)
+	}
+	return order
+}
+
+// orderOptions returns the options a stored order was packed with.
+func orderOptions(order *swagger.Order) CalcOptions {
+	var opts CalcOptions
+	if order.CustomerId != nil {
+		opts.Customer = *order.CustomerId
+	}
+	if order.Attributes != nil {
+		opts.Attributes = *order.Attributes
+	}
+	if order.Objective != nil && *order.Objective != swagger.Packs {
+		opts.Objective = CalcObjective(*order.Objective)
+	}
+	return opts
+}
+
+// GetOrder retrieves a stored order.
+func (ps *PackService) GetOrder(id string) (*swagger.Order, error) {
+	repo, err := ps.orderRepo()
+	if err != nil {
+		return nil, err
+	}
+	order, err := repo.GetOrder(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// ListOrders retrieves every stored order.
+func (ps *PackService) ListOrders() ([]swagger.Order, error) {
+	repo, err := ps.orderRepo()
+	if err != nil {
+		return nil, err
+	}
+	orders, err := repo.ListOrders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	if orders == nil {
+		orders = []swagger.Order{}
+	}
+	return orders, nil
+}
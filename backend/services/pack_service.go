@@ -3,11 +3,15 @@
 package services
 
 import (
+	"container/list"
+	"errors"
 	"fmt"
 	"math"
 	"ship_line/utils"
 	"sort"
 	"strconv"
+	"sync"
+	"time"
 
 	"ship_line/swagger"
 )
@@ -22,6 +26,15 @@ type PackRepository interface {
 // PackService provides methods for calculating pack distribution.
 type PackService struct {
 	repo PackRepository
+
+	// mu guards analyses, the cache of pack-set analyses keyed by version and
+	// range, and analysisOrder, its entries from most to least recently used.
+	mu            sync.Mutex
+	analyses      map[string]*list.Element
+	analysisOrder *list.List
+
+	// quoteMu serialises the acceptance of quotes.
+	quoteMu sync.Mutex
 }
 
 // NewPackService constructs a new PackService.
@@ -29,13 +42,151 @@ func NewPackService(repo PackRepository) *PackService {
 	return &PackService{repo: repo}
 }
 
+// dpThreshold is the largest order handled by the dynamic programming solver;
+// larger orders fall back to the greedy solver.
+// TODO: move to config
+const dpThreshold = 100000
+
+// MaxOrder is the largest order quantity a calculation accepts.
+// TODO: move to config
+const MaxOrder = 1000000000000
+
+// CalcMode selects how a calculation treats orders that cannot be packed exactly.
+type CalcMode string
+
+const (
+	// ModeDefault rounds the order up to the smallest achievable total.
+	ModeDefault CalcMode = ""
+	// ModeExact only accepts distributions that match the order exactly.
+	ModeExact CalcMode = "exact"
+	// ModeBackorder may ship less than ordered and backorder the rest when that
+	// is cheaper than shipping the overage.
+	ModeBackorder CalcMode = "backorder"
+)
+
+// CalcObjective selects what a calculation minimises.
+type CalcObjective string
+
+const (
+	// ObjectivePacks minimises the overage and then the number of packs.
+	ObjectivePacks CalcObjective = ""
+	// ObjectivePrice minimises the total price of the packs. It needs a pack
+	// catalogue with a price for every pack in effect.
+	ObjectivePrice CalcObjective = "price"
+	// ObjectiveShipping minimises the shipping cost of the parcels, according
+	// to the stored carrier rate cards, plus the packaging cost of the packs.
+	ObjectiveShipping CalcObjective = "shipping"
+)
+
+// CalcOptions tunes how CalculatePacksWithOptions chooses a distribution.
+// The zero value reproduces CalculatePacks.
+type CalcOptions struct {
+	Mode      CalcMode
+	Objective CalcObjective
+
+	// ShortTolerance is the largest number of items ModeBackorder may leave
+	// unshipped. Zero means no limit.
+	ShortTolerance int
+	// ShortPenalty is the cost of backordering one item, relative to shipping
+	// one item of overage. Zero means 1.
+	ShortPenalty float64
+	// Availability caps how many packs of each size can be used. Sizes missing
+	// from the map are unlimited. When the stock cannot cover the order, the
+	// available packs are shipped and the rest is backordered.
+	Availability map[int]int
+	// MaxWeight and MaxVolume limit the total gross weight (kg) and volume
+	// (cm³) of the shipment. Zero means no limit.
+	MaxWeight float64
+	MaxVolume float64
+	// At selects the packs in effect at this time, so that scheduled pack
+	// changes can be previewed. The zero value means now.
+	At time.Time
+	// Containers assigns the packs of the result to the stored container
+	// types and reports a manifest per container.
+	Containers bool
+	// Layout arranges the packs of the result in the stored container types
+	// that have dimensions and reports the position of every pack.
+	Layout bool
+	// Pallet is the ID of a container type with dimensions to load the packs
+	// of the result onto as pallets.
+	Pallet string
+	// Hierarchy is the ID of a packaging hierarchy to nest the result into.
+	// The packs used then follow from the nested units.
+	Hierarchy string
+	// BreakPacks lets the solver open a pack and ship part of its items loose
+	// when that costs less than the overage or shortfall of whole packs. The
+	// rest of the opened pack goes to the loose-item pool.
+	BreakPacks bool
+	// BreakCost is the cost of opening a pack and LooseItemCost the handling
+	// cost of every loose item shipped, both relative to one item of overage.
+	BreakCost     float64
+	LooseItemCost float64
+	// Sku names the product ordered, so that its quantity rule is enforced.
+	Sku string
+	// Customer is the ID of a customer whose packing policy is applied on top
+	// of the other options.
+	Customer string
+	// ExcludedSizes lists pack sizes the solver must not use.
+	ExcludedSizes []int
+	// MaxPacks caps the number of packs shipped. Zero means no limit.
+	MaxPacks int
+	// MaxOveragePercent caps the overage as a percentage of the order. Nil
+	// means no limit.
+	MaxOveragePercent *float64
+	// MinShares maps pack sizes to the smallest percentage of the order that
+	// must ship in packs of that size.
+	MinShares map[int]float64
+	// Attributes describe the order, such as its channel, for the conditions
+	// of packing rules.
+	Attributes map[string]string
+	// Rules are the packing rules to evaluate. Nil means the active rule set.
+	Rules []swagger.PolicyRule
+}
+
+// ExactFillError is returned in exact mode when no combination of packs adds up
+// to the order. Below and Above hold the nearest feasible quantities on either
+// side together with their distributions; Below is nil when none exists.
+type ExactFillError struct {
+	Order int
+	Below *swagger.CalcResult
+	Above *swagger.CalcResult
+}
+
+// Error implements the error interface.
+func (e *ExactFillError) Error() string {
+	return fmt.Sprintf("no exact pack combination found for order %d", e.Order)
+}
+
 // CalculatePacks calculates the pack distribution for a given order.
 // It returns a pointer to swagger.CalcResult.
 func (ps *PackService) CalculatePacks(order int) (*swagger.CalcResult, error) {
-	// TODO: move to config
-	const MaxOrder = 1000000000000
+	return ps.CalculatePacksWithOptions(order, CalcOptions{})
+}
+
+// CalculatePacksWithOptions calculates the pack distribution for a given order
+// using the behaviour selected by opts.
+func (ps *PackService) CalculatePacksWithOptions(order int, opts CalcOptions) (*swagger.CalcResult, error) {
+	result, _, err := ps.calculate(order, opts)
+	return result, err
+}
+
+// calculate implements CalculatePacksWithOptions. It also returns the catalogue
+// packs the result refers to, keyed by size, or nil without a catalogue.
+func (ps *PackService) calculate(order int, opts CalcOptions) (*swagger.CalcResult, map[int]swagger.Pack, error) {
 	if order > MaxOrder {
-		return nil, fmt.Errorf("order %d exceeds maximum allowed value of %d", order, MaxOrder)
+		return nil, nil, fmt.Errorf("order %d exceeds maximum allowed value of %d", order, MaxOrder)
+	}
+	if opts.Sku != "" {
+		items := map[string]int{opts.Sku: order}
+		if err := ps.checkQuantities(items, func(string) string { return "items" }); err != nil {
+			return nil, nil, err
+		}
+	}
+	if opts.Customer != "" {
+		var err error
+		if opts, err = ps.withCustomerPolicy(opts); err != nil {
+			return nil, nil, err
+		}
 	}
 	// Special case for zero order.
 	if order == 0 {
@@ -43,17 +194,128 @@ func (ps *PackService) CalculatePacks(order int) (*swagger.CalcResult, error) {
 			ItemsOrdered:   utils.Ptr(0),
 			TotalItemsUsed: utils.Ptr(0),
 			PacksUsed:      &map[string]int{},
-		}, nil
+		}, nil, nil
+	}
+	opts, _, err := ps.withRules(order, opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	packSizes, err := ps.repo.GetPackSizes()
+	packSizes, bySize, err := ps.loadPackSet(opts.At)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pack sizes: %w", err)
+		return nil, nil, fmt.Errorf("failed to get pack sizes: %w", err)
 	}
 	if len(packSizes) == 0 {
-		return nil, fmt.Errorf("no pack sizes configured")
+		return nil, nil, fmt.Errorf("no pack sizes configured")
+	}
+	if packSizes = withoutExcluded(packSizes, opts.ExcludedSizes); len(packSizes) == 0 {
+		return nil, nil, fmt.Errorf("%w: every pack size is excluded", ErrPolicy)
+	}
+
+	if opts.BreakPacks {
+		switch {
+		case opts.MaxWeight != 0 || opts.MaxVolume != 0:
+			return nil, nil, invalid("pack breaking cannot be combined with shipment limits")
+		case opts.Objective != ObjectivePacks:
+			return nil, nil, invalid("pack breaking cannot be combined with the price or shipping objective")
+		case opts.Hierarchy != "":
+			return nil, nil, invalid("pack breaking cannot be combined with a packaging hierarchy")
+		}
+	}
+
+	if len(opts.MinShares) > 0 {
+		switch {
+		case opts.MaxWeight != 0 || opts.MaxVolume != 0:
+			return nil, nil, invalid("pack shares cannot be combined with shipment limits")
+		case opts.Objective != ObjectivePacks:
+			return nil, nil, invalid("pack shares cannot be combined with the price or shipping objective")
+		}
 	}
 
+	var result *swagger.CalcResult
+	switch {
+	case len(opts.MinShares) > 0:
+		result, err = solveWithShares(order, packSizes, opts)
+	case opts.MaxWeight != 0 || opts.MaxVolume != 0:
+		result, err = solveWithinLimits(order, packSizes, bySize, opts)
+	case opts.Objective == ObjectivePrice:
+		result, err = solveCheapest(order, packSizes, bySize, opts)
+	case opts.Objective == ObjectiveShipping:
+		result, err = ps.solveShipping(order, packSizes, bySize, opts)
+	default:
+		result, err = solveWithOptions(order, packSizes, opts)
+	}
+	var fillErr *ExactFillError
+	if errors.As(err, &fillErr) {
+		describeResult(fillErr.Below, bySize)
+		describeResult(fillErr.Above, bySize)
+	}
+	if err != nil {
+		return describeResult(result, bySize), bySize, err
+	}
+	if err := checkPolicy(order, result, opts); err != nil {
+		return nil, bySize, err
+	}
+	if opts.Hierarchy != "" {
+		if result.Shipping != nil {
+			return nil, bySize, invalid("a packaging hierarchy cannot be combined with the shipping objective")
+		}
+		units, packs, err := ps.nestUnits(opts.Hierarchy, result, inStock(packSizes, opts.Availability))
+		if err != nil {
+			return nil, bySize, err
+		}
+		if err := checkPacks(order, packs, opts); err != nil {
+			return nil, bySize, err
+		}
+		result.Units = &units
+		result.PacksUsed = utils.Ptr(utils.ConvertMapKeys(packs))
+	}
+	if opts.Containers {
+		manifests, err := ps.planContainers(result, bySize)
+		if err != nil {
+			return nil, bySize, err
+		}
+		result.Containers = &manifests
+	}
+	if opts.Layout {
+		layouts, err := ps.layoutPacks(result, bySize)
+		if err != nil {
+			return nil, bySize, err
+		}
+		result.Layout = &layouts
+	}
+	if opts.Pallet != "" {
+		plan, err := ps.planPallets(opts.Pallet, result, bySize)
+		if err != nil {
+			return nil, bySize, err
+		}
+		result.Pallets = plan
+	}
+	return describeResult(result, bySize), bySize, nil
+}
+
+// describeResult adds the catalogue details of the packs used to result.
+func describeResult(result *swagger.CalcResult, bySize map[int]swagger.Pack) *swagger.CalcResult {
+	return withShipmentTotals(withOpenedRefs(withPackRefs(result, bySize), bySize), bySize)
+}
+
+// solveWithOptions dispatches a positive order for a known pack set to the
+// solver matching opts.
+func solveWithOptions(order int, packSizes []int, opts CalcOptions) (*swagger.CalcResult, error) {
+	switch {
+	case opts.BreakPacks, opts.MaxPacks > 0:
+		// Only the constrained solver weighs opening packs and counts them.
+	case opts.Mode == ModeDefault && opts.Availability == nil:
+		return calculatePacks(order, packSizes)
+	case opts.Mode == ModeExact && opts.Availability == nil:
+		return calculatePacksExact(order, sortedDesc(packSizes))
+	}
+	return calculatePacksConstrained(order, sortedDesc(packSizes), opts)
+}
+
+// calculatePacks rounds the order up to the best achievable total, choosing the
+// algorithm based on the order value.
+func calculatePacks(order int, packSizes []int) (*swagger.CalcResult, error) {
 	// Sort packSizes in descending order.
 	sort.Sort(sort.Reverse(sort.IntSlice(packSizes)))
 	smallestPack := packSizes[len(packSizes)-1]
@@ -67,18 +329,42 @@ func (ps *PackService) CalculatePacks(order int) (*swagger.CalcResult, error) {
 	}
 
 	// Choose algorithm based on order value.
-	// TODO: move to config
-	const dpThreshold = 100000
-	var result *swagger.CalcResult
 	if order <= dpThreshold {
-		result, err = calculatePacksDP(order, packSizes)
-	} else {
-		result, err = calculatePacksGreedy(order, packSizes)
+		return calculatePacksDP(order, packSizes)
 	}
-	if err != nil {
-		return nil, err
+	return calculatePacksGreedy(order, packSizes)
+}
+
+// calculatePacksExact returns a distribution matching the order exactly, or an
+// *ExactFillError describing the nearest feasible quantities.
+// packSizes must be sorted in descending order.
+func calculatePacksExact(order int, packSizes []int) (*swagger.CalcResult, error) {
+	table := residueTable(packSizes)
+	if packs, ok := solveExact(order, packSizes, table); ok {
+		return exactResult(order, packs), nil
+	}
+
+	below, above := nearestRepresentable(order, table)
+	fillErr := &ExactFillError{Order: order}
+	if below > 0 {
+		packs, _ := solveExact(below, packSizes, table)
+		fillErr.Below = exactResult(below, packs)
+	}
+	if above > 0 {
+		packs, _ := solveExact(above, packSizes, table)
+		fillErr.Above = exactResult(above, packs)
+	}
+	return nil, fillErr
+}
+
+// exactResult wraps a distribution that adds up to exactly quantity.
+func exactResult(quantity int, packs map[int]int) *swagger.CalcResult {
+	packsUsed := utils.ConvertMapKeys(packs)
+	return &swagger.CalcResult{
+		ItemsOrdered:   utils.Ptr(quantity),
+		TotalItemsUsed: utils.Ptr(quantity),
+		PacksUsed:      &packsUsed,
 	}
-	return result, nil
 }
 
 // calculatePacksDP implements a dynamic programming solution to determine the optimal
@@ -3,6 +3,8 @@ package services_test
 import (
 	"testing"
 
+	"ship_line/services"
+
 	"github.com/stretchr/testify/assert"
 )
 
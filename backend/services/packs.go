@@ -0,0 +1,377 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"ship_line/swagger"
+)
+
+var (
+	// ErrPackNotFound is returned when a pack ID does not exist.
+	ErrPackNotFound = errors.New("pack not found")
+	// ErrDuplicatePackSize is returned when another active pack with the
+	// requested size is in effect at an overlapping time.
+	ErrDuplicatePackSize = errors.New("an active pack with this size already exists")
+)
+
+// PackCatalog is implemented by repositories that store packs as individual
+// records. The PackRepository methods then act as a view over the active packs.
+type PackCatalog interface {
+	ListPacks() ([]swagger.Pack, error)
+	GetPack(id string) (*swagger.Pack, error)
+	CreatePack(pack *swagger.Pack) error
+	UpdatePack(pack *swagger.Pack) error
+	DeletePack(id string) error
+}
+
+// catalog returns the pack catalogue of the configured repository.
+func (ps *PackService) catalog() (PackCatalog, error) {
+	repo, ok := ps.repo.(PackCatalog)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// ListPacks retrieves every pack in the catalogue, active or not.
+func (ps *PackService) ListPacks() ([]swagger.Pack, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	packs, err := catalog.ListPacks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packs: %w", err)
+	}
+	if packs == nil {
+		packs = []swagger.Pack{}
+	}
+	return packs, nil
+}
+
+// GetPack retrieves a pack by ID.
+func (ps *PackService) GetPack(id string) (*swagger.Pack, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	pack, err := catalog.GetPack(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pack: %w", err)
+	}
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+	return pack, nil
+}
+
+// CreatePack validates payload and adds it to the catalogue.
+func (ps *PackService) CreatePack(payload swagger.PackPayload) (*swagger.Pack, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	pack, err := packFromPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUniqueSize(catalog, pack); err != nil {
+		return nil, err
+	}
+	if err := catalog.CreatePack(pack); err != nil {
+		return nil, fmt.Errorf("failed to store pack: %w", err)
+	}
+	return pack, nil
+}
+
+// UpdatePack replaces the pack with the given ID by payload.
+func (ps *PackService) UpdatePack(id string, payload swagger.PackPayload) (*swagger.Pack, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPackExists(catalog, id); err != nil {
+		return nil, err
+	}
+	pack, err := packFromPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	pack.Id = id
+	if err := checkUniqueSize(catalog, pack); err != nil {
+		return nil, err
+	}
+	if err := catalog.UpdatePack(pack); err != nil {
+		return nil, fmt.Errorf("failed to store pack: %w", err)
+	}
+	return pack, nil
+}
+
+// DeletePack removes a pack from the catalogue.
+func (ps *PackService) DeletePack(id string) error {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return err
+	}
+	if err := checkPackExists(catalog, id); err != nil {
+		return err
+	}
+	if err := catalog.DeletePack(id); err != nil {
+		return fmt.Errorf("failed to delete pack: %w", err)
+	}
+	return nil
+}
+
+// ActivatePack makes a deactivated pack available to the solver again.
+func (ps *PackService) ActivatePack(id string) (*swagger.Pack, error) {
+	return ps.setPackActive(id, true)
+}
+
+// DeactivatePack hides a pack from the solver without deleting it.
+func (ps *PackService) DeactivatePack(id string) (*swagger.Pack, error) {
+	return ps.setPackActive(id, false)
+}
+
+// setPackActive stores the active flag of a pack.
+func (ps *PackService) setPackActive(id string, active bool) (*swagger.Pack, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	pack, err := catalog.GetPack(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pack: %w", err)
+	}
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+	pack.Active = active
+	if err := checkUniqueSize(catalog, pack); err != nil {
+		return nil, err
+	}
+	if err := catalog.UpdatePack(pack); err != nil {
+		return nil, fmt.Errorf("failed to store pack: %w", err)
+	}
+	return pack, nil
+}
+
+// checkPackExists returns ErrPackNotFound if the catalogue has no pack with id.
+func checkPackExists(catalog PackCatalog, id string) error {
+	pack, err := catalog.GetPack(id)
+	if err != nil {
+		return fmt.Errorf("failed to get pack: %w", err)
+	}
+	if pack == nil {
+		return ErrPackNotFound
+	}
+	return nil
+}
+
+// packFromPayload validates payload and converts it into a Pack without an ID.
+func packFromPayload(payload swagger.PackPayload) (*swagger.Pack, error) {
+	if payload.Size <= 0 {
+		return nil, invalid("size must be a positive integer")
+	}
+	if payload.Gtin != nil && !validGTIN(*payload.Gtin) {
+		return nil, invalid("gtin must be 8, 12, 13 or 14 digits with a valid check digit")
+	}
+	if payload.Weight != nil && *payload.Weight < 0 {
+		return nil, invalid("weight must not be negative")
+	}
+	if payload.Cost != nil && *payload.Cost < 0 {
+		return nil, invalid("cost must not be negative")
+	}
+	if d := payload.Dimensions; d != nil && (d.Length <= 0 || d.Width <= 0 || d.Height <= 0) {
+		return nil, invalid("dimensions must be positive")
+	}
+	if payload.MaxStack != nil && *payload.MaxStack < 1 {
+		return nil, invalid("maxStack must be at least 1")
+	}
+	if err := validatePricing(payload); err != nil {
+		return nil, err
+	}
+	if payload.EffectiveFrom != nil && payload.EffectiveUntil != nil &&
+		!payload.EffectiveUntil.After(*payload.EffectiveFrom) {
+		return nil, invalid("effectiveUntil must be after effectiveFrom")
+	}
+	active := true
+	if payload.Active != nil {
+		active = *payload.Active
+	}
+	return &swagger.Pack{
+		Size:           payload.Size,
+		Active:         active,
+		Name:           payload.Name,
+		Sku:            payload.Sku,
+		Gtin:           payload.Gtin,
+		Dimensions:     payload.Dimensions,
+		Weight:         payload.Weight,
+		Upright:        payload.Upright,
+		MaxStack:       payload.MaxStack,
+		Cost:           payload.Cost,
+		EffectiveFrom:  payload.EffectiveFrom,
+		EffectiveUntil: payload.EffectiveUntil,
+		Price:          payload.Price,
+		PriceTiers:     payload.PriceTiers,
+		Currency:       payload.Currency,
+	}, nil
+}
+
+// validatePricing checks the price, volume tiers and currency of payload.
+func validatePricing(payload swagger.PackPayload) error {
+	if payload.Price == nil {
+		if payload.PriceTiers != nil {
+			return invalid("priceTiers require a price")
+		}
+		return nil
+	}
+	if *payload.Price < 0 {
+		return invalid("price must not be negative")
+	}
+	if payload.Currency == nil || !validCurrency(*payload.Currency) {
+		return invalid("currency must be a three-letter ISO 4217 code")
+	}
+	if payload.PriceTiers != nil {
+		for _, tier := range *payload.PriceTiers {
+			if tier.MinPacks < 1 || tier.UnitPrice < 0 {
+				return invalid("priceTiers need a positive minPacks and a non-negative unitPrice")
+			}
+		}
+	}
+	return nil
+}
+
+// validCurrency reports whether code looks like an ISO 4217 currency code.
+func validCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkUniqueSize rejects an active pack whose size is already used by another
+// active pack with an overlapping effective window, so that a size in a
+// distribution always maps to one pack. Scheduled replacements of a pack with
+// the same size are allowed as long as their windows do not overlap.
+func checkUniqueSize(catalog PackCatalog, pack *swagger.Pack) error {
+	if !pack.Active {
+		return nil
+	}
+	packs, err := catalog.ListPacks()
+	if err != nil {
+		return fmt.Errorf("failed to list packs: %w", err)
+	}
+	for _, other := range packs {
+		if other.Active && other.Size == pack.Size && other.Id != pack.Id && windowsOverlap(*pack, other) {
+			return ErrDuplicatePackSize
+		}
+	}
+	return nil
+}
+
+// windowsOverlap reports whether the effective windows of a and b intersect.
+func windowsOverlap(a, b swagger.Pack) bool {
+	startsBeforeEnd := func(from, until *time.Time) bool {
+		return from == nil || until == nil || from.Before(*until)
+	}
+	return startsBeforeEnd(a.EffectiveFrom, b.EffectiveUntil) &&
+		startsBeforeEnd(b.EffectiveFrom, a.EffectiveUntil)
+}
+
+// validGTIN reports whether s is a GTIN-8, -12, -13 or -14 with a correct
+// check digit.
+func validGTIN(s string) bool {
+	switch len(s) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	sum := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if i == len(s)-1 {
+			continue
+		}
+		// Weights alternate 3, 1, 3, ... starting next to the check digit.
+		if (len(s)-1-i)%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10-sum%10)%10 == int(s[len(s)-1]-'0')
+}
+
+// loadPackSet returns the pack sizes the solver may use at the given time;
+// the zero time means now. With a catalogue it also returns the pack in effect
+// for every size so results can reference packs by ID; otherwise the map is
+// nil and the time is irrelevant, as bare pack sizes have no schedule.
+func (ps *PackService) loadPackSet(at time.Time) ([]int, map[int]swagger.Pack, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		sizes, err := ps.repo.GetPackSizes()
+		return sizes, nil, err
+	}
+	packs, err := catalog.ListPacks()
+	if err != nil {
+		return nil, nil, err
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	sizes, bySize := packSetAt(packs, at)
+	return sizes, bySize, nil
+}
+
+// packSetAt returns the sizes of the catalogue packs in effect at the given
+// time together with the pack in effect for every size.
+func packSetAt(packs []swagger.Pack, at time.Time) ([]int, map[int]swagger.Pack) {
+	var sizes []int
+	bySize := make(map[int]swagger.Pack)
+	for _, pack := range packs {
+		if !pack.InEffect(at) {
+			continue
+		}
+		if _, ok := bySize[pack.Size]; !ok {
+			sizes = append(sizes, pack.Size)
+			bySize[pack.Size] = pack
+		}
+	}
+	return sizes, bySize
+}
+
+// withPackRefs lists the packs of result by size, largest first, adding the
+// catalogue ID of each pack. Results of repositories without a catalogue
+// (bySize is nil) are returned unchanged.
+func withPackRefs(result *swagger.CalcResult, bySize map[int]swagger.Pack) *swagger.CalcResult {
+	if bySize == nil || result == nil || result.PacksUsed == nil {
+		return result
+	}
+	usage := make([]swagger.PackUsage, 0, len(*result.PacksUsed))
+	for key, count := range *result.PacksUsed {
+		size, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		ref := swagger.PackUsage{Size: size, Count: count}
+		if pack, ok := bySize[size]; ok {
+			id := pack.Id
+			ref.PackId = &id
+		}
+		usage = append(usage, ref)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Size > usage[j].Size })
+	result.Packs = &usage
+	return result
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// mockCatalog is an in-memory PackCatalog whose pack sizes are its active packs.
+type mockCatalog struct {
+	mockPackRepo
+	packs []swagger.Pack
+}
+
+func (m *mockCatalog) GetPackSizes() ([]int, error) {
+	var sizes []int
+	for _, pack := range m.packs {
+		if pack.Active {
+			sizes = append(sizes, pack.Size)
+		}
+	}
+	return sizes, nil
+}
+
+func (m *mockCatalog) ListPacks() ([]swagger.Pack, error) {
+	return append([]swagger.Pack{}, m.packs...), nil
+}
+
+func (m *mockCatalog) GetPack(id string) (*swagger.Pack, error) {
+	for _, pack := range m.packs {
+		if pack.Id == id {
+			return &pack, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockCatalog) CreatePack(pack *swagger.Pack) error {
+	pack.Id = strconv.Itoa(len(m.packs) + 1)
+	m.packs = append(m.packs, *pack)
+	return nil
+}
+
+func (m *mockCatalog) UpdatePack(pack *swagger.Pack) error {
+	for i := range m.packs {
+		if m.packs[i].Id == pack.Id {
+			m.packs[i] = *pack
+		}
+	}
+	return nil
+}
+
+func (m *mockCatalog) DeletePack(id string) error {
+	for i := range m.packs {
+		if m.packs[i].Id == id {
+			m.packs = append(m.packs[:i], m.packs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestPackCatalog(t *testing.T) {
+	ps := NewPackService(&mockCatalog{})
+
+	small, err := ps.CreatePack(swagger.PackPayload{Size: 250, Gtin: utils.Ptr("4006381333931")})
+	require.NoError(t, err)
+	assert.True(t, small.Active)
+	_, err = ps.CreatePack(swagger.PackPayload{Size: 500})
+	require.NoError(t, err)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := ps.CreatePack(swagger.PackPayload{Size: 0})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.CreatePack(swagger.PackPayload{Size: 750, Gtin: utils.Ptr("4006381333932")})
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("DuplicateSize", func(t *testing.T) {
+		_, err := ps.CreatePack(swagger.PackPayload{Size: 250})
+		assert.ErrorIs(t, err, ErrDuplicatePackSize)
+		// An inactive pack may share the size.
+		_, err = ps.CreatePack(swagger.PackPayload{Size: 250, Active: utils.Ptr(false)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("CalcReferencesPacks", func(t *testing.T) {
+		result, err := ps.CalculatePacks(501)
+		require.NoError(t, err)
+		require.NotNil(t, result.Packs)
+		assert.Equal(t, []swagger.PackUsage{
+			{PackId: utils.Ptr("2"), Size: 500, Count: 1},
+			{PackId: utils.Ptr("1"), Size: 250, Count: 1},
+		}, *result.Packs)
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		updated, err := ps.UpdatePack(small.Id, swagger.PackPayload{Size: 300})
+		require.NoError(t, err)
+		assert.Equal(t, 300, updated.Size)
+		_, err = ps.UpdatePack("99", swagger.PackPayload{Size: 300})
+		assert.ErrorIs(t, err, ErrPackNotFound)
+
+		require.NoError(t, ps.DeletePack(small.Id))
+		assert.ErrorIs(t, ps.DeletePack(small.Id), ErrPackNotFound)
+	})
+
+	t.Run("NotSupported", func(t *testing.T) {
+		_, err := NewPackService(&mockPackRepo{}).ListPacks()
+		assert.ErrorIs(t, err, ErrNotSupported)
+	})
+}
+
+func TestValidGTIN(t *testing.T) {
+	assert.True(t, validGTIN("4006381333931"))
+	assert.True(t, validGTIN("96385074"))
+	assert.False(t, validGTIN("4006381333932"))
+	assert.False(t, validGTIN("40063813339a1"))
+	assert.False(t, validGTIN("123"))
+}
+
+func TestPackSchedule(t *testing.T) {
+	switchover := time.Now().Add(14 * 24 * time.Hour)
+	ps := NewPackService(&mockCatalog{})
+
+	_, err := ps.CreatePack(swagger.PackPayload{Size: 250})
+	require.NoError(t, err)
+	old, err := ps.CreatePack(swagger.PackPayload{Size: 500, EffectiveUntil: &switchover})
+	require.NoError(t, err)
+	_, err = ps.CreatePack(swagger.PackPayload{Size: 600, EffectiveFrom: &switchover})
+	require.NoError(t, err)
+
+	t.Run("Now", func(t *testing.T) {
+		result, err := ps.CalculatePacks(501)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"500": 1, "250": 1}, *result.PacksUsed)
+	})
+
+	t.Run("AfterSwitchover", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(501, CalcOptions{At: switchover})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"600": 1}, *result.PacksUsed)
+	})
+
+	t.Run("ReplacementWithSameSize", func(t *testing.T) {
+		// A successor may share the size once the windows do not overlap.
+		_, err := ps.CreatePack(swagger.PackPayload{Size: 500, EffectiveFrom: &switchover})
+		assert.NoError(t, err)
+		_, err = ps.CreatePack(swagger.PackPayload{Size: 500})
+		assert.ErrorIs(t, err, ErrDuplicatePackSize)
+	})
+
+	t.Run("InvalidWindow", func(t *testing.T) {
+		_, err := ps.CreatePack(swagger.PackPayload{Size: 750, EffectiveFrom: &switchover, EffectiveUntil: &switchover})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("Deactivate", func(t *testing.T) {
+		pack, err := ps.DeactivatePack(old.Id)
+		require.NoError(t, err)
+		assert.False(t, pack.Active)
+		result, err := ps.CalculatePacks(501)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"250": 3}, *result.PacksUsed)
+
+		_, err = ps.ActivatePack(old.Id)
+		require.NoError(t, err)
+		_, err = ps.ActivatePack("99")
+		assert.ErrorIs(t, err, ErrPackNotFound)
+	})
+}
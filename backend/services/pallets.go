@@ -0,0 +1,239 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// MaxPalletLayers is the largest number of layers a distribution may have to
+// be planned into pallets.
+const MaxPalletLayers = 20000
+
+// pallet is a pallet being loaded by planPallets.
+type pallet struct {
+	layers []swagger.PalletLayer
+	height float64
+	weight float64
+	// allowance is how many more layers may be put on the stack before a
+	// layer below exceeds the MaxStack of its pack.
+	allowance int
+	// closed pallets take no more layers, because their top layer is partial
+	// or no layer fits any more.
+	closed bool
+}
+
+// palletLayer is a layer waiting to be loaded.
+type palletLayer struct {
+	pack   swagger.Pack
+	packs  int
+	weight float64
+}
+
+// planPallets loads the packs of result onto pallets of the container type
+// with the given ID and returns the plan.
+//
+// The pallet type's dimensions give the footprint and the largest stack
+// height, and MaxWeight the largest gross weight. Packs keep their height
+// vertical and every layer holds packs of one size, laid out in the two-block
+// pattern that fits the most packs on the footprint. Layers are loaded
+// heaviest pack first onto the first pallet where they fit within the height,
+// weight and stack limits; the partial layer of every size is loaded last and
+// always ends its pallet.
+func (ps *PackService) planPallets(id string, result *swagger.CalcResult, bySize map[int]swagger.Pack) (*swagger.PalletPlan, error) {
+	if bySize == nil {
+		return nil, ErrNotSupported
+	}
+	kind, err := ps.GetContainerType(id)
+	if err != nil {
+		return nil, err
+	}
+	if kind.Dimensions == nil {
+		return nil, invalid(fmt.Sprintf("container type %s has no dimensions", kind.Id))
+	}
+	footprint := kind.Dimensions
+
+	counts, err := utils.ParseMapKeys(*result.PacksUsed)
+	if err != nil {
+		return nil, err
+	}
+	var packs []swagger.Pack
+	for size, count := range counts {
+		if count > 0 {
+			packs = append(packs, bySize[size])
+		}
+	}
+	sort.Slice(packs, func(i, j int) bool {
+		wi, wj := packWeight(packs[i]), packWeight(packs[j])
+		if wi != wj {
+			return wi > wj
+		}
+		return packs[i].Size > packs[j].Size
+	})
+
+	plan := &swagger.PalletPlan{ContainerTypeId: kind.Id, Name: kind.Name, Patterns: []swagger.LayerPattern{}}
+	var full, partial []palletLayer
+	minHeight := math.Inf(1)
+	for _, pack := range packs {
+		if pack.Dimensions == nil {
+			return nil, invalid(fmt.Sprintf("pack %s (size %d) has no dimensions", pack.Id, pack.Size))
+		}
+		if kind.MaxWeight != nil && pack.Weight == nil {
+			return nil, invalid(fmt.Sprintf("pack %s (size %d) has no weight", pack.Id, pack.Size))
+		}
+		blocks, perLayer := layerPattern(footprint.Length, footprint.Width, pack.Dimensions.Length, pack.Dimensions.Width)
+		if kind.MaxWeight != nil && *pack.Weight > 0 {
+			perLayer = min(perLayer, int((payloadLimit(*kind)+limitTolerance) / *pack.Weight))
+		}
+		if perLayer == 0 || pack.Dimensions.Height > footprint.Height+limitTolerance {
+			return nil, fmt.Errorf("%w: pack size %d", ErrNoContainer, pack.Size)
+		}
+		plan.Patterns = append(plan.Patterns, swagger.LayerPattern{
+			PackId: pack.Id, Size: pack.Size, PacksPerLayer: perLayer, Blocks: blocks,
+		})
+		minHeight = min(minHeight, pack.Dimensions.Height)
+
+		count := counts[pack.Size]
+		if len(full)+len(partial)+count/perLayer > MaxPalletLayers {
+			return nil, invalid(fmt.Sprintf("a pallet plan is limited to %d layers", MaxPalletLayers))
+		}
+		for range count / perLayer {
+			full = append(full, palletLayer{pack: pack, packs: perLayer, weight: packWeight(pack) * float64(perLayer)})
+		}
+		if rest := count % perLayer; rest > 0 {
+			partial = append(partial, palletLayer{pack: pack, packs: rest, weight: packWeight(pack) * float64(rest)})
+		}
+	}
+
+	var pallets []*pallet
+	var open []*pallet
+	for i, layer := range append(full, partial...) {
+		last := i >= len(full)
+		target := -1
+		for j, p := range open {
+			if p.takes(*kind, layer) {
+				target = j
+				break
+			}
+		}
+		if target == -1 {
+			if len(pallets) == MaxContainers {
+				return nil, invalid(fmt.Sprintf("the distribution needs more than %d pallets", MaxContainers))
+			}
+			p := &pallet{allowance: math.MaxInt}
+			pallets = append(pallets, p)
+			open = append(open, p)
+			target = len(open) - 1
+		}
+		p := open[target]
+		p.load(layer)
+		if last || p.allowance == 0 || footprint.Height-p.height < minHeight-limitTolerance {
+			p.closed = true
+			open = append(open[:target], open[target+1:]...)
+		}
+	}
+
+	plan.Pallets = make([]swagger.PalletLoad, 0, len(pallets))
+	for _, p := range pallets {
+		load := swagger.PalletLoad{Height: math.Round(p.height*1000) / 1000, Layers: p.layers}
+		if weighed(p.layers, bySize) {
+			tare := 0.0
+			if kind.TareWeight != nil {
+				tare = *kind.TareWeight
+			}
+			load.Weight = utils.Ptr(math.Round((p.weight+tare)*1000) / 1000)
+		}
+		plan.Pallets = append(plan.Pallets, load)
+	}
+	return plan, nil
+}
+
+// takes reports whether layer can be put on top of p.
+func (p *pallet) takes(kind swagger.ContainerType, layer palletLayer) bool {
+	return !p.closed &&
+		p.height+layer.pack.Dimensions.Height <= kind.Dimensions.Height+limitTolerance &&
+		(kind.MaxWeight == nil || p.weight+layer.weight <= payloadLimit(kind)+limitTolerance) &&
+		(len(p.layers) == 0 || p.allowance >= 1)
+}
+
+// load puts layer on top of p.
+func (p *pallet) load(layer palletLayer) {
+	p.layers = append(p.layers, swagger.PalletLayer{
+		PackId: layer.pack.Id,
+		Size:   layer.pack.Size,
+		Packs:  layer.packs,
+		Z:      math.Round(p.height*1000) / 1000,
+	})
+	p.height += layer.pack.Dimensions.Height
+	p.weight += layer.weight
+	stack := math.MaxInt
+	if layer.pack.MaxStack != nil {
+		stack = *layer.pack.MaxStack
+	}
+	p.allowance = min(p.allowance-1, stack-1)
+}
+
+// layerPattern returns the blocks of the two-block pattern that fits the most
+// packs of footprint l × w on a pallet of footprint length × width, and the
+// number of packs it holds. The pallet is split across its length or its width
+// into a block of packs turned one way and a block of packs turned the other;
+// an empty block gives the uniform patterns.
+func layerPattern(length, width, l, w float64) ([]swagger.LayerBlock, int) {
+	fit := func(space, extent float64) int {
+		return int((space + limitTolerance) / extent)
+	}
+	var best []swagger.LayerBlock
+	bestCount := 0
+	consider := func(blocks ...swagger.LayerBlock) {
+		count := 0
+		var kept []swagger.LayerBlock
+		for _, block := range blocks {
+			if block.Columns > 0 && block.Rows > 0 {
+				count += block.Columns * block.Rows
+				kept = append(kept, block)
+			}
+		}
+		if count > bestCount {
+			best, bestCount = kept, count
+		}
+	}
+	for i := 0; i <= fit(length, l); i++ {
+		split := float64(i) * l
+		consider(
+			swagger.LayerBlock{Columns: i, Rows: fit(width, w), Length: l, Width: w},
+			swagger.LayerBlock{X: split, Columns: fit(length-split, w), Rows: fit(width, l), Length: w, Width: l},
+		)
+	}
+	for j := 0; j <= fit(width, w); j++ {
+		split := float64(j) * w
+		consider(
+			swagger.LayerBlock{Columns: fit(length, l), Rows: j, Length: l, Width: w},
+			swagger.LayerBlock{Y: split, Columns: fit(length, w), Rows: fit(width-split, l), Length: w, Width: l},
+		)
+	}
+	if best == nil {
+		best = []swagger.LayerBlock{}
+	}
+	return best, bestCount
+}
+
+// packWeight returns the weight of pack, 0 if unknown.
+func packWeight(pack swagger.Pack) float64 {
+	if pack.Weight == nil {
+		return 0
+	}
+	return *pack.Weight
+}
+
+// weighed reports whether every pack in layers has a weight.
+func weighed(layers []swagger.PalletLayer, bySize map[int]swagger.Pack) bool {
+	for _, layer := range layers {
+		if bySize[layer.Size].Weight == nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+func TestPlanPallets(t *testing.T) {
+	repo := &mockContainerCatalog{}
+	ps := NewPackService(repo)
+	carton := swagger.PackPayload{
+		Size:       500,
+		Weight:     utils.Ptr(10.0),
+		Dimensions: &swagger.PackDimensions{Length: 40, Width: 30, Height: 25},
+	}
+	_, err := ps.CreatePack(carton)
+	require.NoError(t, err)
+	_, err = ps.CreateContainerType(swagger.ContainerTypePayload{
+		Name:       "Euro pallet",
+		Dimensions: &swagger.PackDimensions{Length: 120, Width: 80, Height: 100},
+		MaxWeight:  utils.Ptr(500.0),
+		TareWeight: utils.Ptr(25.0),
+	})
+	require.NoError(t, err)
+
+	t.Run("HeightLimit", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(20000, CalcOptions{Pallet: "1"})
+		require.NoError(t, err)
+		plan := result.Pallets
+		require.NotNil(t, plan)
+		assert.Equal(t, []swagger.LayerPattern{{
+			PackId: "1", Size: 500, PacksPerLayer: 8,
+			Blocks: []swagger.LayerBlock{{Columns: 4, Rows: 2, Length: 30, Width: 40}},
+		}}, plan.Patterns)
+		require.Len(t, plan.Pallets, 2)
+		assert.Len(t, plan.Pallets[0].Layers, 4)
+		assert.Equal(t, 100.0, plan.Pallets[0].Height)
+		assert.Equal(t, 345.0, *plan.Pallets[0].Weight)
+		assert.Equal(t, 75.0, plan.Pallets[0].Layers[3].Z)
+		assert.Equal(t, 105.0, *plan.Pallets[1].Weight)
+	})
+
+	t.Run("StackLimitAndPartialLayer", func(t *testing.T) {
+		carton.MaxStack = utils.Ptr(2)
+		_, err := ps.UpdatePack("1", carton)
+		require.NoError(t, err)
+		result, err := ps.CalculatePacksWithOptions(21000, CalcOptions{Pallet: "1"})
+		require.NoError(t, err)
+		var layers []int
+		for _, load := range result.Pallets.Pallets {
+			layers = append(layers, len(load.Layers))
+		}
+		assert.Equal(t, []int{2, 2, 2}, layers)
+		assert.Equal(t, 2, result.Pallets.Pallets[2].Layers[1].Packs)
+	})
+
+	t.Run("WeightLimit", func(t *testing.T) {
+		carton.MaxStack = nil
+		carton.Weight = utils.Ptr(100.0)
+		_, err := ps.UpdatePack("1", carton)
+		require.NoError(t, err)
+		result, err := ps.CalculatePacksWithOptions(2500, CalcOptions{Pallet: "1"})
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.Pallets.Patterns[0].PacksPerLayer)
+		require.Len(t, result.Pallets.Pallets, 2)
+		assert.Equal(t, 425.0, *result.Pallets.Pallets[0].Weight)
+	})
+
+	t.Run("TooTall", func(t *testing.T) {
+		carton.Dimensions.Height = 120
+		_, err := ps.UpdatePack("1", carton)
+		require.NoError(t, err)
+		_, err = ps.CalculatePacksWithOptions(500, CalcOptions{Pallet: "1"})
+		assert.ErrorIs(t, err, ErrNoContainer)
+	})
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ErrPolicy is returned when no distribution of the order satisfies the
+// packing policy of the calculation.
+var ErrPolicy = errors.New("no pack distribution satisfies the packing policy")
+
+// validPolicy validates policy and returns a copy that shares no slices with
+// it.
+func validPolicy(policy swagger.PackingPolicy) (*swagger.PackingPolicy, error) {
+	if policy.ForbiddenPackSizes != nil {
+		for _, size := range *policy.ForbiddenPackSizes {
+			if size <= 0 {
+				return nil, invalid("forbidden pack sizes must be positive")
+			}
+		}
+		policy.ForbiddenPackSizes = utils.Ptr(append([]int(nil), *policy.ForbiddenPackSizes...))
+	}
+	if policy.MaxOveragePercent != nil && *policy.MaxOveragePercent < 0 {
+		return nil, invalid("maxOveragePercent must not be negative")
+	}
+	if policy.MaxPacks != nil && *policy.MaxPacks < 1 {
+		return nil, invalid("maxPacks must be positive")
+	}
+	if policy.MinPackShares != nil {
+		total := 0.0
+		for _, share := range *policy.MinPackShares {
+			if share.Size <= 0 || share.Percent <= 0 || share.Percent > 100 {
+				return nil, invalid("pack shares need a positive size and a percent between 0 and 100")
+			}
+			total += share.Percent
+		}
+		if total > 100 {
+			return nil, invalid("pack shares must not add up to more than 100 percent")
+		}
+		policy.MinPackShares = utils.Ptr(append([]swagger.PackShare(nil), *policy.MinPackShares...))
+	}
+	if policy.Objective != nil {
+		switch *policy.Objective {
+		case swagger.Packs, swagger.Price, swagger.Shipping:
+		default:
+			return nil, invalid("objective must be packs, price or shipping")
+		}
+	}
+	return &policy, nil
+}
+
+// withPolicy returns opts constrained by policy. The preferred objective
+// applies only when opts asks for none, while the other constraints tighten
+// those already in opts.
+func withPolicy(opts CalcOptions, policy swagger.PackingPolicy) CalcOptions {
+	if policy.ForbiddenPackSizes != nil {
+		opts.ExcludedSizes = append(slices.Clone(opts.ExcludedSizes), *policy.ForbiddenPackSizes...)
+	}
+	if policy.MaxOveragePercent != nil && (opts.MaxOveragePercent == nil || *policy.MaxOveragePercent < *opts.MaxOveragePercent) {
+		opts.MaxOveragePercent = policy.MaxOveragePercent
+	}
+	if policy.MaxPacks != nil && (opts.MaxPacks == 0 || *policy.MaxPacks < opts.MaxPacks) {
+		opts.MaxPacks = *policy.MaxPacks
+	}
+	if policy.MinPackShares != nil {
+		shares := utils.CopyMap(opts.MinShares)
+		if shares == nil {
+			shares = make(map[int]float64)
+		}
+		for _, share := range *policy.MinPackShares {
+			shares[share.Size] = max(shares[share.Size], share.Percent)
+		}
+		opts.MinShares = shares
+	}
+	if policy.Objective != nil && opts.Objective == ObjectivePacks {
+		switch *policy.Objective {
+		case swagger.Price:
+			opts.Objective = ObjectivePrice
+		case swagger.Shipping:
+			opts.Objective = ObjectiveShipping
+		}
+	}
+	return opts
+}
+
+// withoutExcluded returns the pack sizes that are not excluded.
+func withoutExcluded(packSizes, excluded []int) []int {
+	if len(excluded) == 0 {
+		return packSizes
+	}
+	var allowed []int
+	for _, size := range packSizes {
+		if !slices.Contains(excluded, size) {
+			allowed = append(allowed, size)
+		}
+	}
+	return allowed
+}
+
+// checkPolicy returns an error wrapping ErrPolicy if result ships more packs
+// or more overage than opts allows.
+func checkPolicy(order int, result *swagger.CalcResult, opts CalcOptions) error {
+	if opts.MaxPacks > 0 && result.PacksUsed != nil {
+		packs := 0
+		for _, count := range *result.PacksUsed {
+			packs += count
+		}
+		if packs > opts.MaxPacks {
+			return fmt.Errorf("%w: %d packs are needed but at most %d are allowed", ErrPolicy, packs, opts.MaxPacks)
+		}
+	}
+	if opts.MaxOveragePercent != nil && order > 0 {
+		overage := *result.TotalItemsUsed - order
+		if percent := float64(overage) * 100 / float64(order); percent > *opts.MaxOveragePercent {
+			return fmt.Errorf("%w: an overage of %d items (%.4g%%) exceeds the %.4g%% allowed",
+				ErrPolicy, overage, percent, *opts.MaxOveragePercent)
+		}
+	}
+	return nil
+}
+
+// packLimits bounds the distributions a solver may choose: at most maxTotal
+// items in at most maxPacks packs. math.MaxInt stands for no bound.
+type packLimits struct {
+	maxTotal, maxPacks int
+}
+
+// noLimits leaves every distribution allowed.
+var noLimits = packLimits{maxTotal: math.MaxInt, maxPacks: math.MaxInt}
+
+// policyLimits returns the limits the overage and pack caps of opts put on
+// the distributions for order, so that solvers optimising something other
+// than the overage honour them instead of leaving checkPolicy to reject their
+// choice.
+func policyLimits(order int, opts CalcOptions) packLimits {
+	limits := noLimits
+	if opts.MaxPacks > 0 {
+		limits.maxPacks = opts.MaxPacks
+	}
+	if opts.MaxOveragePercent == nil || order <= 0 {
+		return limits
+	}
+	percent := *opts.MaxOveragePercent
+	allowed := float64(order) * percent / 100
+	if allowed >= MaxOrder {
+		return limits
+	}
+	// Step from the rounded bound to the overage checkPolicy just accepts.
+	overage := int(allowed)
+	for float64(overage+1)*100/float64(order) <= percent {
+		overage++
+	}
+	for overage > 0 && float64(overage)*100/float64(order) > percent {
+		overage--
+	}
+	limits.maxTotal = order + overage
+	return limits
+}
+
+// allow reports whether packs stays within the limits.
+func (l packLimits) allow(packs map[int]int) bool {
+	total := 0
+	for size, count := range packs {
+		total += size * count
+	}
+	return total <= l.maxTotal && countPacks(packs) <= l.maxPacks
+}
+
+// without returns the limits left for the rest of a distribution once packs
+// are committed, or ok false if packs already exceed them.
+func (l packLimits) without(packs map[int]int) (rest packLimits, ok bool) {
+	if !l.allow(packs) {
+		return l, false
+	}
+	for size, count := range packs {
+		if l.maxTotal != math.MaxInt {
+			l.maxTotal -= size * count
+		}
+		if l.maxPacks != math.MaxInt {
+			l.maxPacks -= count
+		}
+	}
+	return l, true
+}
+
+// resolvePolicy applies the policy of the customer of opts and the packing
+// rules matching an order of items to opts, as calculate does.
+func (ps *PackService) resolvePolicy(items int, opts CalcOptions) (CalcOptions, error) {
+	if opts.Customer != "" {
+		var err error
+		if opts, err = ps.withCustomerPolicy(opts); err != nil {
+			return opts, err
+		}
+	}
+	opts, _, err := ps.withRules(items, opts)
+	return opts, err
+}
+
+// checkPacks checks packs composed outside the solvers, such as nested or
+// amended distributions, against the availability caps, pack shares and
+// packing policy of opts.
+func checkPacks(order int, packs map[int]int, opts CalcOptions) error {
+	for size, count := range packs {
+		if available, capped := opts.Availability[size]; capped && count > available {
+			return fmt.Errorf("%w: %d %d-packs are needed but %d are available",
+				ErrPolicy, count, size, available)
+		}
+	}
+	for size, percent := range opts.MinShares {
+		items := int(math.Ceil(float64(order)*percent/100 - limitTolerance))
+		if packs[size]*size < items {
+			return fmt.Errorf("%w: a share of %.4g%% needs %d items in %d-packs but %d are shipped",
+				ErrPolicy, percent, items, size, packs[size]*size)
+		}
+	}
+	total := 0
+	for size, count := range packs {
+		total += size * count
+	}
+	return checkPolicy(order, &swagger.CalcResult{
+		TotalItemsUsed: &total,
+		PacksUsed:      utils.Ptr(utils.ConvertMapKeys(packs)),
+	}, opts)
+}
+
+// solveWithShares ships the fewest packs of every size that cover its share
+// of the order and solves the rest with solveWithOptions. Every distribution
+// that meets the shares holds at least those packs, and the rest of it covers
+// the rest of the order, so nothing better is lost by committing them first.
+func solveWithShares(order int, packSizes []int, opts CalcOptions) (*swagger.CalcResult, error) {
+	reserved := make(map[int]int)
+	reservedItems, reservedPacks := 0, 0
+	caps := utils.CopyMap(opts.Availability)
+	for size, percent := range opts.MinShares {
+		if !slices.Contains(packSizes, size) {
+			return nil, fmt.Errorf("%w: a share of %.4g%% needs %d-packs, which are not available", ErrPolicy, percent, size)
+		}
+		items := int(math.Ceil(float64(order)*percent/100 - limitTolerance))
+		count := (items + size - 1) / size
+		if available, capped := caps[size]; capped {
+			if available < count {
+				return nil, fmt.Errorf("%w: a share of %.4g%% needs %d %d-packs but %d are available",
+					ErrPolicy, percent, count, size, available)
+			}
+			caps[size] = available - count
+		}
+		reserved[size] = count
+		reservedItems += count * size
+		reservedPacks += count
+	}
+
+	rest := order - reservedItems
+	if rest <= 0 {
+		return distributionResult(order, reserved), nil
+	}
+	if opts.MaxPacks > 0 {
+		if reservedPacks >= opts.MaxPacks {
+			return nil, fmt.Errorf("%w: the pack shares alone need %d packs but at most %d are allowed",
+				ErrPolicy, reservedPacks, opts.MaxPacks)
+		}
+		opts.MaxPacks -= reservedPacks
+	}
+	if opts.Availability != nil {
+		opts.Availability = caps
+	}
+	result, err := solveWithOptions(rest, packSizes, opts)
+	var fillErr *ExactFillError
+	if errors.As(err, &fillErr) {
+		fillErr.Order = order
+		withReserved(fillErr.Below, reserved)
+		withReserved(fillErr.Above, reserved)
+	}
+	return withReserved(result, reserved), err
+}
+
+// withReserved adds the reserved packs to result, a distribution for the rest
+// of the order, and returns it.
+func withReserved(result *swagger.CalcResult, reserved map[int]int) *swagger.CalcResult {
+	if result == nil {
+		return nil
+	}
+	packs := map[string]int{}
+	if result.PacksUsed != nil {
+		packs = utils.CopyMap(*result.PacksUsed)
+	}
+	items := 0
+	for size, count := range reserved {
+		packs[strconv.Itoa(size)] += count
+		items += size * count
+	}
+	result.PacksUsed = &packs
+	result.ItemsOrdered = utils.Ptr(*result.ItemsOrdered + items)
+	result.TotalItemsUsed = utils.Ptr(*result.TotalItemsUsed + items)
+	return result
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+const (
+	// MaxPreviewSamples caps how many sample orders a dry run evaluates.
+	MaxPreviewSamples = 100
+)
+
+// defaultPreviewSamples are evaluated when a dry run names no sample orders.
+var defaultPreviewSamples = []int{1, 250, 251, 501, 12001, 100001}
+
+// PreviewOptions configures the sample orders of a dry run.
+type PreviewOptions struct {
+	// Samples are the order quantities to compare; empty means the defaults.
+	Samples []int
+	// Availability caps the packs per size, as in CalcOptions.
+	Availability map[int]int
+}
+
+// PreviewUpdatePackSizes validates an update like UpdatePackSizes and reports
+// how the sample orders would be packed before and after it, without storing
+// anything. Updates merge with the stored sizes, so the preview does the same.
+func (ps *PackService) PreviewUpdatePackSizes(newSizes []int, opts PreviewOptions) (*swagger.PackSizesPreview, error) {
+	if len(newSizes) == 0 {
+		return nil, invalid("pack sizes cannot be empty")
+	}
+	for _, size := range newSizes {
+		if size <= 0 {
+			return nil, invalid(fmt.Sprintf("invalid pack size: %d (must be positive)", size))
+		}
+	}
+	before, _, err := ps.loadPackSet(time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pack sizes: %w", err)
+	}
+	after := append(append([]int{}, before...), newSizes...)
+	return previewPackSizes(before, after, opts)
+}
+
+// PreviewDeletePackSize reports how the sample orders would be packed before
+// and after removing size, without storing anything.
+func (ps *PackService) PreviewDeletePackSize(size int, opts PreviewOptions) (*swagger.PackSizesPreview, error) {
+	before, _, err := ps.loadPackSet(time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pack sizes: %w", err)
+	}
+	after := slices.DeleteFunc(append([]int{}, before...), func(s int) bool { return s == size })
+	return previewPackSizes(before, after, opts)
+}
+
+// PreviewCreatePack validates payload like CreatePack and reports how the
+// sample orders would be packed before and after adding the pack, without
+// storing anything.
+func (ps *PackService) PreviewCreatePack(payload swagger.PackPayload, opts PreviewOptions) (*swagger.PackSizesPreview, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	pack, err := packFromPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUniqueSize(catalog, pack); err != nil {
+		return nil, err
+	}
+	return previewCatalog(catalog, func(packs []swagger.Pack) []swagger.Pack {
+		return append(packs, *pack)
+	}, opts)
+}
+
+// PreviewUpdatePack validates payload like UpdatePack and reports how the
+// sample orders would be packed before and after replacing the pack, without
+// storing anything.
+func (ps *PackService) PreviewUpdatePack(id string, payload swagger.PackPayload, opts PreviewOptions) (*swagger.PackSizesPreview, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPackExists(catalog, id); err != nil {
+		return nil, err
+	}
+	pack, err := packFromPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	pack.Id = id
+	if err := checkUniqueSize(catalog, pack); err != nil {
+		return nil, err
+	}
+	return previewCatalog(catalog, replacePack(*pack), opts)
+}
+
+// PreviewDeletePack reports how the sample orders would be packed before and
+// after removing the pack with the given ID, without storing anything.
+func (ps *PackService) PreviewDeletePack(id string, opts PreviewOptions) (*swagger.PackSizesPreview, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPackExists(catalog, id); err != nil {
+		return nil, err
+	}
+	return previewCatalog(catalog, func(packs []swagger.Pack) []swagger.Pack {
+		return slices.DeleteFunc(packs, func(p swagger.Pack) bool { return p.Id == id })
+	}, opts)
+}
+
+// PreviewSetPackActive validates an activation or deactivation like
+// ActivatePack and DeactivatePack and reports how the sample orders would be
+// packed before and after it, without storing anything.
+func (ps *PackService) PreviewSetPackActive(id string, active bool, opts PreviewOptions) (*swagger.PackSizesPreview, error) {
+	catalog, err := ps.catalog()
+	if err != nil {
+		return nil, err
+	}
+	pack, err := catalog.GetPack(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pack: %w", err)
+	}
+	if pack == nil {
+		return nil, ErrPackNotFound
+	}
+	pack.Active = active
+	if err := checkUniqueSize(catalog, pack); err != nil {
+		return nil, err
+	}
+	return previewCatalog(catalog, replacePack(*pack), opts)
+}
+
+// previewCatalog compares the sample orders under the packs in effect now
+// before and after change is applied to a copy of the catalogue.
+func previewCatalog(catalog PackCatalog, change func([]swagger.Pack) []swagger.Pack, opts PreviewOptions) (*swagger.PackSizesPreview, error) {
+	packs, err := catalog.ListPacks()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packs: %w", err)
+	}
+	now := time.Now()
+	before, _ := packSetAt(packs, now)
+	after, _ := packSetAt(change(slices.Clone(packs)), now)
+	return previewPackSizes(before, after, opts)
+}
+
+// replacePack returns a catalogue change that replaces the pack with the ID
+// of pack by pack.
+func replacePack(pack swagger.Pack) func([]swagger.Pack) []swagger.Pack {
+	return func(packs []swagger.Pack) []swagger.Pack {
+		for i := range packs {
+			if packs[i].Id == pack.Id {
+				packs[i] = pack
+			}
+		}
+		return packs
+	}
+}
+
+// previewPackSizes compares the sample orders under two pack sets.
+func previewPackSizes(before, after []int, opts PreviewOptions) (*swagger.PackSizesPreview, error) {
+	samples := opts.Samples
+	if len(samples) == 0 {
+		samples = defaultPreviewSamples
+	}
+	if len(samples) > MaxPreviewSamples {
+		return nil, invalid(fmt.Sprintf("at most %d sample orders can be previewed", MaxPreviewSamples))
+	}
+	for _, q := range samples {
+		if q <= 0 {
+			return nil, invalid(fmt.Sprintf("invalid sample order: %d", q))
+		}
+	}
+
+	beforeSet := distinctAsc(before)
+	afterSet := distinctAsc(after)
+	preview := &swagger.PackSizesPreview{
+		DryRun:  true,
+		Before:  beforeSet,
+		After:   afterSet,
+		Added:   difference(afterSet, beforeSet),
+		Removed: difference(beforeSet, afterSet),
+		Samples: make([]swagger.PackSizesPreviewSample, 0, len(samples)),
+	}
+	calcOpts := CalcOptions{Availability: opts.Availability}
+	exactOpts := CalcOptions{Mode: ModeExact, Availability: opts.Availability}
+	for _, q := range samples {
+		sample := swagger.PackSizesPreviewSample{ItemsOrdered: q}
+		before, feasibleBefore, err := previewSolve(q, beforeSet, calcOpts)
+		if err != nil {
+			return nil, err
+		}
+		after, feasibleAfter, err := previewSolve(q, afterSet, calcOpts)
+		if err != nil {
+			return nil, err
+		}
+		if feasibleBefore {
+			sample.Before = before
+			feasibleBefore = before.ItemsBackordered == nil || *before.ItemsBackordered == 0
+		}
+		if feasibleAfter {
+			sample.After = after
+			feasibleAfter = after.ItemsBackordered == nil || *after.ItemsBackordered == 0
+		}
+		if _, sample.ExactBefore, err = previewSolve(q, beforeSet, exactOpts); err != nil {
+			return nil, err
+		}
+		if _, sample.ExactAfter, err = previewSolve(q, afterSet, exactOpts); err != nil {
+			return nil, err
+		}
+		if sample.Before != nil && sample.After != nil {
+			sample.OverageDelta = utils.Ptr(*sample.After.TotalItemsUsed - *sample.Before.TotalItemsUsed)
+		}
+		sample.BecomesInfeasible = feasibleBefore && !feasibleAfter
+		sample.ExactFillLost = sample.ExactBefore && !sample.ExactAfter
+		preview.Samples = append(preview.Samples, sample)
+	}
+	return preview, nil
+}
+
+// previewSolve packs q with packSizes, reporting false when no distribution
+// exists (for example when the set is empty or has no stock). Other solver
+// errors are returned.
+func previewSolve(q int, packSizes []int, opts CalcOptions) (*swagger.CalcResult, bool, error) {
+	if len(packSizes) == 0 {
+		return nil, false, nil
+	}
+	result, err := solveWithOptions(q, append([]int{}, packSizes...), opts)
+	var fillErr *ExactFillError
+	switch {
+	case errors.As(err, &fillErr), errors.Is(err, ErrPolicy), errors.Is(err, errNoStock):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, fmt.Errorf("failed to pack sample order %d: %w", q, err)
+	}
+	return result, true, nil
+}
+
+// distinctAsc returns the distinct values in ascending order.
+func distinctAsc(values []int) []int {
+	sorted := sortedAsc(values)
+	return slices.Compact(sorted)
+}
+
+// difference returns the values of a that are missing from b.
+func difference(a, b []int) []int {
+	result := []int{}
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
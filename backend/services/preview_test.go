@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewPackSizes(t *testing.T) {
+	repo := &mockPackRepo{sizes: []int{250, 500, 1000}}
+	ps := NewPackService(repo)
+
+	t.Run("UpdateMergesAndDoesNotStore", func(t *testing.T) {
+		preview, err := ps.PreviewUpdatePackSizes([]int{300}, PreviewOptions{Samples: []int{300, 501}})
+		require.NoError(t, err)
+		assert.True(t, preview.DryRun)
+		assert.Equal(t, []int{250, 300, 500, 1000}, preview.After)
+		assert.Equal(t, []int{300}, preview.Added)
+		assert.Empty(t, preview.Removed)
+		assert.Equal(t, []int{250, 500, 1000}, repo.sizes)
+
+		first := preview.Samples[0]
+		assert.Equal(t, 500, *first.Before.TotalItemsUsed)
+		assert.Equal(t, 300, *first.After.TotalItemsUsed)
+		assert.Equal(t, -200, *first.OverageDelta)
+		assert.False(t, first.ExactBefore)
+		assert.True(t, first.ExactAfter)
+	})
+
+	t.Run("DeleteReportsLostExactFills", func(t *testing.T) {
+		preview, err := ps.PreviewDeletePackSize(250, PreviewOptions{Samples: []int{750}})
+		require.NoError(t, err)
+		assert.Equal(t, []int{250}, preview.Removed)
+		assert.True(t, preview.Samples[0].ExactFillLost)
+		assert.False(t, preview.Samples[0].BecomesInfeasible)
+	})
+
+	t.Run("DeleteReportsInfeasibleOrders", func(t *testing.T) {
+		opts := PreviewOptions{Samples: []int{1000}, Availability: map[int]int{1000: 0, 500: 2, 250: 0}}
+		preview, err := ps.PreviewDeletePackSize(500, opts)
+		require.NoError(t, err)
+		assert.True(t, preview.Samples[0].BecomesInfeasible)
+		assert.Nil(t, preview.Samples[0].After)
+	})
+
+	t.Run("InvalidUpdate", func(t *testing.T) {
+		var validationErr *ValidationError
+		_, err := ps.PreviewUpdatePackSizes([]int{-1}, PreviewOptions{})
+		assert.True(t, errors.As(err, &validationErr))
+		_, err = ps.PreviewUpdatePackSizes([]int{1}, PreviewOptions{Samples: []int{0}})
+		assert.True(t, errors.As(err, &validationErr))
+	})
+}
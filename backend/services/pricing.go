@@ -0,0 +1,355 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// maxTierCombinations bounds how many volume-tier assignments solveCheapest
+// explores; larger catalogues are optimised at list prices only.
+const maxTierCombinations = 64
+
+// solveCheapest solves a positive order under ObjectivePrice.
+//
+// Volume tiers make the price of a distribution non-linear in the pack counts.
+// For every assignment of a tier to each pack size, the tier's minimum number
+// of packs is committed up front and the remainder is solved at the tier
+// prices; any extra packs can only reach a cheaper tier. The distribution with
+// the lowest actual price over all assignments is the cheapest overall.
+func solveCheapest(order int, packSizes []int, bySize map[int]swagger.Pack, opts CalcOptions) (*swagger.CalcResult, error) {
+	if bySize == nil {
+		return nil, ErrNotSupported
+	}
+	if opts.Mode != ModeDefault || opts.Availability != nil {
+		return nil, invalid("the price objective cannot be combined with a mode or availability caps")
+	}
+	if _, err := packCurrency(packSizes, bySize); err != nil {
+		return nil, err
+	}
+
+	sizes := sortedDesc(packSizes)
+	limits := policyLimits(order, opts)
+	var best *swagger.CalcResult
+	var bestPrice float64
+	for _, tiers := range tierAssignments(sizes, bySize) {
+		unit := make(map[int]float64, len(sizes))
+		forced := make(map[int]int)
+		forcedItems := 0
+		for i, size := range sizes {
+			unit[size] = *bySize[size].Price
+			if tier := tiers[i]; tier != nil {
+				unit[size] = tier.UnitPrice
+				forced[size] = tier.MinPacks
+				forcedItems += tier.MinPacks * size
+			}
+		}
+		rest, ok := limits.without(forced)
+		if !ok {
+			continue
+		}
+		packs, ok := cheapestPacks(max(order-forcedItems, 0), sizes, unit, rest)
+		if !ok {
+			continue
+		}
+		for size, count := range forced {
+			packs[size] += count
+		}
+		result := distributionResult(order, packs)
+		quote, err := priceDistribution(result, bySize)
+		if err != nil {
+			return nil, err
+		}
+		if best == nil || quote.Total < bestPrice ||
+			(quote.Total == bestPrice && *result.TotalItemsUsed < *best.TotalItemsUsed) {
+			best, bestPrice = result, quote.Total
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: no distribution stays within the overage and pack caps", ErrPolicy)
+	}
+	return best, nil
+}
+
+// tierAssignments lists every combination of one volume tier (or nil for the
+// list price) per pack size, in the order of packSizes. When there are more
+// than maxTierCombinations, only the list-price assignment is returned.
+func tierAssignments(packSizes []int, bySize map[int]swagger.Pack) [][]*swagger.PriceTier {
+	combinations := [][]*swagger.PriceTier{{}}
+	for _, size := range packSizes {
+		options := []*swagger.PriceTier{nil}
+		if tiers := bySize[size].PriceTiers; tiers != nil {
+			for i := range *tiers {
+				options = append(options, &(*tiers)[i])
+			}
+		}
+		if len(combinations)*len(options) > maxTierCombinations {
+			return [][]*swagger.PriceTier{make([]*swagger.PriceTier, len(packSizes))}
+		}
+		var next [][]*swagger.PriceTier
+		for _, combination := range combinations {
+			for _, option := range options {
+				next = append(next, append(append([]*swagger.PriceTier{}, combination...), option))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// cheapestPacks returns the distribution of at least order items with the
+// lowest cost at the given unit prices within limits; ties go to the smaller
+// total. It returns ok false if no distribution stays within limits.
+// packSizes must be sorted in descending order.
+//
+// Orders above dpThreshold are first reduced with the pack that has the lowest
+// price per item, which is what an optimal distribution of a large order
+// consists of almost entirely, so that the remainder fits the DP window. Under
+// a pack cap the largest pack is peeled instead, as calculatePacksConstrained
+// does.
+func cheapestPacks(order int, packSizes []int, unit map[int]float64, limits packLimits) (packs map[int]int, ok bool) {
+	peeledSize, peeled := packSizes[0], 0
+	if order > dpThreshold {
+		if limits.maxPacks == math.MaxInt {
+			for _, size := range packSizes {
+				if unit[size]/float64(size) < unit[peeledSize]/float64(peeledSize) {
+					peeledSize = size
+				}
+			}
+		}
+		peeled = (order - dpThreshold) / peeledSize
+	}
+	rest := order - peeled*peeledSize
+	if limits, ok = limits.without(map[int]int{peeledSize: peeled}); !ok {
+		return nil, false
+	}
+
+	// A cheapest distribution never exceeds the order by a whole pack, because
+	// that pack could be dropped.
+	limit := min(rest+packSizes[0], limits.maxTotal)
+	if limit < rest {
+		return nil, false
+	}
+	if packs, ok = cheapestWithin(rest, limit, packSizes, unit, limits.maxPacks); ok && peeled > 0 {
+		packs[peeledSize] += peeled
+	}
+	return packs, ok
+}
+
+// cheapestWithin returns the cheapest distribution of a total between from and
+// to in at most maxPacks packs, or ok false if there is none.
+//
+// When the cheapest distribution has too many packs, every pack is charged a
+// penalty on top of its cost, and the smallest penalty found by doubling and
+// bisection whose cheapest distribution meets the cap is used. Like any such
+// relaxation this may miss a cheaper distribution that no penalty selects; the
+// fewest-packs distribution is the fallback.
+func cheapestWithin(from, to int, packSizes []int, unit map[int]float64, maxPacks int) (map[int]int, bool) {
+	table := newCostTable(to, packSizes, unit)
+	if s := table.cheapestFrom(from); s >= 0 && table.packs[s] <= maxPacks {
+		return table.distribution(s), true
+	}
+	fewest := newPackTable(to, packSizes)
+	total := firstWithinPacks(from, maxPacks, fewest)
+	if total == -1 {
+		return nil, false
+	}
+	best := fewest.distribution(total)
+	bestCost := packsCost(best, unit)
+	meetsCap := func(penalty float64) bool {
+		penalised := make(map[int]float64, len(unit))
+		for size, cost := range unit {
+			penalised[size] = cost + penalty
+		}
+		table := newCostTable(to, packSizes, penalised)
+		s := table.cheapestFrom(from)
+		if s < 0 || table.packs[s] > maxPacks {
+			return false
+		}
+		if packs := table.distribution(s); packsCost(packs, unit) < bestCost {
+			best, bestCost = packs, packsCost(packs, unit)
+		}
+		return true
+	}
+	low, high := 0.0, 1.0
+	for _, cost := range unit {
+		high = max(high, cost)
+	}
+	for i := 0; i < 64 && !meetsCap(high); i++ {
+		low, high = high, high*2
+	}
+	for i := 0; i < 20; i++ {
+		if mid := (low + high) / 2; meetsCap(mid) {
+			high = mid
+		} else {
+			low = mid
+		}
+	}
+	return best, true
+}
+
+// packsCost returns the cost of packs at the given unit prices.
+func packsCost(packs map[int]int, unit map[int]float64) float64 {
+	cost := 0.0
+	for size, count := range packs {
+		cost += unit[size] * float64(count)
+	}
+	return cost
+}
+
+// costTable holds, for every total from 0 to a limit, the lowest cost of
+// packing it exactly at the given unit costs (+Inf if unreachable), the pack
+// size added last on that path and the number of packs on it.
+type costTable struct {
+	cost  []float64
+	last  []int
+	packs []int
+}
+
+// newCostTable fills a costTable up to limit. packSizes must be sorted in
+// descending order.
+func newCostTable(limit int, packSizes []int, unit map[int]float64) *costTable {
+	t := &costTable{
+		cost:  make([]float64, limit+1),
+		last:  make([]int, limit+1),
+		packs: make([]int, limit+1),
+	}
+	for s := 1; s <= limit; s++ {
+		t.cost[s] = math.Inf(1)
+	}
+	for s := 0; s <= limit; s++ {
+		if math.IsInf(t.cost[s], 1) {
+			continue
+		}
+		for _, size := range packSizes {
+			if ns := s + size; ns <= limit && t.cost[s]+unit[size] < t.cost[ns] {
+				t.cost[ns] = t.cost[s] + unit[size]
+				t.last[ns] = size
+				t.packs[ns] = t.packs[s] + 1
+			}
+		}
+	}
+	return t
+}
+
+// cheapestFrom returns the cheapest reachable total >= from, the smallest one
+// on ties, or -1 if there is none.
+func (t *costTable) cheapestFrom(from int) int {
+	best := -1
+	for s := from; s < len(t.cost); s++ {
+		if !math.IsInf(t.cost[s], 1) && (best == -1 || t.cost[s] < t.cost[best]) {
+			best = s
+		}
+	}
+	return best
+}
+
+// distribution rebuilds the pack counts used to reach the total s.
+func (t *costTable) distribution(s int) map[int]int {
+	packs := make(map[int]int)
+	for ; s > 0; s -= t.last[s] {
+		packs[t.last[s]]++
+	}
+	return packs
+}
+
+// distributionResult wraps a distribution chosen for order.
+func distributionResult(order int, packs map[int]int) *swagger.CalcResult {
+	total := 0
+	for size, count := range packs {
+		total += size * count
+	}
+	packsUsed := utils.ConvertMapKeys(packs)
+	return &swagger.CalcResult{
+		ItemsOrdered:   utils.Ptr(order),
+		TotalItemsUsed: utils.Ptr(total),
+		PacksUsed:      &packsUsed,
+	}
+}
+
+// packCurrency checks that every pack size has a price and that all prices
+// share one currency, which it returns.
+func packCurrency(packSizes []int, bySize map[int]swagger.Pack) (string, error) {
+	currency := ""
+	for _, size := range packSizes {
+		pack := bySize[size]
+		if pack.Price == nil || pack.Currency == nil {
+			return "", invalid(fmt.Sprintf("pack %s (size %d) has no price", pack.Id, size))
+		}
+		if currency != "" && *pack.Currency != currency {
+			return "", invalid(fmt.Sprintf("packs are priced in different currencies (%s, %s)", currency, *pack.Currency))
+		}
+		currency = *pack.Currency
+	}
+	return currency, nil
+}
+
+// tierPrice returns the price of one pack when count packs are bought: the
+// unit price of the tier with the highest MinPacks not above count, or the
+// list price if no tier applies.
+func tierPrice(pack swagger.Pack, count int) float64 {
+	price := *pack.Price
+	if pack.PriceTiers == nil {
+		return price
+	}
+	minPacks := 0
+	for _, tier := range *pack.PriceTiers {
+		if tier.MinPacks <= count && tier.MinPacks > minPacks {
+			price, minPacks = tier.UnitPrice, tier.MinPacks
+		}
+	}
+	return price
+}
+
+// priceDistribution prices the packs of result, largest size first, and
+// returns a quote with the lines, subtotal, discount, total and currency set.
+func priceDistribution(result *swagger.CalcResult, bySize map[int]swagger.Pack) (*swagger.Quote, error) {
+	var sizes []int
+	for key, count := range *result.PacksUsed {
+		size, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	currency, err := packCurrency(sizes, bySize)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &swagger.Quote{Currency: currency, Lines: []swagger.QuoteLine{}, Result: *result}
+	for _, size := range sizes {
+		pack := bySize[size]
+		count := (*result.PacksUsed)[strconv.Itoa(size)]
+		unit := tierPrice(pack, count)
+		line := swagger.QuoteLine{
+			PackId:    pack.Id,
+			Size:      size,
+			Count:     count,
+			ListPrice: *pack.Price,
+			UnitPrice: unit,
+			Discount:  roundCents((*pack.Price - unit) * float64(count)),
+			Total:     roundCents(unit * float64(count)),
+		}
+		quote.Lines = append(quote.Lines, line)
+		quote.Subtotal += roundCents(*pack.Price * float64(count))
+		quote.Discount += line.Discount
+		quote.Total += line.Total
+	}
+	quote.Subtotal = roundCents(quote.Subtotal)
+	quote.Discount = roundCents(quote.Discount)
+	quote.Total = roundCents(quote.Total)
+	return quote, nil
+}
+
+// roundCents rounds an amount to two decimal places.
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// mockQuoteCatalog is a mockCatalog that also stores quotes.
+type mockQuoteCatalog struct {
+	mockCatalog
+	quotes []swagger.Quote
+}
+
+func (m *mockQuoteCatalog) CreateQuote(quote *swagger.Quote) error {
+	quote.Id = strconv.Itoa(len(m.quotes) + 1)
+	m.quotes = append(m.quotes, *quote)
+	return nil
+}
+
+func (m *mockQuoteCatalog) UpdateQuote(quote *swagger.Quote) error {
+	for i := range m.quotes {
+		if m.quotes[i].Id == quote.Id {
+			m.quotes[i] = *quote
+		}
+	}
+	return nil
+}
+
+func (m *mockQuoteCatalog) GetQuote(id string) (*swagger.Quote, error) {
+	for _, quote := range m.quotes {
+		if quote.Id == id {
+			return &quote, nil
+		}
+	}
+	return nil, nil
+}
+
+// pricedPack builds a payload for a pack priced in EUR.
+func pricedPack(size int, price float64, tiers ...swagger.PriceTier) swagger.PackPayload {
+	payload := swagger.PackPayload{Size: size, Price: &price, Currency: utils.Ptr("EUR")}
+	if len(tiers) > 0 {
+		payload.PriceTiers = &tiers
+	}
+	return payload
+}
+
+func TestTierPrice(t *testing.T) {
+	pack := swagger.Pack{Price: utils.Ptr(10.0), PriceTiers: &[]swagger.PriceTier{
+		{MinPacks: 10, UnitPrice: 9},
+		{MinPacks: 100, UnitPrice: 8},
+	}}
+	assert.Equal(t, 10.0, tierPrice(pack, 9))
+	assert.Equal(t, 9.0, tierPrice(pack, 10))
+	assert.Equal(t, 8.0, tierPrice(pack, 250))
+}
+
+func TestCalculatePacksCheapest(t *testing.T) {
+	repo := &mockQuoteCatalog{}
+	ps := NewPackService(repo)
+	for _, payload := range []swagger.PackPayload{pricedPack(250, 4), pricedPack(500, 9), pricedPack(1000, 15)} {
+		_, err := ps.CreatePack(payload)
+		require.NoError(t, err)
+	}
+
+	t.Run("PrefersCheapOverFewPacks", func(t *testing.T) {
+		// 500 costs more than two 250s, so 501 is best packed as 250+250+250.
+		result, err := ps.CalculatePacksWithOptions(501, CalcOptions{Objective: ObjectivePrice})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"250": 3}, *result.PacksUsed)
+	})
+
+	t.Run("LargeOrder", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(1000001, CalcOptions{Objective: ObjectivePrice})
+		require.NoError(t, err)
+		assert.Equal(t, 1000250, *result.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"1000": 1000, "250": 1}, *result.PacksUsed)
+	})
+
+	t.Run("WithinPolicyCaps", func(t *testing.T) {
+		ps := NewPackService(&mockQuoteCatalog{})
+		for _, payload := range []swagger.PackPayload{pricedPack(250, 1), pricedPack(300, 100)} {
+			_, err := ps.CreatePack(payload)
+			require.NoError(t, err)
+		}
+		// Two 250s are cheapest but ship 92% overage in two packs.
+		result, err := ps.CalculatePacksWithOptions(260, CalcOptions{Objective: ObjectivePrice, MaxOveragePercent: utils.Ptr(20.0)})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"300": 1}, *result.PacksUsed)
+		result, err = ps.CalculatePacksWithOptions(260, CalcOptions{Objective: ObjectivePrice, MaxPacks: 1})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"300": 1}, *result.PacksUsed)
+		// Five 250s are cheapest; of the totals in four packs 300 + 3 x 250 is.
+		result, err = ps.CalculatePacksWithOptions(1010, CalcOptions{Objective: ObjectivePrice, MaxPacks: 4})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"300": 1, "250": 3}, *result.PacksUsed)
+
+		_, err = ps.CalculatePacksWithOptions(260, CalcOptions{Objective: ObjectivePrice, MaxOveragePercent: utils.Ptr(10.0)})
+		assert.ErrorIs(t, err, ErrPolicy)
+	})
+
+	t.Run("RejectsConstraints", func(t *testing.T) {
+		_, err := ps.CalculatePacksWithOptions(501, CalcOptions{Objective: ObjectivePrice, Mode: ModeExact})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("MissingPrice", func(t *testing.T) {
+		pack, err := ps.CreatePack(swagger.PackPayload{Size: 100})
+		require.NoError(t, err)
+		defer ps.DeletePack(pack.Id)
+		_, err = ps.CalculatePacksWithOptions(501, CalcOptions{Objective: ObjectivePrice})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
+
+func TestQuotes(t *testing.T) {
+	repo := &mockQuoteCatalog{}
+	ps := NewPackService(repo)
+	_, err := ps.CreatePack(pricedPack(250, 4, swagger.PriceTier{MinPacks: 3, UnitPrice: 3.5}))
+	require.NoError(t, err)
+	_, err = ps.CreatePack(pricedPack(500, 7.5))
+	require.NoError(t, err)
+
+	t.Run("FewestPacks", func(t *testing.T) {
+		quote, err := ps.CreateQuote(swagger.QuoteRequest{Items: 501})
+		require.NoError(t, err)
+		assert.Equal(t, swagger.Packs, quote.Objective)
+		assert.Equal(t, "EUR", quote.Currency)
+		assert.Equal(t, 11.5, quote.Total)
+		assert.Len(t, quote.Lines, 2)
+	})
+
+	t.Run("LowestPrice", func(t *testing.T) {
+		// Three 250s reach the volume tier: 3 x 3.50 beats 7.50 + 4.00.
+		quote, err := ps.CreateQuote(swagger.QuoteRequest{Items: 501, Objective: utils.Ptr(swagger.Price)})
+		require.NoError(t, err)
+		assert.Equal(t, 10.5, quote.Total)
+		assert.Equal(t, 12.0, quote.Subtotal)
+		assert.Equal(t, 1.5, quote.Discount)
+		assert.Equal(t, []swagger.QuoteLine{
+			{PackId: "1", Size: 250, Count: 3, ListPrice: 4, UnitPrice: 3.5, Discount: 1.5, Total: 10.5},
+		}, quote.Lines)
+
+		stored, err := ps.GetQuote(quote.Id)
+		require.NoError(t, err)
+		assert.Equal(t, quote.Total, stored.Total)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		repo.quotes = append(repo.quotes, swagger.Quote{Id: "99", ExpiresAt: time.Now().Add(-time.Minute)})
+		_, err := ps.GetQuote("99")
+		assert.ErrorIs(t, err, ErrQuoteExpired)
+		_, err = ps.GetQuote("100")
+		assert.ErrorIs(t, err, ErrQuoteNotFound)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		var validationErr *ValidationError
+		_, err := ps.CreateQuote(swagger.QuoteRequest{Items: 0})
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.CreateQuote(swagger.QuoteRequest{Items: 10, ValidDays: utils.Ptr(0)})
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.CreatePack(swagger.PackPayload{Size: 1000, Price: utils.Ptr(10.0), Currency: utils.Ptr("euro")})
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
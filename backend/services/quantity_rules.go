@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ErrQuantityRuleNotFound is returned when a SKU has no quantity rule.
+var ErrQuantityRuleNotFound = errors.New("quantity rule not found")
+
+// QuantityRuleRepository is implemented by repositories that can also persist
+// the order quantity rules of products, keyed by SKU.
+type QuantityRuleRepository interface {
+	ListQuantityRules() ([]swagger.QuantityRule, error)
+	GetQuantityRule(sku string) (*swagger.QuantityRule, error)
+	SetQuantityRule(rule *swagger.QuantityRule) error
+	DeleteQuantityRule(sku string) error
+}
+
+// quantityRuleRepo returns the quantity rule storage of the configured
+// repository.
+func (ps *PackService) quantityRuleRepo() (QuantityRuleRepository, error) {
+	repo, ok := ps.repo.(QuantityRuleRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// ListQuantityRules retrieves the quantity rules of every product.
+func (ps *PackService) ListQuantityRules() ([]swagger.QuantityRule, error) {
+	repo, err := ps.quantityRuleRepo()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := repo.ListQuantityRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quantity rules: %w", err)
+	}
+	if rules == nil {
+		rules = []swagger.QuantityRule{}
+	}
+	return rules, nil
+}
+
+// GetQuantityRule retrieves the quantity rule of a SKU.
+func (ps *PackService) GetQuantityRule(sku string) (*swagger.QuantityRule, error) {
+	repo, err := ps.quantityRuleRepo()
+	if err != nil {
+		return nil, err
+	}
+	rule, err := repo.GetQuantityRule(sku)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quantity rule: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrQuantityRuleNotFound
+	}
+	return rule, nil
+}
+
+// SetQuantityRule validates payload and stores it as the quantity rule of
+// sku, replacing any previous rule.
+func (ps *PackService) SetQuantityRule(sku string, payload swagger.QuantityRulePayload) (*swagger.QuantityRule, error) {
+	repo, err := ps.quantityRuleRepo()
+	if err != nil {
+		return nil, err
+	}
+	rule, err := quantityRuleFromPayload(sku, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SetQuantityRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to store quantity rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteQuantityRule removes the quantity rule of a SKU.
+func (ps *PackService) DeleteQuantityRule(sku string) error {
+	repo, err := ps.quantityRuleRepo()
+	if err != nil {
+		return err
+	}
+	if _, err := ps.GetQuantityRule(sku); err != nil {
+		return err
+	}
+	if err := repo.DeleteQuantityRule(sku); err != nil {
+		return fmt.Errorf("failed to delete quantity rule: %w", err)
+	}
+	return nil
+}
+
+// quantityRuleFromPayload validates payload and converts it into the rule of
+// sku. Every broken constraint is reported as a field error.
+func quantityRuleFromPayload(sku string, payload swagger.QuantityRulePayload) (*swagger.QuantityRule, error) {
+	if sku == "" {
+		return nil, invalid("sku is required")
+	}
+	var fields []swagger.FieldError
+	fail := func(field, message string) {
+		fields = append(fields, swagger.FieldError{Field: field, Message: message})
+	}
+	if payload.MinOrder != nil && *payload.MinOrder < 0 {
+		fail("minOrder", "must not be negative")
+	}
+	if payload.MaxOrder != nil {
+		switch {
+		case *payload.MaxOrder < 1:
+			fail("maxOrder", "must be positive")
+		case payload.MinOrder != nil && *payload.MaxOrder < *payload.MinOrder:
+			fail("maxOrder", "must not be below minOrder")
+		}
+	}
+	if payload.Multiple != nil && *payload.Multiple < 1 {
+		fail("multiple", "must be positive")
+	}
+	if payload.BlockedRanges != nil {
+		for i, r := range *payload.BlockedRanges {
+			if r.From < 1 || r.To < r.From {
+				fail(fmt.Sprintf("blockedRanges.%d", i), "from must be positive and not above to")
+			}
+		}
+	}
+	if len(fields) > 0 {
+		return nil, invalidFields(fields)
+	}
+	rule := &swagger.QuantityRule{
+		Sku:      sku,
+		MinOrder: payload.MinOrder,
+		MaxOrder: payload.MaxOrder,
+		Multiple: payload.Multiple,
+	}
+	if payload.BlockedRanges != nil && len(*payload.BlockedRanges) > 0 {
+		rule.BlockedRanges = utils.Ptr(append([]swagger.QuantityRange(nil), *payload.BlockedRanges...))
+	}
+	return rule, nil
+}
+
+// checkQuantities checks the ordered items of every SKU against its quantity
+// rule and reports each violation as a field error named by field. Nothing
+// ordered breaks no rule, and without quantity rule storage nothing is
+// checked.
+func (ps *PackService) checkQuantities(items map[string]int, field func(sku string) string) error {
+	repo, err := ps.quantityRuleRepo()
+	if err != nil {
+		return nil
+	}
+	skus := make([]string, 0, len(items))
+	for sku, count := range items {
+		if count != 0 {
+			skus = append(skus, sku)
+		}
+	}
+	sort.Strings(skus)
+	var fields []swagger.FieldError
+	for _, sku := range skus {
+		rule, err := repo.GetQuantityRule(sku)
+		if err != nil {
+			return fmt.Errorf("failed to get quantity rule: %w", err)
+		}
+		if rule == nil {
+			continue
+		}
+		for _, message := range quantityViolations(*rule, items[sku]) {
+			fields = append(fields, swagger.FieldError{Field: field(sku), Message: message})
+		}
+	}
+	if len(fields) > 0 {
+		return invalidFields(fields)
+	}
+	return nil
+}
+
+// quantityViolations lists the constraints of rule that items breaks.
+func quantityViolations(rule swagger.QuantityRule, items int) []string {
+	var messages []string
+	if rule.MinOrder != nil && items < *rule.MinOrder {
+		messages = append(messages, fmt.Sprintf("must be at least %d", *rule.MinOrder))
+	}
+	if rule.MaxOrder != nil && items > *rule.MaxOrder {
+		messages = append(messages, fmt.Sprintf("must be at most %d", *rule.MaxOrder))
+	}
+	if rule.Multiple != nil && items%*rule.Multiple != 0 {
+		messages = append(messages, fmt.Sprintf("must be a multiple of %d", *rule.Multiple))
+	}
+	if rule.BlockedRanges != nil {
+		for _, r := range *rule.BlockedRanges {
+			if items >= r.From && items <= r.To {
+				messages = append(messages, fmt.Sprintf("must not be between %d and %d", r.From, r.To))
+			}
+		}
+	}
+	return messages
+}
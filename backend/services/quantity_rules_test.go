@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+func TestQuantityViolations(t *testing.T) {
+	rule := swagger.QuantityRule{
+		Sku:           "A",
+		MinOrder:      utils.Ptr(10),
+		MaxOrder:      utils.Ptr(100),
+		Multiple:      utils.Ptr(5),
+		BlockedRanges: &[]swagger.QuantityRange{{From: 40, To: 60}},
+	}
+	tests := []struct {
+		items    int
+		expected []string
+	}{
+		{items: 25},
+		{items: 5, expected: []string{"must be at least 10"}},
+		{items: 103, expected: []string{"must be at most 100", "must be a multiple of 5"}},
+		{items: 50, expected: []string{"must not be between 40 and 60"}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, quantityViolations(rule, tt.items), "items %d", tt.items)
+	}
+}
+
+func TestSetQuantityRule(t *testing.T) {
+	_, err := NewPackService(&mockPackRepo{}).SetQuantityRule("A", swagger.QuantityRulePayload{})
+	assert.ErrorIs(t, err, ErrNotSupported)
+
+	_, err = quantityRuleFromPayload("A", swagger.QuantityRulePayload{
+		MinOrder:      utils.Ptr(-1),
+		BlockedRanges: &[]swagger.QuantityRange{{From: 10, To: 20}, {From: 30, To: 25}},
+	})
+	var validation *ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Equal(t, []swagger.FieldError{
+		{Field: "minOrder", Message: "must not be negative"},
+		{Field: "blockedRanges.1", Message: "from must be positive and not above to"},
+	}, validation.Fields)
+	assert.Equal(t, "minOrder: must not be negative; blockedRanges.1: from must be positive and not above to", validation.Message)
+}
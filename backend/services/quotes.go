@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+var (
+	// ErrQuoteNotFound is returned when a quote ID does not exist.
+	ErrQuoteNotFound = errors.New("quote not found")
+	// ErrQuoteExpired is returned when a stored quote is past its expiry.
+	ErrQuoteExpired = errors.New("quote has expired")
+	// ErrQuoteAccepted is returned when an order is placed from a quote that
+	// has already been accepted.
+	ErrQuoteAccepted = errors.New("quote has already been accepted")
+)
+
+const (
+	// defaultQuoteValidDays is how long quoted prices are honoured by default.
+	defaultQuoteValidDays = 14
+	// MaxQuoteValidDays is the longest validity a quote can be given.
+	MaxQuoteValidDays = 365
+)
+
+// QuoteRepository is implemented by repositories that can also persist quotes.
+type QuoteRepository interface {
+	CreateQuote(quote *swagger.Quote) error
+	GetQuote(id string) (*swagger.Quote, error)
+	UpdateQuote(quote *swagger.Quote) error
+}
+
+// quoteRepo returns the quote storage of the configured repository.
+func (ps *PackService) quoteRepo() (QuoteRepository, error) {
+	repo, ok := ps.repo.(QuoteRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// CreateQuote prices the distribution chosen for req and stores it, so the
+// quoted prices can be honoured until the quote expires even if the catalogue
+// prices change in the meantime. The customer and attributes of req select the
+// customer policy and packing rules, as they do for CreateOrder.
+func (ps *PackService) CreateQuote(req swagger.QuoteRequest) (*swagger.Quote, error) {
+	if req.Items <= 0 {
+		return nil, invalid("items must be a positive integer")
+	}
+	validDays := defaultQuoteValidDays
+	if req.ValidDays != nil {
+		validDays = *req.ValidDays
+	}
+	if validDays < 1 || validDays > MaxQuoteValidDays {
+		return nil, invalid(fmt.Sprintf("validDays must be between 1 and %d", MaxQuoteValidDays))
+	}
+	objective := swagger.Packs
+	if req.Objective != nil {
+		objective = *req.Objective
+	}
+	var opts CalcOptions
+	switch objective {
+	case swagger.Packs:
+	case swagger.Price:
+		opts.Objective = ObjectivePrice
+	default:
+		return nil, invalid("objective must be packs or price")
+	}
+	if req.CustomerId != nil {
+		opts.Customer = *req.CustomerId
+	}
+	if req.Attributes != nil {
+		opts.Attributes = *req.Attributes
+	}
+
+	repo, err := ps.quoteRepo()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ps.catalog(); err != nil {
+		return nil, err
+	}
+	result, bySize, err := ps.calculate(req.Items, opts)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := priceDistribution(result, bySize)
+	if err != nil {
+		return nil, err
+	}
+	quote.ItemsOrdered = req.Items
+	quote.Objective = objective
+	quote.CustomerId = req.CustomerId
+	if len(opts.Attributes) > 0 {
+		quote.Attributes = utils.Ptr(opts.Attributes)
+	}
+	quote.CreatedAt = time.Now().UTC()
+	quote.ExpiresAt = quote.CreatedAt.AddDate(0, 0, validDays)
+	if err := repo.CreateQuote(quote); err != nil {
+		return nil, fmt.Errorf("failed to store quote: %w", err)
+	}
+	return quote, nil
+}
+
+// GetQuote retrieves a stored quote. Expired quotes are reported with
+// ErrQuoteExpired instead.
+func (ps *PackService) GetQuote(id string) (*swagger.Quote, error) {
+	repo, err := ps.quoteRepo()
+	if err != nil {
+		return nil, err
+	}
+	quote, err := repo.GetQuote(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote: %w", err)
+	}
+	if quote == nil {
+		return nil, ErrQuoteNotFound
+	}
+	if !time.Now().Before(quote.ExpiresAt) {
+		return nil, ErrQuoteExpired
+	}
+	return quote, nil
+}
+
+// AcceptQuote places an order for a stored quote that has not expired. The
+// order ships the quoted distribution at the quoted total and keeps the
+// customer, attributes and objective of the quote. items must be zero or the
+// quantity of the quote. A quote can be accepted once; later attempts fail
+// with ErrQuoteAccepted.
+func (ps *PackService) AcceptQuote(id string, items int) (*swagger.Order, error) {
+	repo, err := ps.quoteRepo()
+	if err != nil {
+		return nil, err
+	}
+	orders, err := ps.orderRepo()
+	if err != nil {
+		return nil, err
+	}
+	ps.quoteMu.Lock()
+	defer ps.quoteMu.Unlock()
+	quote, err := ps.GetQuote(id)
+	if err != nil {
+		return nil, err
+	}
+	if quote.AcceptedAt != nil {
+		return nil, ErrQuoteAccepted
+	}
+	if items != 0 && items != quote.ItemsOrdered {
+		return nil, invalid(fmt.Sprintf("items must match the %d items of the quote", quote.ItemsOrdered))
+	}
+
+	// Mark the quote first so that a failure cannot leave a second order.
+	quote.AcceptedAt = utils.Ptr(time.Now().UTC())
+	if err := repo.UpdateQuote(quote); err != nil {
+		return nil, fmt.Errorf("failed to update quote: %w", err)
+	}
+	order := newOrder(quote.ItemsOrdered, quote.Result, quoteOptions(quote))
+	order.QuoteId = utils.Ptr(quote.Id)
+	order.Currency = utils.Ptr(quote.Currency)
+	order.Total = utils.Ptr(quote.Total)
+	if err := orders.CreateOrder(order); err != nil {
+		quote.AcceptedAt = nil
+		_ = repo.UpdateQuote(quote)
+		return nil, fmt.Errorf("failed to store order: %w", err)
+	}
+	quote.OrderId = utils.Ptr(order.Id)
+	if err := repo.UpdateQuote(quote); err != nil {
+		return nil, fmt.Errorf("failed to update quote: %w", err)
+	}
+	if pool, _ := ps.looseStockRepo(); pool != nil {
+		if overage := *order.Result.TotalItemsUsed - order.ItemsOrdered; overage > 0 {
+			if err := pool.AddLooseStock(overage); err != nil {
+				return nil, fmt.Errorf("failed to add overage to loose stock: %w", err)
+			}
+		}
+	}
+	return order, nil
+}
+
+// quoteOptions returns the options a quote was packed with.
+func quoteOptions(quote *swagger.Quote) CalcOptions {
+	var opts CalcOptions
+	if quote.CustomerId != nil {
+		opts.Customer = *quote.CustomerId
+	}
+	if quote.Attributes != nil {
+		opts.Attributes = *quote.Attributes
+	}
+	if quote.Objective == swagger.Price {
+		opts.Objective = ObjectivePrice
+	}
+	return opts
+}
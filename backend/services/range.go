@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"ship_line/swagger"
+)
+
+// MaxRangeRows caps how many quantities a single range export may contain.
+const MaxRangeRows = 100000
+
+// maxRuleRows caps how many rows of a range may match a rule. Those rows are
+// solved one by one instead of from the shared DP table.
+const maxRuleRows = 1000
+
+// RangeCalculation is the distribution table of a range of order quantities,
+// computed with a single DP pass and read row by row.
+type RangeCalculation struct {
+	// PackSizes are the active sizes in ascending order.
+	PackSizes []int
+
+	from, to, step int
+	solver         *rangeSolver
+	bySize         map[int]swagger.Pack
+	// ruled holds the results of the rows that match a rule, by quantity.
+	ruled map[int]*swagger.CalcResult
+}
+
+// CalculateRange prepares the distributions of every quantity from from to to
+// in increments of step. Each row matches what CalculatePacks returns for the
+// same quantity: the rows share one DP table over the active catalogue packs,
+// and the rows that match a rule of the active rule set are solved on their own.
+func (ps *PackService) CalculateRange(from, to, step int) (*RangeCalculation, error) {
+	if from < 1 || to < from || to > MaxRangeQuantity {
+		return nil, invalid(fmt.Sprintf("invalid range %d-%d (must satisfy 1 <= from <= to <= %d)", from, to, MaxRangeQuantity))
+	}
+	if step < 1 {
+		return nil, invalid("step must be a positive integer")
+	}
+	if rows := (to-from)/step + 1; rows > MaxRangeRows {
+		return nil, invalid(fmt.Sprintf("range of %d rows exceeds the limit of %d", rows, MaxRangeRows))
+	}
+
+	packSizes, bySize, err := ps.loadPackSet(time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pack sizes: %w", err)
+	}
+	if len(packSizes) == 0 {
+		return nil, fmt.Errorf("no pack sizes configured")
+	}
+	rules := []swagger.PolicyRule{}
+	set, err := ps.activeRuleSet()
+	if err != nil {
+		return nil, err
+	}
+	if set != nil {
+		rules = set.Rules
+	}
+	ruled := map[int]*swagger.CalcResult{}
+	for q := from; q <= to; q += step {
+		if _, matched, _ := ps.withRules(q, CalcOptions{Rules: rules}); len(matched) == 0 {
+			continue
+		}
+		if len(ruled) == maxRuleRows {
+			return nil, invalid(fmt.Sprintf("more than %d rows of the range match a rule; narrow the range", maxRuleRows))
+		}
+		result, _, err := ps.calculate(q, CalcOptions{Rules: rules})
+		if err != nil {
+			return nil, fmt.Errorf("order %d: %w", q, err)
+		}
+		ruled[q] = result
+	}
+
+	return &RangeCalculation{
+		PackSizes: distinctAsc(packSizes),
+		from:      from,
+		to:        to,
+		step:      step,
+		solver:    newRangeSolver(to, sortedDesc(packSizes)),
+		bySize:    bySize,
+		ruled:     ruled,
+	}, nil
+}
+
+// Each calls fn with the result for every quantity in the range, in order, and
+// stops at the first error fn returns.
+func (r *RangeCalculation) Each(fn func(*swagger.CalcResult) error) error {
+	for q := r.from; q <= r.to; q += r.step {
+		result, ok := r.ruled[q]
+		if !ok {
+			result = describeResult(r.solver.result(q), r.bySize)
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+func TestCalculateRange(t *testing.T) {
+	ps := NewPackService(&mockPackRepo{sizes: []int{500, 250, 1000}})
+
+	t.Run("MatchesCalculatePacks", func(t *testing.T) {
+		calc, err := ps.CalculateRange(1, 3000, 7)
+		require.NoError(t, err)
+		assert.Equal(t, []int{250, 500, 1000}, calc.PackSizes)
+
+		rows := 0
+		err = calc.Each(func(result *swagger.CalcResult) error {
+			want, err := ps.CalculatePacks(*result.ItemsOrdered)
+			require.NoError(t, err)
+			assert.Equal(t, want, result)
+			rows++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 429, rows)
+	})
+
+	t.Run("StopsOnError", func(t *testing.T) {
+		calc, err := ps.CalculateRange(1, 10, 1)
+		require.NoError(t, err)
+		stop := errors.New("stop")
+		rows := 0
+		err = calc.Each(func(*swagger.CalcResult) error {
+			rows++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, rows)
+	})
+
+	t.Run("CatalogueAndRules", func(t *testing.T) {
+		repo := &mockRuleSetRepo{mockPackRepo: mockPackRepo{sizes: []int{250, 500, 1000}}}
+		ruled := NewPackService(repo)
+		_, err := ruled.CreateRuleSet(swagger.RuleSetPayload{Rules: []swagger.PolicyRule{{
+			Name: "no 250-packs above 2000",
+			When: &swagger.RuleCondition{MinItems: utils.Ptr(2001)},
+			Then: swagger.PackingPolicy{ForbiddenPackSizes: &[]int{250}},
+		}}})
+		require.NoError(t, err)
+
+		catalog := &mockCatalog{}
+		catalogued := NewPackService(catalog)
+		future := time.Now().Add(time.Hour)
+		for _, payload := range []swagger.PackPayload{
+			{Size: 250, Sku: utils.Ptr("BOX-250")},
+			{Size: 500},
+			{Size: 100, EffectiveFrom: &future},
+		} {
+			_, err := catalogued.CreatePack(payload)
+			require.NoError(t, err)
+		}
+
+		for _, ps := range []*PackService{ruled, catalogued} {
+			calc, err := ps.CalculateRange(1900, 2600, 50)
+			require.NoError(t, err)
+			err = calc.Each(func(result *swagger.CalcResult) error {
+				want, err := ps.CalculatePacks(*result.ItemsOrdered)
+				require.NoError(t, err)
+				assert.Equal(t, want, result)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		var validationErr *ValidationError
+		_, err := ps.CalculateRange(1, MaxRangeQuantity+1, 1)
+		assert.True(t, errors.As(err, &validationErr))
+		_, err = ps.CalculateRange(1, 10, 0)
+		assert.True(t, errors.As(err, &validationErr))
+	})
+}
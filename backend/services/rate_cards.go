@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"sort"
+
+	"ship_line/swagger"
+)
+
+// ErrRateCardNotFound is returned when a rate card ID does not exist.
+var ErrRateCardNotFound = errors.New("rate card not found")
+
+// RateCardRepository is implemented by repositories that can also persist
+// carrier rate cards.
+type RateCardRepository interface {
+	ListRateCards() ([]swagger.RateCard, error)
+	GetRateCard(id string) (*swagger.RateCard, error)
+	CreateRateCard(card *swagger.RateCard) error
+	UpdateRateCard(card *swagger.RateCard) error
+	DeleteRateCard(id string) error
+}
+
+// rateCardRepo returns the rate card storage of the configured repository.
+func (ps *PackService) rateCardRepo() (RateCardRepository, error) {
+	repo, ok := ps.repo.(RateCardRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// rateCards returns the CRUD methods of the stored carrier rate cards.
+func (ps *PackService) rateCards() records[swagger.RateCard, swagger.RateCardPayload] {
+	repo, err := ps.rateCardRepo()
+	if err != nil {
+		return records[swagger.RateCard, swagger.RateCardPayload]{err: err}
+	}
+	return records[swagger.RateCard, swagger.RateCardPayload]{
+		noun:        "rate card",
+		plural:      "rate cards",
+		notFound:    ErrRateCardNotFound,
+		fromPayload: rateCardFromPayload,
+		id:          func(card *swagger.RateCard) *string { return &card.Id },
+		list:        repo.ListRateCards,
+		get:         repo.GetRateCard,
+		create:      repo.CreateRateCard,
+		update:      repo.UpdateRateCard,
+		delete:      repo.DeleteRateCard,
+	}
+}
+
+// ListRateCards retrieves every carrier rate card.
+func (ps *PackService) ListRateCards() ([]swagger.RateCard, error) {
+	return ps.rateCards().listAll()
+}
+
+// GetRateCard retrieves a rate card by ID.
+func (ps *PackService) GetRateCard(id string) (*swagger.RateCard, error) {
+	return ps.rateCards().getOne(id)
+}
+
+// CreateRateCard validates payload and stores it as a new rate card.
+func (ps *PackService) CreateRateCard(payload swagger.RateCardPayload) (*swagger.RateCard, error) {
+	return ps.rateCards().createFrom(payload)
+}
+
+// UpdateRateCard replaces the rate card with the given ID by payload.
+func (ps *PackService) UpdateRateCard(id string, payload swagger.RateCardPayload) (*swagger.RateCard, error) {
+	return ps.rateCards().updateFrom(id, payload)
+}
+
+// DeleteRateCard removes a rate card.
+func (ps *PackService) DeleteRateCard(id string) error {
+	return ps.rateCards().remove(id)
+}
+
+// rateCardFromPayload validates payload and converts it into a RateCard without
+// an ID. The brackets are stored sorted by weight.
+func rateCardFromPayload(payload swagger.RateCardPayload) (*swagger.RateCard, error) {
+	if payload.Carrier == "" {
+		return nil, invalid("carrier is required")
+	}
+	if !validCurrency(payload.Currency) {
+		return nil, invalid("currency must be a three-letter ISO 4217 code")
+	}
+	if payload.DimDivisor <= 0 {
+		return nil, invalid("dimDivisor must be positive")
+	}
+	if len(payload.Brackets) == 0 {
+		return nil, invalid("brackets cannot be empty")
+	}
+	brackets := append([]swagger.WeightBracket{}, payload.Brackets...)
+	for _, bracket := range brackets {
+		if bracket.MaxWeight <= 0 || bracket.Price < 0 {
+			return nil, invalid("brackets need a positive maxWeight and a non-negative price")
+		}
+	}
+	sort.Slice(brackets, func(i, j int) bool { return brackets[i].MaxWeight < brackets[j].MaxWeight })
+	card := &swagger.RateCard{
+		Carrier:    payload.Carrier,
+		Service:    payload.Service,
+		Currency:   payload.Currency,
+		DimDivisor: payload.DimDivisor,
+		Brackets:   brackets,
+	}
+	if payload.ParcelFee != nil {
+		if *payload.ParcelFee < 0 {
+			return nil, invalid("parcelFee must not be negative")
+		}
+		card.ParcelFee = *payload.ParcelFee
+	}
+	return card, nil
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+const (
+	// maxRecommendCandidates caps how many sizes between the bounds are tried.
+	maxRecommendCandidates = 25
+	// maxRecommendRounds caps the swap-improvement rounds after the greedy pick.
+	maxRecommendRounds = 3
+	// MaxRecommendSizes is the largest number of pack sizes that can be requested.
+	MaxRecommendSizes = 10
+)
+
+// RecommendOptions describes the pack set RecommendPackSizes should design.
+type RecommendOptions struct {
+	// K is the number of pack sizes to suggest.
+	K int
+	// Min and Max bound the suggested sizes.
+	Min, Max int
+	// PackWeight is the cost of one pack relative to one item of overage.
+	PackWeight float64
+}
+
+// demand is a histogram of order quantities.
+type demand map[int]int
+
+// RecommendPackSizes suggests K pack sizes between Min and Max that minimise
+// total overage plus PackWeight times the pack count over the given orders.
+// Every candidate set is scored with the same solvers CalculatePacks uses: a
+// greedy forward selection picks sizes one at a time, then single-size swaps are
+// tried until no swap improves the score. The configured pack set is scored
+// against the same orders for comparison.
+func (ps *PackService) RecommendPackSizes(orders []int, opts RecommendOptions) (*swagger.PackSetRecommendation, error) {
+	if len(orders) == 0 {
+		return nil, invalid("no order quantities provided")
+	}
+	if opts.K < 1 || opts.K > MaxRecommendSizes {
+		return nil, invalid(fmt.Sprintf("k must be between 1 and %d", MaxRecommendSizes))
+	}
+	if opts.Min < 1 || opts.Max < opts.Min || opts.Max > MaxRangeQuantity {
+		return nil, invalid(fmt.Sprintf("bounds must satisfy 1 <= min <= max <= %d", MaxRangeQuantity))
+	}
+
+	orderDemand := demand{}
+	for _, q := range orders {
+		orderDemand[q]++
+	}
+	candidates := recommendCandidates(opts.Min, opts.Max, orderDemand)
+	if len(candidates) < opts.K {
+		return nil, invalid(fmt.Sprintf("only %d sizes fit between %d and %d", len(candidates), opts.Min, opts.Max))
+	}
+
+	evaluated := 0
+	score := func(sizes []int) float64 {
+		evaluated++
+		return scorePackSet(sizes, orderDemand).cost(opts.PackWeight)
+	}
+
+	// Greedy forward selection.
+	var chosen []int
+	for len(chosen) < opts.K {
+		best, bestScore := 0, math.Inf(1)
+		for _, c := range candidates {
+			if slices.Contains(chosen, c) {
+				continue
+			}
+			if s := score(append(append([]int{}, chosen...), c)); s < bestScore {
+				best, bestScore = c, s
+			}
+		}
+		chosen = append(chosen, best)
+	}
+
+	// Swap improvement.
+	current := score(chosen)
+	for round := 0; round < maxRecommendRounds; round++ {
+		improved := false
+		for i := range chosen {
+			for _, c := range candidates {
+				if slices.Contains(chosen, c) {
+					continue
+				}
+				trial := append([]int{}, chosen...)
+				trial[i] = c
+				if s := score(trial); s < current {
+					chosen, current, improved = trial, s, true
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	recommendation := &swagger.PackSetRecommendation{
+		PackSizes:           sortedAsc(chosen),
+		Score:               scorePackSet(chosen, orderDemand).toSwagger(len(orders)),
+		Orders:              len(orders),
+		CandidatesEvaluated: evaluated,
+	}
+	if configured, _, err := ps.loadPackSet(time.Time{}); err == nil && len(configured) > 0 {
+		recommendation.Current = utils.Ptr(scorePackSet(configured, orderDemand).toSwagger(len(orders)))
+	}
+	return recommendation, nil
+}
+
+// recommendCandidates returns, in ascending order, the sizes in [lo, hi] worth
+// trying: at most maxRecommendCandidates sizes spread evenly over the bounds,
+// including both, the round sizes (1, 2, 2.5 and 5 times a power of ten) and
+// the maxRecommendCandidates most frequent order quantities.
+func recommendCandidates(lo, hi int, d demand) []int {
+	step := max(1, (hi-lo+maxRecommendCandidates-2)/(maxRecommendCandidates-1))
+	var candidates []int
+	for c := lo; c < hi; c += step {
+		candidates = append(candidates, c)
+	}
+	candidates = append(candidates, hi)
+
+	for scale := 1; scale <= hi; scale *= 10 {
+		for _, round := range []int{scale, 2 * scale, 5 * scale / 2, 5 * scale} {
+			if round >= lo && round <= hi {
+				candidates = append(candidates, round)
+			}
+		}
+	}
+
+	var observed []int
+	for q := range d {
+		if q >= lo && q <= hi {
+			observed = append(observed, q)
+		}
+	}
+	slices.SortFunc(observed, func(a, b int) int {
+		if d[a] != d[b] {
+			return d[b] - d[a]
+		}
+		return a - b
+	})
+	candidates = append(candidates, observed[:min(len(observed), maxRecommendCandidates)]...)
+
+	slices.Sort(candidates)
+	return slices.Compact(candidates)
+}
+
+// packSetScore accumulates overage and pack counts over a demand histogram.
+type packSetScore struct {
+	overage int
+	packs   int
+}
+
+// cost combines the score into a single objective value.
+func (s packSetScore) cost(packWeight float64) float64 {
+	return float64(s.overage) + packWeight*float64(s.packs)
+}
+
+// toSwagger converts the score for a sample of n orders.
+func (s packSetScore) toSwagger(n int) swagger.PackSetScore {
+	return swagger.PackSetScore{
+		TotalOverage:   s.overage,
+		TotalPacks:     s.packs,
+		AverageOverage: float64(s.overage) / float64(n),
+		AveragePacks:   float64(s.packs) / float64(n),
+	}
+}
+
+// scorePackSet runs every quantity in d through the pack solvers. Quantities in
+// the DP window share one rangeSolver pass; larger ones use calculatePacks.
+func scorePackSet(packSizes []int, d demand) packSetScore {
+	sizes := sortedDesc(packSizes)
+	largestInWindow := 0
+	for q := range d {
+		if q <= MaxRangeQuantity {
+			largestInWindow = max(largestInWindow, q)
+		}
+	}
+	var solver *rangeSolver
+	if largestInWindow > 0 {
+		solver = newRangeSolver(largestInWindow, sizes)
+	}
+
+	var score packSetScore
+	for q, n := range d {
+		if q == 0 {
+			continue
+		}
+		if q <= MaxRangeQuantity {
+			total := solver.next[q]
+			score.overage += n * (total - q)
+			score.packs += n * solver.table.packs[total]
+			continue
+		}
+		result, err := calculatePacks(q, append([]int{}, sizes...))
+		if err != nil {
+			continue
+		}
+		score.overage += n * (*result.TotalItemsUsed - q)
+		for _, count := range *result.PacksUsed {
+			score.packs += n * count
+		}
+	}
+	return score
+}
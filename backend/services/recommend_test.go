@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendPackSizes(t *testing.T) {
+	ps := NewPackService(&mockPackRepo{sizes: []int{250, 500}})
+
+	t.Run("FindsExactSizes", func(t *testing.T) {
+		orders := []int{300, 300, 600, 600, 600}
+		rec, err := ps.RecommendPackSizes(orders, RecommendOptions{K: 2, Min: 100, Max: 700, PackWeight: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []int{300, 600}, rec.PackSizes)
+		assert.Equal(t, 0, rec.Score.TotalOverage)
+		assert.Equal(t, 5, rec.Score.TotalPacks)
+		assert.Equal(t, 5, rec.Orders)
+		require.NotNil(t, rec.Current)
+		assert.Equal(t, 850, rec.Current.TotalOverage)
+	})
+
+	t.Run("LargeOrdersUseGreedySolver", func(t *testing.T) {
+		rec, err := ps.RecommendPackSizes([]int{250000, 500}, RecommendOptions{K: 1, Min: 250, Max: 5000})
+		require.NoError(t, err)
+		assert.Len(t, rec.PackSizes, 1)
+	})
+
+	t.Run("TriesRoundAndObservedSizes", func(t *testing.T) {
+		assert.Subset(t, recommendCandidates(100, 5000, demand{1234: 1}), []int{250, 500, 1000, 1234, 2500})
+		orders := []int{1000, 1000, 2000, 2000, 2000, 4321}
+		rec, err := ps.RecommendPackSizes(orders, RecommendOptions{K: 3, Min: 100, Max: 5000, PackWeight: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1000, 2000, 4321}, rec.PackSizes)
+		assert.Equal(t, 0, rec.Score.TotalOverage)
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		var validationErr *ValidationError
+		_, err := ps.RecommendPackSizes([]int{1}, RecommendOptions{K: 0, Min: 1, Max: 2})
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.RecommendPackSizes([]int{1}, RecommendOptions{K: 3, Min: 1, Max: 2})
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.RecommendPackSizes(nil, RecommendOptions{K: 1, Min: 1, Max: 2})
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.RecommendPackSizes([]int{1}, RecommendOptions{K: 1, Min: 1, Max: MaxRangeQuantity + 1})
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
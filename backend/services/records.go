@@ -0,0 +1,93 @@
+package services
+
+import "fmt"
+
+// records implements the CRUD methods shared by the kinds of record that are
+// stored as a whole and validated from a payload of type P, such as rate cards
+// or warehouses. A records with err set fails every call with err.
+type records[T, P any] struct {
+	err error
+	// noun and plural name the kind of record in error messages.
+	noun, plural string
+	notFound     error
+	fromPayload  func(payload P) (*T, error)
+	id           func(record *T) *string
+
+	list   func() ([]T, error)
+	get    func(id string) (*T, error)
+	create func(record *T) error
+	update func(record *T) error
+	delete func(id string) error
+}
+
+// listAll retrieves every record, as an empty slice if there is none.
+func (r records[T, P]) listAll() ([]T, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	all, err := r.list()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", r.plural, err)
+	}
+	if all == nil {
+		all = []T{}
+	}
+	return all, nil
+}
+
+// getOne retrieves a record by ID. It returns notFound if it does not exist.
+func (r records[T, P]) getOne(id string) (*T, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	record, err := r.get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", r.noun, err)
+	}
+	if record == nil {
+		return nil, r.notFound
+	}
+	return record, nil
+}
+
+// createFrom validates payload and stores it as a new record.
+func (r records[T, P]) createFrom(payload P) (*T, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	record, err := r.fromPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.create(record); err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", r.noun, err)
+	}
+	return record, nil
+}
+
+// updateFrom replaces the record with the given ID by payload.
+func (r records[T, P]) updateFrom(id string, payload P) (*T, error) {
+	if _, err := r.getOne(id); err != nil {
+		return nil, err
+	}
+	record, err := r.fromPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	*r.id(record) = id
+	if err := r.update(record); err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", r.noun, err)
+	}
+	return record, nil
+}
+
+// remove deletes the record with the given ID.
+func (r records[T, P]) remove(id string) error {
+	if _, err := r.getOne(id); err != nil {
+		return err
+	}
+	if err := r.delete(id); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", r.noun, err)
+	}
+	return nil
+}
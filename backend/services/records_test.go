@@ -0,0 +1,102 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+)
+
+// mockRecords stores records of type T in memory for the mock repositories.
+type mockRecords[T any] struct {
+	seq     int
+	ids     []string
+	records map[string]T
+}
+
+func (m *mockRecords[T]) list() ([]T, error) {
+	var records []T
+	for _, id := range m.ids {
+		records = append(records, m.records[id])
+	}
+	return records, nil
+}
+
+func (m *mockRecords[T]) get(id string) (*T, error) {
+	record, ok := m.records[id]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// create assigns the next ID to *id and stores record under it.
+func (m *mockRecords[T]) create(record *T, id *string) error {
+	if m.records == nil {
+		m.records = map[string]T{}
+	}
+	m.seq++
+	*id = strconv.Itoa(m.seq)
+	m.ids = append(m.ids, *id)
+	m.records[*id] = *record
+	return nil
+}
+
+func (m *mockRecords[T]) update(id string, record *T) error {
+	if _, ok := m.records[id]; ok {
+		m.records[id] = *record
+	}
+	return nil
+}
+
+func (m *mockRecords[T]) delete(id string) error {
+	for i := range m.ids {
+		if m.ids[i] == id {
+			m.ids = append(m.ids[:i], m.ids[i+1:]...)
+			delete(m.records, id)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestRecords(t *testing.T) {
+	t.Run("NotSupported", func(t *testing.T) {
+		ps := NewPackService(&mockPackRepo{})
+		_, err := ps.ListKits()
+		assert.ErrorIs(t, err, ErrNotSupported)
+		_, err = ps.UpdateKit("1", swagger.KitPayload{})
+		assert.ErrorIs(t, err, ErrNotSupported)
+		assert.ErrorIs(t, ps.DeleteKit("1"), ErrNotSupported)
+	})
+
+	ps := NewPackService(&mockKitRepo{})
+	kits, err := ps.ListKits()
+	require.NoError(t, err)
+	assert.NotNil(t, kits)
+	assert.Empty(t, kits)
+
+	_, err = ps.GetKit("1")
+	assert.ErrorIs(t, err, ErrKitNotFound)
+	_, err = ps.UpdateKit("1", swagger.KitPayload{Name: "Starter", Contents: map[string]int{"A": 1}})
+	assert.ErrorIs(t, err, ErrKitNotFound)
+
+	kit, err := ps.CreateKit(swagger.KitPayload{Name: "Starter", Contents: map[string]int{"A": 1}})
+	require.NoError(t, err)
+	assert.Equal(t, "1", kit.Id)
+	_, err = ps.CreateKit(swagger.KitPayload{Contents: map[string]int{"A": 1}})
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	kit, err = ps.UpdateKit("1", swagger.KitPayload{Name: "Refill", Contents: map[string]int{"A": 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "1", kit.Id)
+	kit, err = ps.GetKit("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Refill", kit.Name)
+
+	require.NoError(t, ps.DeleteKit("1"))
+	assert.ErrorIs(t, ps.DeleteKit("1"), ErrKitNotFound)
+}
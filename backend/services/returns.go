@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ReturnPacks books packs a customer returned against a stored order.
+// The returned packs must have been shipped with the order and not returned
+// before. Unopened packs are put back into the stock of the warehouse named by
+// the payload, or of the only warehouse stored, so that fulfilment plans use
+// them again; sizes the warehouse does not limit stay unlimited. The items
+// left in opened packs go to the loose-item pool, and the return is recorded
+// in the history of the order. The stock is booked first and taken back when
+// the order cannot be updated.
+func (ps *PackService) ReturnPacks(id string, payload swagger.ReturnPayload) (*swagger.Order, error) {
+	repo, err := ps.orderRepo()
+	if err != nil {
+		return nil, err
+	}
+	pool, err := ps.looseStockRepo()
+	if err != nil {
+		return nil, err
+	}
+	order, err := ps.GetOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	returnable, err := returnablePacks(order)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload.Packs) == 0 {
+		return nil, invalid("at least one pack must be returned")
+	}
+	returned, restocked := map[int]int{}, map[int]int{}
+	loose := 0
+	for i, pack := range payload.Packs {
+		if pack.Count <= 0 {
+			return nil, invalid(fmt.Sprintf("packs[%d]: count must be positive", i))
+		}
+		if _, shipped := returnable[pack.Size]; !shipped {
+			return nil, invalid(fmt.Sprintf("packs[%d]: no packs of size %d were shipped with this order", i, pack.Size))
+		}
+		returned[pack.Size] += pack.Count
+		if pack.Opened == nil || !*pack.Opened {
+			if pack.Items != nil {
+				return nil, invalid(fmt.Sprintf("packs[%d]: items only applies to opened packs", i))
+			}
+			restocked[pack.Size] += pack.Count
+			continue
+		}
+		items := pack.Count * pack.Size
+		if pack.Items != nil {
+			if *pack.Items < 0 || *pack.Items > items {
+				return nil, invalid(fmt.Sprintf("packs[%d]: items must be between 0 and %d", i, items))
+			}
+			items = *pack.Items
+		}
+		loose += items
+	}
+	for size, count := range returned {
+		if count > returnable[size] {
+			return nil, invalid(fmt.Sprintf("only %d packs of size %d can still be returned", returnable[size], size))
+		}
+	}
+
+	var warehouse *swagger.Warehouse
+	if len(restocked) > 0 {
+		if warehouse, err = ps.returnWarehouse(payload.WarehouseId); err != nil {
+			return nil, err
+		}
+	}
+
+	event := swagger.OrderEvent{
+		At:            time.Now().UTC(),
+		Type:          swagger.Returned,
+		PacksReturned: utils.Ptr(utils.ConvertMapKeys(returned)),
+	}
+	if len(restocked) > 0 {
+		event.PacksRestocked = utils.Ptr(utils.ConvertMapKeys(restocked))
+	}
+	if warehouse != nil {
+		event.WarehouseId = utils.Ptr(warehouse.Id)
+	}
+	if loose > 0 {
+		event.LooseItemsReturned = utils.Ptr(loose)
+	}
+
+	// Book the stock before the order, and take it back if a later step
+	// fails, so a failed return can be retried without counting twice.
+	if warehouse != nil {
+		if err := ps.restock(warehouse, restocked); err != nil {
+			return nil, err
+		}
+	}
+	unstock := func() {
+		if warehouse != nil {
+			_ = ps.restock(warehouse, negated(restocked))
+		}
+	}
+	if loose > 0 {
+		if err := pool.AddLooseStock(loose); err != nil {
+			unstock()
+			return nil, fmt.Errorf("failed to add returned items to loose stock: %w", err)
+		}
+	}
+	order.History = utils.Ptr(append(history(order), event))
+	if err := repo.UpdateOrder(order); err != nil {
+		unstock()
+		if loose > 0 {
+			_, _ = pool.TakeLooseStock(loose)
+		}
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+	return order, nil
+}
+
+// negated returns packs with every count negated.
+func negated(packs map[int]int) map[int]int {
+	negated := make(map[int]int, len(packs))
+	for size, count := range packs {
+		negated[size] = -count
+	}
+	return negated
+}
+
+// returnWarehouse returns the warehouse returned unopened packs go back to:
+// the one with the given ID, or else the only one stored. It returns nil when
+// no warehouse is stored, as pack stock is then not limited anywhere.
+func (ps *PackService) returnWarehouse(id *string) (*swagger.Warehouse, error) {
+	if id != nil {
+		return ps.GetWarehouse(*id)
+	}
+	warehouses, err := ps.ListWarehouses()
+	if errors.Is(err, ErrNotSupported) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch len(warehouses) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &warehouses[0], nil
+	}
+	return nil, invalid("warehouseId is required to restock unopened packs when several warehouses are stored")
+}
+
+// restock adds packs to the stock of warehouse. Sizes the warehouse does not
+// limit are left unlimited.
+func (ps *PackService) restock(warehouse *swagger.Warehouse, packs map[int]int) error {
+	if warehouse.Stock == nil {
+		return nil
+	}
+	repo, err := ps.warehouseRepo()
+	if err != nil {
+		return err
+	}
+	stock := utils.CopyMap(*warehouse.Stock)
+	for size, count := range packs {
+		if _, capped := stock[strconv.Itoa(size)]; capped {
+			stock[strconv.Itoa(size)] += count
+		}
+	}
+	warehouse.Stock = &stock
+	if err := repo.UpdateWarehouse(warehouse); err != nil {
+		return fmt.Errorf("failed to restock warehouse %s: %w", warehouse.Id, err)
+	}
+	return nil
+}
+
+// returnablePacks returns, per size, the packs shipped with order that have
+// not been returned yet.
+func returnablePacks(order *swagger.Order) (map[int]int, error) {
+	shipped := map[int]int{}
+	if order.Result.PacksUsed != nil {
+		var err error
+		if shipped, err = utils.ParseMapKeys(*order.Result.PacksUsed); err != nil {
+			return nil, fmt.Errorf("failed to read stored packs: %w", err)
+		}
+	}
+	for _, event := range history(order) {
+		if event.Type != swagger.Returned || event.PacksReturned == nil {
+			continue
+		}
+		returned, err := utils.ParseMapKeys(*event.PacksReturned)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read returned packs: %w", err)
+		}
+		for size, count := range returned {
+			shipped[size] -= count
+		}
+	}
+	return shipped, nil
+}
+
+// history returns the recorded changes to order, oldest first.
+func history(order *swagger.Order) []swagger.OrderEvent {
+	if order.History == nil {
+		return nil
+	}
+	return *order.History
+}
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ErrRuleSetNotFound is returned when a rule set version does not exist.
+var ErrRuleSetNotFound = errors.New("rule set not found")
+
+// MaxRuleSamples is the largest number of sample orders a rule set test may
+// calculate.
+const MaxRuleSamples = 1000
+
+// RuleSetRepository is implemented by repositories that can also persist
+// versions of the packing rules. Versions are never changed once stored; the
+// latest one is active.
+type RuleSetRepository interface {
+	ListRuleSets() ([]swagger.RuleSet, error)
+	GetRuleSet(version int) (*swagger.RuleSet, error)
+	LatestRuleSet() (*swagger.RuleSet, error)
+	CreateRuleSet(set *swagger.RuleSet) error
+}
+
+// ruleSetRepo returns the rule set storage of the configured repository.
+func (ps *PackService) ruleSetRepo() (RuleSetRepository, error) {
+	repo, ok := ps.repo.(RuleSetRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// ListRuleSets retrieves every stored rule set version, oldest first.
+func (ps *PackService) ListRuleSets() ([]swagger.RuleSet, error) {
+	repo, err := ps.ruleSetRepo()
+	if err != nil {
+		return nil, err
+	}
+	sets, err := repo.ListRuleSets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rule sets: %w", err)
+	}
+	if sets == nil {
+		sets = []swagger.RuleSet{}
+	}
+	return sets, nil
+}
+
+// GetRuleSet retrieves a rule set version.
+func (ps *PackService) GetRuleSet(version int) (*swagger.RuleSet, error) {
+	repo, err := ps.ruleSetRepo()
+	if err != nil {
+		return nil, err
+	}
+	set, err := repo.GetRuleSet(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule set: %w", err)
+	}
+	if set == nil {
+		return nil, ErrRuleSetNotFound
+	}
+	return set, nil
+}
+
+// CreateRuleSet validates payload and stores it as the next version, which
+// becomes the active rule set.
+func (ps *PackService) CreateRuleSet(payload swagger.RuleSetPayload) (*swagger.RuleSet, error) {
+	repo, err := ps.ruleSetRepo()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := validRules(payload.Rules)
+	if err != nil {
+		return nil, err
+	}
+	set := &swagger.RuleSet{Rules: rules, CreatedAt: time.Now().UTC()}
+	if err := repo.CreateRuleSet(set); err != nil {
+		return nil, fmt.Errorf("failed to store rule set: %w", err)
+	}
+	return set, nil
+}
+
+// TestRuleSet calculates every sample order under a rule set and reports the
+// rules that matched, the constraints they produced and the outcome. The rule
+// set is the draft in test, the stored version it names or the active one.
+func (ps *PackService) TestRuleSet(test swagger.RuleSetTest) (*swagger.RuleSetTestResult, error) {
+	if len(test.Orders) == 0 || len(test.Orders) > MaxRuleSamples {
+		return nil, invalid(fmt.Sprintf("between 1 and %d sample orders are required", MaxRuleSamples))
+	}
+	report := &swagger.RuleSetTestResult{Results: make([]swagger.RuleSampleResult, 0, len(test.Orders))}
+	var rules []swagger.PolicyRule
+	switch {
+	case test.Rules != nil:
+		var err error
+		if rules, err = validRules(*test.Rules); err != nil {
+			return nil, err
+		}
+	case test.Version != nil:
+		set, err := ps.GetRuleSet(*test.Version)
+		if err != nil {
+			return nil, err
+		}
+		rules, report.Version = set.Rules, utils.Ptr(set.Version)
+	default:
+		set, err := ps.activeRuleSet()
+		if err != nil {
+			return nil, err
+		}
+		if set != nil {
+			rules, report.Version = set.Rules, utils.Ptr(set.Version)
+		}
+	}
+	if rules == nil {
+		rules = []swagger.PolicyRule{}
+	}
+
+	for i, sample := range test.Orders {
+		if sample.Items < 0 {
+			return nil, invalidFields([]swagger.FieldError{{Field: fmt.Sprintf("orders.%d.items", i), Message: "must not be negative"}})
+		}
+		opts := CalcOptions{Rules: rules}
+		if sample.Attributes != nil {
+			opts.Attributes = *sample.Attributes
+		}
+		opts, matched, err := ps.withRules(sample.Items, opts)
+		if err != nil {
+			return nil, err
+		}
+		outcome := swagger.RuleSampleResult{Items: sample.Items, MatchedRules: matched, Policy: policyOf(opts)}
+		result, err := ps.CalculatePacksWithOptions(sample.Items, opts)
+		if err != nil {
+			outcome.Error = utils.Ptr(err.Error())
+		} else {
+			outcome.Result = result
+		}
+		report.Results = append(report.Results, outcome)
+	}
+	return report, nil
+}
+
+// activeRuleSet returns the latest stored rule set, or nil if there is none or
+// rule sets cannot be stored.
+func (ps *PackService) activeRuleSet() (*swagger.RuleSet, error) {
+	repo, err := ps.ruleSetRepo()
+	if err != nil {
+		return nil, nil
+	}
+	set, err := repo.LatestRuleSet()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule set: %w", err)
+	}
+	return set, nil
+}
+
+// withRules returns opts constrained by the rules that match an order of
+// items, evaluated in order, together with the names of those rules. opts.Rules
+// selects the rules; nil means the active rule set. The returned options hold
+// an empty rule list so that the rules are not applied again.
+func (ps *PackService) withRules(items int, opts CalcOptions) (CalcOptions, []string, error) {
+	rules := opts.Rules
+	if rules == nil {
+		set, err := ps.activeRuleSet()
+		if err != nil {
+			return opts, nil, err
+		}
+		if set != nil {
+			rules = set.Rules
+		}
+	}
+	matched := []string{}
+	for _, rule := range rules {
+		if rule.When == nil || ruleMatches(*rule.When, items, opts.Attributes) {
+			opts = withPolicy(opts, rule.Then)
+			matched = append(matched, rule.Name)
+		}
+	}
+	opts.Rules = []swagger.PolicyRule{}
+	return opts, matched, nil
+}
+
+// ruleMatches reports whether an order of items with attributes meets every
+// field of cond.
+func ruleMatches(cond swagger.RuleCondition, items int, attributes map[string]string) bool {
+	if cond.MinItems != nil && items < *cond.MinItems {
+		return false
+	}
+	if cond.MaxItems != nil && items > *cond.MaxItems {
+		return false
+	}
+	if cond.Attributes != nil {
+		for key, value := range *cond.Attributes {
+			if actual, ok := attributes[key]; !ok || actual != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validRules validates rules and returns a copy that shares no slices with
+// them. Broken rules are reported as field errors.
+func validRules(rules []swagger.PolicyRule) ([]swagger.PolicyRule, error) {
+	var fields []swagger.FieldError
+	fail := func(i int, field, message string) {
+		fields = append(fields, swagger.FieldError{Field: fmt.Sprintf("rules.%d.%s", i, field), Message: message})
+	}
+	names := map[string]bool{}
+	valid := make([]swagger.PolicyRule, len(rules))
+	for i, rule := range rules {
+		switch {
+		case rule.Name == "":
+			fail(i, "name", "is required")
+		case names[rule.Name]:
+			fail(i, "name", "must be unique")
+		}
+		names[rule.Name] = true
+		if cond := rule.When; cond != nil {
+			if cond.MinItems != nil && *cond.MinItems < 0 {
+				fail(i, "when.minItems", "must not be negative")
+			}
+			if cond.MaxItems != nil && cond.MinItems != nil && *cond.MaxItems < *cond.MinItems {
+				fail(i, "when.maxItems", "must not be below minItems")
+			}
+			if cond.Attributes != nil {
+				for key := range *cond.Attributes {
+					if key == "" {
+						fail(i, "when.attributes", "keys must not be empty")
+					}
+				}
+				rule.When = &swagger.RuleCondition{
+					MinItems:   cond.MinItems,
+					MaxItems:   cond.MaxItems,
+					Attributes: utils.Ptr(utils.CopyMap(*cond.Attributes)),
+				}
+			}
+		}
+		policy, err := validPolicy(rule.Then)
+		if err != nil {
+			fail(i, "then", err.Error())
+			continue
+		}
+		rule.Then = *policy
+		valid[i] = rule
+	}
+	if len(fields) > 0 {
+		return nil, invalidFields(fields)
+	}
+	return valid, nil
+}
+
+// policyOf reports the packing constraints in opts as a policy.
+func policyOf(opts CalcOptions) swagger.PackingPolicy {
+	var policy swagger.PackingPolicy
+	if len(opts.ExcludedSizes) > 0 {
+		sizes := slices.Clone(opts.ExcludedSizes)
+		slices.Sort(sizes)
+		policy.ForbiddenPackSizes = utils.Ptr(slices.Compact(sizes))
+	}
+	policy.MaxOveragePercent = opts.MaxOveragePercent
+	if opts.MaxPacks > 0 {
+		policy.MaxPacks = utils.Ptr(opts.MaxPacks)
+	}
+	if len(opts.MinShares) > 0 {
+		shares := make([]swagger.PackShare, 0, len(opts.MinShares))
+		for size, percent := range opts.MinShares {
+			shares = append(shares, swagger.PackShare{Size: size, Percent: percent})
+		}
+		sort.Slice(shares, func(i, j int) bool { return shares[i].Size > shares[j].Size })
+		policy.MinPackShares = &shares
+	}
+	switch opts.Objective {
+	case ObjectivePrice:
+		policy.Objective = utils.Ptr(swagger.Price)
+	case ObjectiveShipping:
+		policy.Objective = utils.Ptr(swagger.Shipping)
+	}
+	return policy
+}
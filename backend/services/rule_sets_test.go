@@ -0,0 +1,181 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// mockRuleSetRepo is a mockPackRepo that also stores rule sets.
+type mockRuleSetRepo struct {
+	mockPackRepo
+	sets []swagger.RuleSet
+}
+
+func (m *mockRuleSetRepo) ListRuleSets() ([]swagger.RuleSet, error) {
+	return m.sets, nil
+}
+
+func (m *mockRuleSetRepo) GetRuleSet(version int) (*swagger.RuleSet, error) {
+	if version < 1 || version > len(m.sets) {
+		return nil, nil
+	}
+	return &m.sets[version-1], nil
+}
+
+func (m *mockRuleSetRepo) LatestRuleSet() (*swagger.RuleSet, error) {
+	return m.GetRuleSet(len(m.sets))
+}
+
+func (m *mockRuleSetRepo) CreateRuleSet(set *swagger.RuleSet) error {
+	set.Version = len(m.sets) + 1
+	m.sets = append(m.sets, *set)
+	return nil
+}
+
+func TestRuleMatches(t *testing.T) {
+	cond := swagger.RuleCondition{
+		MinItems:   utils.Ptr(100),
+		MaxItems:   utils.Ptr(1000),
+		Attributes: &map[string]string{"channel": "wholesale"},
+	}
+	wholesale := map[string]string{"channel": "wholesale", "region": "eu"}
+	assert.True(t, ruleMatches(cond, 100, wholesale))
+	assert.True(t, ruleMatches(cond, 1000, wholesale))
+	assert.False(t, ruleMatches(cond, 99, wholesale))
+	assert.False(t, ruleMatches(cond, 1001, wholesale))
+	assert.False(t, ruleMatches(cond, 500, map[string]string{"channel": "retail"}))
+	assert.False(t, ruleMatches(cond, 500, nil))
+	assert.True(t, ruleMatches(swagger.RuleCondition{}, 0, nil))
+}
+
+func TestCalculateWithRules(t *testing.T) {
+	ps := NewPackService(&mockRuleSetRepo{mockPackRepo: mockPackRepo{sizes: []int{250, 500, 1000, 2000, 5000}}})
+	_, err := ps.CreateRuleSet(swagger.RuleSetPayload{Rules: []swagger.PolicyRule{
+		{
+			Name: "half in 1000-packs",
+			When: &swagger.RuleCondition{MinItems: utils.Ptr(10000)},
+			Then: swagger.PackingPolicy{MinPackShares: &[]swagger.PackShare{{Size: 1000, Percent: 50}}},
+		},
+		{
+			Name: "no 250-packs for wholesale",
+			When: &swagger.RuleCondition{Attributes: &map[string]string{"channel": "wholesale"}},
+			Then: swagger.PackingPolicy{ForbiddenPackSizes: &[]int{250}},
+		},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		order      int
+		attributes map[string]string
+		expected   map[string]int
+	}{
+		{"NoMatch", 501, nil, map[string]int{"500": 1, "250": 1}},
+		{"Attribute", 501, map[string]string{"channel": "wholesale"}, map[string]int{"1000": 1}},
+		{"Share", 10000, nil, map[string]int{"1000": 5, "5000": 1}},
+		{"ShareAndAttribute", 10001, map[string]string{"channel": "wholesale"}, map[string]int{"1000": 6, "2000": 2, "500": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ps.CalculatePacksWithOptions(tt.order, CalcOptions{Attributes: tt.attributes})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *res.PacksUsed)
+			assert.Equal(t, tt.order, *res.ItemsOrdered)
+		})
+	}
+
+	t.Run("ExplicitRulesReplaceTheActiveSet", func(t *testing.T) {
+		res, err := ps.CalculatePacksWithOptions(10000, CalcOptions{Rules: []swagger.PolicyRule{}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"5000": 2}, *res.PacksUsed)
+	})
+
+	t.Run("ShareCoversOrder", func(t *testing.T) {
+		rules := []swagger.PolicyRule{{Name: "all", Then: swagger.PackingPolicy{MinPackShares: &[]swagger.PackShare{{Size: 1000, Percent: 100}}}}}
+		res, err := ps.CalculatePacksWithOptions(2500, CalcOptions{Rules: rules})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"1000": 3}, *res.PacksUsed)
+		assert.Equal(t, 3000, *res.TotalItemsUsed)
+	})
+
+	t.Run("ShareWithPriceObjective", func(t *testing.T) {
+		_, err := ps.CalculatePacksWithOptions(10000, CalcOptions{Objective: ObjectivePrice})
+		var validation *ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+}
+
+func TestTestRuleSet(t *testing.T) {
+	ps := NewPackService(&mockRuleSetRepo{mockPackRepo: mockPackRepo{sizes: []int{250, 500, 1000, 2000, 5000}}})
+	rules := []swagger.PolicyRule{
+		{
+			Name: "large orders",
+			When: &swagger.RuleCondition{MinItems: utils.Ptr(100001)},
+			Then: swagger.PackingPolicy{MinPackShares: &[]swagger.PackShare{{Size: 5000, Percent: 80}}, MaxPacks: utils.Ptr(30)},
+		},
+		{
+			Name: "small orders",
+			When: &swagger.RuleCondition{MaxItems: utils.Ptr(1000)},
+			Then: swagger.PackingPolicy{MaxOveragePercent: utils.Ptr(10.0)},
+		},
+	}
+
+	report, err := ps.TestRuleSet(swagger.RuleSetTest{
+		Rules:  &rules,
+		Orders: []swagger.RuleSample{{Items: 120000}, {Items: 250000}, {Items: 260}, {Items: 5000}},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, report.Version)
+	require.Len(t, report.Results, 4)
+
+	large := report.Results[0]
+	assert.Equal(t, []string{"large orders"}, large.MatchedRules)
+	assert.Equal(t, swagger.PackingPolicy{
+		MaxPacks:      utils.Ptr(30),
+		MinPackShares: &[]swagger.PackShare{{Size: 5000, Percent: 80}},
+	}, large.Policy)
+	require.NotNil(t, large.Result)
+	assert.Equal(t, map[string]int{"5000": 24}, *large.Result.PacksUsed)
+
+	// 250000 items need 50 packs, more than the rule allows.
+	assert.Nil(t, report.Results[1].Result)
+	require.NotNil(t, report.Results[1].Error)
+	assert.Contains(t, *report.Results[1].Error, "at most 30")
+
+	// 260 items round up to 500, an overage of 92%.
+	assert.Equal(t, []string{"small orders"}, report.Results[2].MatchedRules)
+	require.NotNil(t, report.Results[2].Error)
+
+	assert.Empty(t, report.Results[3].MatchedRules)
+	assert.Equal(t, map[string]int{"5000": 1}, *report.Results[3].Result.PacksUsed)
+
+	t.Run("StoredVersion", func(t *testing.T) {
+		_, err := ps.CreateRuleSet(swagger.RuleSetPayload{Rules: rules})
+		require.NoError(t, err)
+		report, err := ps.TestRuleSet(swagger.RuleSetTest{Orders: []swagger.RuleSample{{Items: 260}}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, *report.Version)
+		assert.Equal(t, []string{"small orders"}, report.Results[0].MatchedRules)
+
+		_, err = ps.TestRuleSet(swagger.RuleSetTest{Version: utils.Ptr(2), Orders: []swagger.RuleSample{{Items: 260}}})
+		assert.ErrorIs(t, err, ErrRuleSetNotFound)
+	})
+
+	t.Run("InvalidRules", func(t *testing.T) {
+		_, err := ps.CreateRuleSet(swagger.RuleSetPayload{Rules: []swagger.PolicyRule{
+			{Name: "a", When: &swagger.RuleCondition{MinItems: utils.Ptr(10), MaxItems: utils.Ptr(5)}},
+			{Name: "a", Then: swagger.PackingPolicy{MaxPacks: utils.Ptr(0)}},
+		}})
+		var validation *ValidationError
+		require.ErrorAs(t, err, &validation)
+		assert.Equal(t, []swagger.FieldError{
+			{Field: "rules.0.when.maxItems", Message: "must not be below minItems"},
+			{Field: "rules.1.name", Message: "must be unique"},
+			{Field: "rules.1.then", Message: "maxPacks must be positive"},
+		}, validation.Fields)
+	})
+}
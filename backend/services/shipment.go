@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ErrShipmentLimits is returned when no distribution of the order fits within
+// the requested weight and volume limits.
+var ErrShipmentLimits = errors.New("no pack distribution fits within the shipment weight and volume limits")
+
+// limitTolerance absorbs floating-point noise when comparing totals to limits.
+const limitTolerance = 1e-9
+
+// packVolume returns the outer volume of a pack in cubic centimetres, or 0 if
+// its dimensions are unknown.
+func packVolume(pack swagger.Pack) float64 {
+	if pack.Dimensions == nil {
+		return 0
+	}
+	return pack.Dimensions.Length * pack.Dimensions.Width * pack.Dimensions.Height
+}
+
+// withShipmentTotals adds the number of shipping units (packs) of result and,
+// when every pack used has a weight or dimensions in the catalogue, the total
+// gross weight and volume. Results without a catalogue are returned unchanged.
+func withShipmentTotals(result *swagger.CalcResult, bySize map[int]swagger.Pack) *swagger.CalcResult {
+	if bySize == nil || result == nil || result.PacksUsed == nil {
+		return result
+	}
+	units := 0
+	weight, volume := 0.0, 0.0
+	weighed, measured := true, true
+	for key, count := range *result.PacksUsed {
+		size, err := strconv.Atoi(key)
+		if err != nil {
+			continue
+		}
+		units += count
+		pack, ok := bySize[size]
+		if !ok || pack.Weight == nil {
+			weighed = false
+		} else {
+			weight += *pack.Weight * float64(count)
+		}
+		if !ok || pack.Dimensions == nil {
+			measured = false
+		} else {
+			volume += packVolume(pack) * float64(count)
+		}
+	}
+	result.ShippingUnits = utils.Ptr(units)
+	if weighed {
+		result.TotalWeight = utils.Ptr(math.Round(weight*1000) / 1000)
+	}
+	if measured {
+		result.TotalVolume = utils.Ptr(math.Round(volume*1000) / 1000)
+	}
+	return result
+}
+
+// solveWithinLimits finds the distribution with the least overage whose total
+// weight and volume stay within opts.MaxWeight and opts.MaxVolume, and whose
+// overage and pack count stay within the caps of opts.
+//
+// Each pack is charged its share of the limits (weight/MaxWeight plus
+// volume/MaxVolume, plus 1/MaxPacks under a pack cap) and, for every total,
+// the distribution with the smallest charge is tabulated; the first total from
+// the order upwards whose distribution fits is chosen. With a single limit
+// this is exact. With several limits the combined charge is a heuristic that
+// may miss a distribution that only just fits one of them.
+func solveWithinLimits(order int, packSizes []int, bySize map[int]swagger.Pack, opts CalcOptions) (*swagger.CalcResult, error) {
+	if bySize == nil {
+		return nil, ErrNotSupported
+	}
+	if opts.Mode != ModeDefault || opts.Availability != nil || opts.Objective != ObjectivePacks {
+		return nil, invalid("weight and volume limits cannot be combined with a mode, availability caps or another objective")
+	}
+	if opts.MaxWeight < 0 || opts.MaxVolume < 0 {
+		return nil, invalid("maxWeight and maxVolume must not be negative")
+	}
+
+	limits := policyLimits(order, opts)
+	weight := make(map[int]float64, len(packSizes))
+	volume := make(map[int]float64, len(packSizes))
+	load := make(map[int]float64, len(packSizes))
+	for _, size := range packSizes {
+		pack := bySize[size]
+		if limits.maxPacks != math.MaxInt {
+			load[size] = 1 / float64(limits.maxPacks)
+		}
+		if opts.MaxWeight > 0 {
+			if pack.Weight == nil {
+				return nil, invalid(fmt.Sprintf("pack %s (size %d) has no weight", pack.Id, size))
+			}
+			weight[size] = *pack.Weight
+			load[size] += *pack.Weight / opts.MaxWeight
+		}
+		if opts.MaxVolume > 0 {
+			if pack.Dimensions == nil {
+				return nil, invalid(fmt.Sprintf("pack %s (size %d) has no dimensions", pack.Id, size))
+			}
+			volume[size] = packVolume(pack)
+			load[size] += volume[size] / opts.MaxVolume
+		}
+	}
+
+	// Reduce large orders with the pack that uses the least of the limits per
+	// item, so that the remainder fits the DP window. Under a pack cap the
+	// largest pack is peeled, as calculatePacksConstrained does.
+	sizes := sortedDesc(packSizes)
+	peeledSize, peeled := sizes[0], 0
+	if order > dpThreshold && limits.maxPacks == math.MaxInt {
+		for _, size := range sizes {
+			if load[size]/float64(size) < load[peeledSize]/float64(peeledSize) {
+				peeledSize = size
+			}
+		}
+	}
+	if order > dpThreshold {
+		peeled = (order - dpThreshold) / peeledSize
+	}
+	rest := order - peeled*peeledSize
+
+	// Any distribution beyond order + largest pack has a pack that can be
+	// dropped without going below the order, so larger totals never help.
+	table := newCostTable(rest+sizes[0], sizes, load)
+	totalWeight := make([]float64, len(table.cost))
+	totalVolume := make([]float64, len(table.cost))
+	for s := 1; s < len(table.cost); s++ {
+		if math.IsInf(table.cost[s], 1) {
+			continue
+		}
+		size := table.last[s]
+		totalWeight[s] = totalWeight[s-size] + weight[size]
+		totalVolume[s] = totalVolume[s-size] + volume[size]
+	}
+	fits := false
+	for s := rest; s < len(table.cost); s++ {
+		if math.IsInf(table.cost[s], 1) {
+			continue
+		}
+		w := totalWeight[s] + float64(peeled)*weight[peeledSize]
+		v := totalVolume[s] + float64(peeled)*volume[peeledSize]
+		if (opts.MaxWeight > 0 && w > opts.MaxWeight+limitTolerance) ||
+			(opts.MaxVolume > 0 && v > opts.MaxVolume+limitTolerance) {
+			continue
+		}
+		fits = true
+		packs := table.distribution(s)
+		if peeled > 0 {
+			packs[peeledSize] += peeled
+		}
+		if !limits.allow(packs) {
+			continue
+		}
+		return distributionResult(order, packs), nil
+	}
+	if fits {
+		return nil, fmt.Errorf("%w: no distribution within the shipment limits stays within the overage and pack caps", ErrPolicy)
+	}
+	return nil, ErrShipmentLimits
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// physicalPack builds a payload for a pack with a weight and a cube of side cm.
+func physicalPack(size int, weight, side float64) swagger.PackPayload {
+	return swagger.PackPayload{
+		Size:       size,
+		Weight:     &weight,
+		Dimensions: &swagger.PackDimensions{Length: side, Width: side, Height: side},
+	}
+}
+
+func TestShipmentTotals(t *testing.T) {
+	ps := NewPackService(&mockCatalog{})
+	for _, payload := range []swagger.PackPayload{physicalPack(250, 3, 20), physicalPack(500, 7, 30), physicalPack(1000, 11, 40)} {
+		_, err := ps.CreatePack(payload)
+		require.NoError(t, err)
+	}
+
+	t.Run("Totals", func(t *testing.T) {
+		result, err := ps.CalculatePacks(12001)
+		require.NoError(t, err)
+		// 12 x 1000 + 1 x 250
+		assert.Equal(t, 13, *result.ShippingUnits)
+		assert.Equal(t, 135.0, *result.TotalWeight)
+		assert.Equal(t, 12*64000.0+8000, *result.TotalVolume)
+	})
+
+	t.Run("MaxWeight", func(t *testing.T) {
+		// 500 + 250 weighs 10 kg; three 250s reach the same total at 9 kg.
+		result, err := ps.CalculatePacksWithOptions(501, CalcOptions{MaxWeight: 9})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"250": 3}, *result.PacksUsed)
+		assert.Equal(t, 9.0, *result.TotalWeight)
+	})
+
+	t.Run("MaxVolume", func(t *testing.T) {
+		// 500 + 250 takes 35000 cm³; three 250s take 24000 cm³.
+		result, err := ps.CalculatePacksWithOptions(600, CalcOptions{MaxVolume: 30000})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"250": 3}, *result.PacksUsed)
+	})
+
+	t.Run("LargeOrder", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(250000, CalcOptions{MaxWeight: 3000})
+		require.NoError(t, err)
+		assert.Equal(t, 250000, *result.TotalItemsUsed)
+		assert.LessOrEqual(t, *result.TotalWeight, 3000.0)
+	})
+
+	t.Run("WithinPolicyCaps", func(t *testing.T) {
+		// Three 250s are lightest; 500 + 250 ships the same total in two packs.
+		result, err := ps.CalculatePacksWithOptions(501, CalcOptions{MaxWeight: 11, MaxPacks: 2})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"500": 1, "250": 1}, *result.PacksUsed)
+		_, err = ps.CalculatePacksWithOptions(501, CalcOptions{MaxWeight: 11, MaxOveragePercent: utils.Ptr(10.0)})
+		assert.ErrorIs(t, err, ErrPolicy)
+	})
+
+	t.Run("Infeasible", func(t *testing.T) {
+		_, err := ps.CalculatePacksWithOptions(501, CalcOptions{MaxWeight: 5})
+		assert.ErrorIs(t, err, ErrShipmentLimits)
+	})
+
+	t.Run("MissingWeight", func(t *testing.T) {
+		pack, err := ps.CreatePack(swagger.PackPayload{Size: 100})
+		require.NoError(t, err)
+		defer ps.DeletePack(pack.Id)
+
+		result, err := ps.CalculatePacks(100)
+		require.NoError(t, err)
+		assert.Nil(t, result.TotalWeight)
+		assert.Equal(t, utils.Ptr(1), result.ShippingUnits)
+
+		_, err = ps.CalculatePacksWithOptions(100, CalcOptions{MaxWeight: 5})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// MaxShippingPacks is the largest number of packs a distribution may have to be
+// planned into parcels.
+const MaxShippingPacks = 2000
+
+// ErrNoCarrier is returned when no rate card can ship any candidate
+// distribution, typically because a pack is heavier than every parcel limit.
+var ErrNoCarrier = errors.New("no carrier rate card can ship this order")
+
+// solveShipping solves a positive order under ObjectiveShipping: it chooses the
+// distribution, rate card and split into parcels with the lowest shipping plus
+// packaging cost.
+//
+// Parcel costs depend on how packs share parcels, so the search is heuristic.
+// The candidates are the fewest-packs distribution and, for every rate card,
+// the distribution of the packs that fit its parcels that is cheapest when each
+// pack is charged its packaging cost plus its billable weight at the card's
+// full-parcel rate. Candidates stay within the overage and pack caps of opts.
+// Every candidate is then split into parcels for every card with first-fit
+// decreasing, and the cheapest plan wins.
+func (ps *PackService) solveShipping(order int, packSizes []int, bySize map[int]swagger.Pack, opts CalcOptions) (*swagger.CalcResult, error) {
+	if bySize == nil {
+		return nil, ErrNotSupported
+	}
+	if opts.Mode != ModeDefault || opts.Availability != nil {
+		return nil, invalid("the shipping objective cannot be combined with a mode or availability caps")
+	}
+	repo, err := ps.rateCardRepo()
+	if err != nil {
+		return nil, err
+	}
+	cards, err := repo.ListRateCards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rate cards: %w", err)
+	}
+	if len(cards) == 0 {
+		return nil, invalid("no carrier rate cards configured")
+	}
+	for _, size := range packSizes {
+		if pack := bySize[size]; pack.Weight == nil {
+			return nil, invalid(fmt.Sprintf("pack %s (size %d) has no weight", pack.Id, size))
+		}
+	}
+
+	sizes := sortedDesc(packSizes)
+	fewest, err := calculatePacks(order, append([]int{}, sizes...))
+	if err != nil {
+		return nil, err
+	}
+	fewestPacks, err := utils.ParseMapKeys(*fewest.PacksUsed)
+	if err != nil {
+		return nil, err
+	}
+	limits := policyLimits(order, opts)
+	if !limits.allow(fewestPacks) && opts.MaxPacks > 0 {
+		// The fewest packs that cover the order with the least overage may
+		// still be too many; a larger total may need fewer.
+		capped, err := calculatePacksConstrained(order, sizes, CalcOptions{MaxPacks: opts.MaxPacks})
+		if err != nil {
+			return nil, err
+		}
+		if fewestPacks, err = utils.ParseMapKeys(*capped.PacksUsed); err != nil {
+			return nil, err
+		}
+	}
+	candidates := []map[int]int{fewestPacks}
+	for _, card := range cards {
+		if fitting := parcelSizes(card, sizes, bySize); len(fitting) > 0 {
+			if packs, ok := cheapestPacks(order, fitting, estimatedPackCost(card, fitting, bySize), limits); ok {
+				candidates = append(candidates, packs)
+			}
+		}
+	}
+
+	var best map[int]int
+	var bestPlan *swagger.ShippingPlan
+	tooMany, allowed := false, false
+	for _, packs := range candidates {
+		if !limits.allow(packs) {
+			continue
+		}
+		allowed = true
+		if countPacks(packs) > MaxShippingPacks {
+			tooMany = true
+			continue
+		}
+		for _, card := range cards {
+			plan := planParcels(packs, bySize, card)
+			if plan != nil && (bestPlan == nil || plan.TotalCost < bestPlan.TotalCost) {
+				best, bestPlan = packs, plan
+			}
+		}
+	}
+	if bestPlan == nil {
+		if !allowed {
+			return nil, fmt.Errorf("%w: no distribution stays within the overage and pack caps", ErrPolicy)
+		}
+		if tooMany {
+			return nil, invalid(fmt.Sprintf("the shipping objective supports at most %d packs per order", MaxShippingPacks))
+		}
+		return nil, ErrNoCarrier
+	}
+	result := distributionResult(order, best)
+	result.Shipping = bestPlan
+	return result, nil
+}
+
+// billableWeight returns the larger of the actual and the dimensional weight.
+func billableWeight(weight, volume float64, card swagger.RateCard) float64 {
+	return math.Max(weight, volume/card.DimDivisor)
+}
+
+// parcelSizes returns the pack sizes, in the order of packSizes, that fit into
+// a parcel of card on their own.
+func parcelSizes(card swagger.RateCard, packSizes []int, bySize map[int]swagger.Pack) []int {
+	limit := card.Brackets[len(card.Brackets)-1].MaxWeight
+	var fitting []int
+	for _, size := range packSizes {
+		pack := bySize[size]
+		if billableWeight(*pack.Weight, packVolume(pack), card) <= limit+limitTolerance {
+			fitting = append(fitting, size)
+		}
+	}
+	return fitting
+}
+
+// estimatedPackCost charges every pack its packaging cost plus its billable
+// weight at the price per kilogram of a full parcel in the heaviest bracket.
+func estimatedPackCost(card swagger.RateCard, packSizes []int, bySize map[int]swagger.Pack) map[int]float64 {
+	top := card.Brackets[len(card.Brackets)-1]
+	perKg := (top.Price + card.ParcelFee) / top.MaxWeight
+	unit := make(map[int]float64, len(packSizes))
+	for _, size := range packSizes {
+		pack := bySize[size]
+		unit[size] = billableWeight(*pack.Weight, packVolume(pack), card) * perKg
+		if pack.Cost != nil {
+			unit[size] += *pack.Cost
+		}
+	}
+	return unit
+}
+
+// parcelLoad is a parcel being filled by planParcels.
+type parcelLoad struct {
+	packs  map[int]int
+	weight float64
+	volume float64
+}
+
+// planParcels splits packs into parcels of card with first-fit decreasing by
+// billable weight and prices them. It returns nil if a pack exceeds the
+// heaviest bracket of the card on its own.
+func planParcels(packs map[int]int, bySize map[int]swagger.Pack, card swagger.RateCard) *swagger.ShippingPlan {
+	limit := card.Brackets[len(card.Brackets)-1].MaxWeight
+	sizes := make([]int, 0, len(packs))
+	for size := range packs {
+		sizes = append(sizes, size)
+	}
+	billable := func(size int) float64 {
+		pack := bySize[size]
+		return billableWeight(*pack.Weight, packVolume(pack), card)
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if bi, bj := billable(sizes[i]), billable(sizes[j]); bi != bj {
+			return bi > bj
+		}
+		return sizes[i] > sizes[j]
+	})
+
+	var parcels []*parcelLoad
+	plan := &swagger.ShippingPlan{
+		RateCardId: card.Id,
+		Carrier:    card.Carrier,
+		Service:    card.Service,
+		Currency:   card.Currency,
+		Parcels:    []swagger.Parcel{},
+	}
+	for _, size := range sizes {
+		pack := bySize[size]
+		weight, volume := *pack.Weight, packVolume(pack)
+		if billableWeight(weight, volume, card) > limit+limitTolerance {
+			return nil
+		}
+		if pack.Cost != nil {
+			plan.PackagingCost += *pack.Cost * float64(packs[size])
+		}
+		for n := 0; n < packs[size]; n++ {
+			var target *parcelLoad
+			for _, parcel := range parcels {
+				if billableWeight(parcel.weight+weight, parcel.volume+volume, card) <= limit+limitTolerance {
+					target = parcel
+					break
+				}
+			}
+			if target == nil {
+				target = &parcelLoad{packs: map[int]int{}}
+				parcels = append(parcels, target)
+			}
+			target.packs[size]++
+			target.weight += weight
+			target.volume += volume
+		}
+	}
+
+	for _, parcel := range parcels {
+		chargeable := billableWeight(parcel.weight, parcel.volume, card)
+		cost := card.ParcelFee + bracketPrice(card, chargeable)
+		plan.Parcels = append(plan.Parcels, swagger.Parcel{
+			PacksUsed:         utils.ConvertMapKeys(parcel.packs),
+			Weight:            math.Round(parcel.weight*1000) / 1000,
+			DimensionalWeight: math.Round(parcel.volume/card.DimDivisor*1000) / 1000,
+			BillableWeight:    math.Round(chargeable*1000) / 1000,
+			Cost:              roundCents(cost),
+		})
+		plan.ShippingCost += cost
+	}
+	plan.ShippingCost = roundCents(plan.ShippingCost)
+	plan.PackagingCost = roundCents(plan.PackagingCost)
+	plan.TotalCost = roundCents(plan.ShippingCost + plan.PackagingCost)
+	return plan
+}
+
+// bracketPrice returns the price of the first bracket covering weight. The
+// brackets of a stored card are sorted by weight.
+func bracketPrice(card swagger.RateCard, weight float64) float64 {
+	for _, bracket := range card.Brackets {
+		if weight <= bracket.MaxWeight+limitTolerance {
+			return bracket.Price
+		}
+	}
+	return card.Brackets[len(card.Brackets)-1].Price
+}
+
+// countPacks returns the number of packs in a distribution.
+func countPacks(packs map[int]int) int {
+	n := 0
+	for _, count := range packs {
+		n += count
+	}
+	return n
+}
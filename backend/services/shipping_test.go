@@ -0,0 +1,145 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// mockShippingCatalog is a mockCatalog that also stores rate cards.
+type mockShippingCatalog struct {
+	mockCatalog
+	cards mockRecords[swagger.RateCard]
+}
+
+func (m *mockShippingCatalog) ListRateCards() ([]swagger.RateCard, error) {
+	return m.cards.list()
+}
+
+func (m *mockShippingCatalog) GetRateCard(id string) (*swagger.RateCard, error) {
+	return m.cards.get(id)
+}
+
+func (m *mockShippingCatalog) CreateRateCard(card *swagger.RateCard) error {
+	return m.cards.create(card, &card.Id)
+}
+
+func (m *mockShippingCatalog) UpdateRateCard(card *swagger.RateCard) error {
+	return m.cards.update(card.Id, card)
+}
+
+func (m *mockShippingCatalog) DeleteRateCard(id string) error {
+	return m.cards.delete(id)
+}
+
+func TestSolveShipping(t *testing.T) {
+	repo := &mockShippingCatalog{}
+	ps := NewPackService(repo)
+	small := physicalPack(250, 3, 20)
+	small.Cost = utils.Ptr(0.5)
+	large := physicalPack(500, 5, 25)
+	large.Cost = utils.Ptr(0.8)
+	for _, payload := range []swagger.PackPayload{small, large} {
+		_, err := ps.CreatePack(payload)
+		require.NoError(t, err)
+	}
+
+	t.Run("NoRateCards", func(t *testing.T) {
+		_, err := ps.CalculatePacksWithOptions(501, CalcOptions{Objective: ObjectiveShipping})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	_, err := ps.CreateRateCard(swagger.RateCardPayload{
+		Carrier:    "Parcelly",
+		Currency:   "EUR",
+		DimDivisor: 5000,
+		ParcelFee:  utils.Ptr(1.0),
+		Brackets: []swagger.WeightBracket{
+			{MaxWeight: 10, Price: 6},
+			{MaxWeight: 5, Price: 4},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("CheapestPlan", func(t *testing.T) {
+		// 500 + 250 (8 kg) ships in one 10 kg parcel: 7.00 + 1.30 packaging.
+		result, err := ps.CalculatePacksWithOptions(501, CalcOptions{Objective: ObjectiveShipping})
+		require.NoError(t, err)
+		require.NotNil(t, result.Shipping)
+		plan := result.Shipping
+		assert.Equal(t, "Parcelly", plan.Carrier)
+		require.Len(t, plan.Parcels, 1)
+		assert.Equal(t, 8.0, plan.Parcels[0].BillableWeight)
+		assert.Equal(t, 7.0, plan.ShippingCost)
+		assert.Equal(t, 1.3, plan.PackagingCost)
+		assert.Equal(t, 8.3, plan.TotalCost)
+	})
+
+	t.Run("SplitsIntoParcels", func(t *testing.T) {
+		result, err := ps.CalculatePacksWithOptions(2000, CalcOptions{Objective: ObjectiveShipping})
+		require.NoError(t, err)
+		// Four 500 packs weigh 20 kg: two parcels of 10 kg.
+		assert.Len(t, result.Shipping.Parcels, 2)
+		assert.Equal(t, 14.0, result.Shipping.ShippingCost)
+	})
+
+	t.Run("WithinPolicyCaps", func(t *testing.T) {
+		repo := &mockShippingCatalog{}
+		ps := NewPackService(repo)
+		for _, payload := range []swagger.PackPayload{physicalPack(250, 1, 10), physicalPack(500, 9, 10)} {
+			_, err := ps.CreatePack(payload)
+			require.NoError(t, err)
+		}
+		_, err := ps.CreateRateCard(swagger.RateCardPayload{
+			Carrier: "Parcelly", Currency: "EUR", DimDivisor: 5000, ParcelFee: utils.Ptr(1.0),
+			Brackets: []swagger.WeightBracket{{MaxWeight: 10, Price: 6}},
+		})
+		require.NoError(t, err)
+
+		// Four 250s share one parcel; two 500s need two.
+		result, err := ps.CalculatePacksWithOptions(1000, CalcOptions{Objective: ObjectiveShipping})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"250": 4}, *result.PacksUsed)
+		result, err = ps.CalculatePacksWithOptions(1000, CalcOptions{Objective: ObjectiveShipping, MaxPacks: 2})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"500": 2}, *result.PacksUsed)
+		assert.Equal(t, 14.0, result.Shipping.TotalCost)
+
+		_, err = ps.CalculatePacksWithOptions(1001, CalcOptions{Objective: ObjectiveShipping, MaxPacks: 2})
+		assert.ErrorIs(t, err, ErrPolicy)
+	})
+
+	t.Run("TooHeavy", func(t *testing.T) {
+		heavy := physicalPack(5000, 40, 50)
+		pack, err := ps.CreatePack(heavy)
+		require.NoError(t, err)
+		defer ps.DeletePack(pack.Id)
+		_, err = ps.CalculatePacksWithOptions(5000, CalcOptions{Objective: ObjectiveShipping})
+		assert.NoError(t, err, "the heavy pack is avoided")
+	})
+
+	t.Run("RateCardValidation", func(t *testing.T) {
+		_, err := ps.CreateRateCard(swagger.RateCardPayload{Carrier: "X", Currency: "EUR", DimDivisor: 5000})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		_, err = ps.UpdateRateCard("99", swagger.RateCardPayload{})
+		assert.ErrorIs(t, err, ErrRateCardNotFound)
+	})
+}
+
+func TestPlanParcels_DimensionalWeight(t *testing.T) {
+	bulky := swagger.Pack{Id: "1", Size: 100, Weight: utils.Ptr(1.0),
+		Dimensions: &swagger.PackDimensions{Length: 50, Width: 40, Height: 30}}
+	card := swagger.RateCard{Id: "1", DimDivisor: 5000, Brackets: []swagger.WeightBracket{{MaxWeight: 30, Price: 10}}}
+
+	plan := planParcels(map[int]int{100: 3}, map[int]swagger.Pack{100: bulky}, card)
+	require.NotNil(t, plan)
+	// Each pack has a dimensional weight of 12 kg, so only two fit a 30 kg parcel.
+	require.Len(t, plan.Parcels, 2)
+	assert.Equal(t, 24.0, plan.Parcels[0].BillableWeight)
+	assert.Equal(t, 2.0, plan.Parcels[0].Weight)
+}
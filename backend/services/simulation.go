@@ -0,0 +1,247 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+const (
+	// MaxSimulationOrders caps the size of a simulated order sample.
+	MaxSimulationOrders = 100000
+	// defaultWorstOrders is how many orders are reported when none is requested.
+	defaultWorstOrders = 10
+)
+
+// Simulate replays an order sample against the configured pack sizes and the
+// candidate sizes in req, and reports aggregate deltas between the two. The
+// sample comes from the stored orders, from csvOrders, or from a synthetic
+// distribution, as selected by req.Source. Orders are solved exactly as
+// CalculatePacks would solve them.
+func (ps *PackService) Simulate(req swagger.SimulationRequest, csvOrders []int) (*swagger.SimulationResult, error) {
+	if len(req.PackSizes) == 0 {
+		return nil, invalid("packSizes cannot be empty")
+	}
+	if err := checkProposedSizes(req.PackSizes); err != nil {
+		return nil, err
+	}
+	orders, err := ps.simulationOrders(req, csvOrders)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, invalid("the order sample is empty")
+	}
+	if len(orders) > MaxSimulationOrders {
+		return nil, invalid(fmt.Sprintf("the order sample exceeds %d orders", MaxSimulationOrders))
+	}
+
+	current, _, err := ps.loadPackSet(time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pack sizes: %w", err)
+	}
+	if len(current) == 0 {
+		return nil, fmt.Errorf("no pack sizes configured")
+	}
+
+	before := replayOrders(current, orders)
+	after := replayOrders(req.PackSizes, orders)
+
+	worst := defaultWorstOrders
+	if req.WorstOrders != nil {
+		worst = max(*req.WorstOrders, 0)
+	}
+	comparisons := make([]swagger.SimulationOrder, 0, len(orders))
+	for i, q := range orders {
+		if before.failed[i] || after.failed[i] {
+			continue
+		}
+		comparisons = append(comparisons, swagger.SimulationOrder{
+			ItemsOrdered:     q,
+			CurrentOverage:   before.overage[i],
+			CandidateOverage: after.overage[i],
+			OverageDelta:     after.overage[i] - before.overage[i],
+			CurrentPacks:     before.packs[i],
+			CandidatePacks:   after.packs[i],
+		})
+	}
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		return comparisons[i].OverageDelta > comparisons[j].OverageDelta
+	})
+
+	currentSummary := before.summary(current)
+	candidateSummary := after.summary(req.PackSizes)
+	return &swagger.SimulationResult{
+		Orders:    len(orders),
+		Current:   currentSummary,
+		Candidate: candidateSummary,
+		Delta: swagger.SimulationDelta{
+			TotalOverage:   candidateSummary.TotalOverage - currentSummary.TotalOverage,
+			TotalPacks:     candidateSummary.TotalPacks - currentSummary.TotalPacks,
+			AverageOverage: candidateSummary.AverageOverage - currentSummary.AverageOverage,
+			AveragePacks:   candidateSummary.AveragePacks - currentSummary.AveragePacks,
+		},
+		WorstOrders: comparisons[:min(worst, len(comparisons))],
+	}, nil
+}
+
+// simulationOrders assembles the order sample selected by req.Source.
+func (ps *PackService) simulationOrders(req swagger.SimulationRequest, csvOrders []int) ([]int, error) {
+	switch req.Source {
+	case swagger.SimulationRequestSourceCsv:
+		return csvOrders, nil
+	case swagger.SimulationRequestSourceStored:
+		stored, err := ps.ListOrders()
+		if err != nil {
+			return nil, err
+		}
+		orders := make([]int, len(stored))
+		for i, order := range stored {
+			orders[i] = order.ItemsOrdered
+		}
+		return orders, nil
+	case swagger.SimulationRequestSourceSynthetic:
+		if req.Synthetic == nil {
+			return nil, invalid("synthetic source requires a synthetic distribution")
+		}
+		return syntheticOrders(*req.Synthetic)
+	}
+	return nil, invalid(fmt.Sprintf("unknown source %q", req.Source))
+}
+
+// syntheticOrders draws order quantities from a uniform or normal distribution.
+// Normal draws are rounded and clipped to [min, max]; min defaults to 1 and max
+// to MaxOrder, which also caps it.
+func syntheticOrders(spec swagger.SyntheticOrders) ([]int, error) {
+	if spec.Count < 1 || spec.Count > MaxSimulationOrders {
+		return nil, invalid(fmt.Sprintf("synthetic count must be between 1 and %d", MaxSimulationOrders))
+	}
+	lo, hi := 1, MaxOrder
+	if spec.Min != nil {
+		lo = *spec.Min
+	}
+	if spec.Max != nil {
+		hi = *spec.Max
+	}
+	if lo < 0 || hi < lo || hi > MaxOrder {
+		return nil, invalid(fmt.Sprintf("synthetic bounds must satisfy 0 <= min <= max <= %d", MaxOrder))
+	}
+	var seed int64 = 1
+	if spec.Seed != nil {
+		seed = *spec.Seed
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	orders := make([]int, spec.Count)
+	switch spec.Distribution {
+	case swagger.Uniform:
+		if spec.Max == nil {
+			return nil, invalid("uniform distribution requires max")
+		}
+		for i := range orders {
+			orders[i] = lo + rng.Intn(hi-lo+1)
+		}
+	case swagger.Normal:
+		if spec.Mean == nil || spec.Stddev == nil || *spec.Stddev < 0 {
+			return nil, invalid("normal distribution requires mean and a non-negative stddev")
+		}
+		for i := range orders {
+			// Clip before converting: a draw beyond the int range has no
+			// defined conversion.
+			switch v := math.Round(rng.NormFloat64()*(*spec.Stddev) + *spec.Mean); {
+			case v <= float64(lo):
+				orders[i] = lo
+			case v >= float64(hi):
+				orders[i] = hi
+			default:
+				orders[i] = int(v)
+			}
+		}
+	default:
+		return nil, invalid(fmt.Sprintf("unknown distribution %q", spec.Distribution))
+	}
+	return orders, nil
+}
+
+// replay holds the per-order outcome of solving a sample with one pack set.
+// The overage and packs of failed orders are zero.
+type replay struct {
+	overage []int
+	packs   []int
+	failed  []bool
+	usage   map[int]int
+}
+
+// replayOrders solves every order with packSizes. Quantities in the DP window
+// share one rangeSolver pass, which gives the same answers as CalculatePacks;
+// larger ones go through calculatePacks itself, and orders above MaxOrder fail
+// as they would in CalculatePacks.
+func replayOrders(packSizes []int, orders []int) *replay {
+	sizes := sortedDesc(packSizes)
+	largestInWindow := 0
+	for _, q := range orders {
+		if q <= MaxRangeQuantity {
+			largestInWindow = max(largestInWindow, q)
+		}
+	}
+	solver := newRangeSolver(largestInWindow, sizes)
+
+	r := &replay{
+		overage: make([]int, len(orders)),
+		packs:   make([]int, len(orders)),
+		failed:  make([]bool, len(orders)),
+		usage:   map[int]int{},
+	}
+	for i, q := range orders {
+		var total int
+		var packs map[int]int
+		if q <= MaxRangeQuantity {
+			total, packs = solver.solve(q)
+		} else {
+			if q > MaxOrder {
+				r.failed[i] = true
+				continue
+			}
+			result, err := calculatePacks(q, append([]int{}, sizes...))
+			if err != nil {
+				r.failed[i] = true
+				continue
+			}
+			total = *result.TotalItemsUsed
+			packs, _ = utils.ParseMapKeys(*result.PacksUsed)
+		}
+		r.overage[i] = total - q
+		for size, count := range packs {
+			r.packs[i] += count
+			r.usage[size] += count
+		}
+	}
+	return r
+}
+
+// summary aggregates the replay into a swagger.SimulationSummary. Failed orders
+// are counted separately and left out of the totals and averages.
+func (r *replay) summary(packSizes []int) swagger.SimulationSummary {
+	summary := swagger.SimulationSummary{
+		PackSizes:    sortedAsc(packSizes),
+		PerSizeUsage: utils.ConvertMapKeys(r.usage),
+	}
+	for i := range r.overage {
+		if r.failed[i] {
+			summary.Failed++
+			continue
+		}
+		summary.TotalOverage += r.overage[i]
+		summary.TotalPacks += r.packs[i]
+	}
+	if packed := len(r.overage) - summary.Failed; packed > 0 {
+		summary.AverageOverage = float64(summary.TotalOverage) / float64(packed)
+		summary.AveragePacks = float64(summary.TotalPacks) / float64(packed)
+	}
+	return summary
+}
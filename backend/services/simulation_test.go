@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+func TestSimulate(t *testing.T) {
+	ps := NewPackService(&mockPackRepo{sizes: []int{250, 500, 1000}})
+
+	t.Run("CSVSample", func(t *testing.T) {
+		req := swagger.SimulationRequest{PackSizes: []int{300, 600}, Source: swagger.SimulationRequestSourceCsv}
+		res, err := ps.Simulate(req, []int{300, 600, 501, 250000})
+		require.NoError(t, err)
+		assert.Equal(t, 4, res.Orders)
+		// Current: 200 + 150 + 249 + 0 overage; candidate: 0 + 0 + 99 + 200.
+		assert.Equal(t, 599, res.Current.TotalOverage)
+		assert.Equal(t, 299, res.Candidate.TotalOverage)
+		assert.Equal(t, -300, res.Delta.TotalOverage)
+		assert.Equal(t, 250000, res.WorstOrders[0].ItemsOrdered)
+		assert.Equal(t, 200, res.WorstOrders[0].OverageDelta)
+		assert.Equal(t, 1, res.Candidate.PerSizeUsage["300"])
+	})
+
+	t.Run("SyntheticSampleIsDeterministic", func(t *testing.T) {
+		req := swagger.SimulationRequest{
+			PackSizes:   []int{250, 750},
+			Source:      swagger.SimulationRequestSourceSynthetic,
+			Synthetic:   &swagger.SyntheticOrders{Distribution: swagger.Normal, Count: 200, Mean: utils.Ptr(2000.0), Stddev: utils.Ptr(500.0)},
+			WorstOrders: utils.Ptr(3),
+		}
+		first, err := ps.Simulate(req, nil)
+		require.NoError(t, err)
+		second, err := ps.Simulate(req, nil)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.Len(t, first.WorstOrders, 3)
+	})
+
+	t.Run("FullRangeUniformSample", func(t *testing.T) {
+		orders, err := syntheticOrders(swagger.SyntheticOrders{
+			Distribution: swagger.Uniform, Count: 100, Min: utils.Ptr(0), Max: utils.Ptr(MaxOrder),
+		})
+		require.NoError(t, err)
+		for _, q := range orders {
+			assert.GreaterOrEqual(t, q, 0)
+			assert.LessOrEqual(t, q, MaxOrder)
+		}
+		_, err = syntheticOrders(swagger.SyntheticOrders{
+			Distribution: swagger.Uniform, Count: 100, Min: utils.Ptr(0), Max: utils.Ptr(math.MaxInt),
+		})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("NormalSampleBeyondMaxOrder", func(t *testing.T) {
+		orders, err := syntheticOrders(swagger.SyntheticOrders{
+			Distribution: swagger.Normal, Count: 10, Mean: utils.Ptr(1e19), Stddev: utils.Ptr(0.0),
+		})
+		require.NoError(t, err)
+		for _, q := range orders {
+			assert.Equal(t, MaxOrder, q)
+		}
+	})
+
+	t.Run("FailedOrdersAreReportedSeparately", func(t *testing.T) {
+		req := swagger.SimulationRequest{PackSizes: []int{300, 600}, Source: swagger.SimulationRequestSourceCsv}
+		res, err := ps.Simulate(req, []int{300, 600, MaxOrder + 1})
+		require.NoError(t, err)
+		assert.Equal(t, 3, res.Orders)
+		assert.Equal(t, 1, res.Current.Failed)
+		assert.Equal(t, 1, res.Candidate.Failed)
+		// Current: 200 + 150 overage over the two packed orders.
+		assert.Equal(t, 350, res.Current.TotalOverage)
+		assert.Equal(t, 175.0, res.Current.AverageOverage)
+		assert.Equal(t, 0.0, res.Candidate.AverageOverage)
+		assert.Len(t, res.WorstOrders, 2)
+	})
+
+	t.Run("StoredSampleNeedsOrderStorage", func(t *testing.T) {
+		req := swagger.SimulationRequest{PackSizes: []int{250}, Source: swagger.SimulationRequestSourceStored}
+		_, err := ps.Simulate(req, nil)
+		assert.ErrorIs(t, err, ErrNotSupported)
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		var validationErr *ValidationError
+		_, err := ps.Simulate(swagger.SimulationRequest{PackSizes: []int{0}, Source: swagger.SimulationRequestSourceCsv}, []int{1})
+		assert.True(t, errors.As(err, &validationErr))
+		_, err = ps.Simulate(swagger.SimulationRequest{PackSizes: []int{250}, Source: swagger.SimulationRequestSourceSynthetic}, nil)
+		assert.True(t, errors.As(err, &validationErr))
+		_, err = ps.Simulate(swagger.SimulationRequest{PackSizes: []int{MaxRangeQuantity + 1}, Source: swagger.SimulationRequestSourceCsv}, []int{1})
+		assert.True(t, errors.As(err, &validationErr))
+	})
+}
@@ -0,0 +1,388 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// MaxKitSearch is the largest number of kit combinations CalculateSkus tries
+// before it settles for the best one found so far.
+const MaxKitSearch = 1000000
+
+// maxKitPeriod caps the residue period over which kit counts are tried.
+const maxKitPeriod = 1000
+
+// maxPackMemo is the largest number of uncovered item counts whose packs are
+// memoised per SKU.
+const maxPackMemo = 10000
+
+// ErrUncoverable is returned when the kits and packs of a multi-SKU order
+// cannot cover every SKU.
+var ErrUncoverable = errors.New("the order cannot be covered by the kits and packs of its SKUs")
+
+// CalculateSkus distributes a multi-SKU order over the stored kits and the
+// single-SKU packs of every SKU, minimising the overage summed over all SKUs
+// and then the number of kits and packs to ship.
+//
+// This extends the one-dimensional problem calculatePacks solves to one
+// dimension per SKU. The search starts from the distribution without kits,
+// then enumerates kit counts depth-first, largest kits first and smallest
+// counts first, and packs the items kits leave uncovered per SKU with
+// calculatePacks. Only the counts countRanges selects from the residues of
+// the pack sizes are tried, so large orders do not try every count. Since
+// more kits never ship fewer items, a branch is cut as soon as the overage
+// of its kits alone exceeds the best distribution found. Single-SKU packs of
+// a SKU are the active catalogue packs with that SKU, or the packs without a
+// SKU when it has none.
+func (ps *PackService) CalculateSkus(order swagger.SkuOrder) (*swagger.SkuResult, error) {
+	if len(order.Items) == 0 {
+		return nil, invalid("at least one SKU must be ordered")
+	}
+	for sku, items := range order.Items {
+		if sku == "" || items < 0 || items > MaxOrder {
+			return nil, invalid(fmt.Sprintf("items must be between 0 and %d for every non-empty SKU", MaxOrder))
+		}
+	}
+	if err := ps.checkQuantities(order.Items, func(sku string) string { return "items." + sku }); err != nil {
+		return nil, err
+	}
+	var kits []swagger.Kit
+	if repo, err := ps.kitRepo(); err == nil {
+		if kits, err = repo.ListKits(); err != nil {
+			return nil, fmt.Errorf("failed to list kits: %w", err)
+		}
+	}
+	skus := skusOf(order.Items, kits)
+	packSizes, err := ps.skuPackSizes(skus)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSkuSearch(order.Items, skus, kits, packSizes)
+	// Kits are only worth shipping when they beat the packs alone.
+	s.evaluate()
+	s.search(0)
+	if s.best == nil {
+		return nil, ErrUncoverable
+	}
+	return s.result(), nil
+}
+
+// skusOf returns, sorted, the SKUs that are ordered or held by a kit.
+func skusOf(items map[string]int, kits []swagger.Kit) []string {
+	seen := map[string]bool{}
+	for sku := range items {
+		seen[sku] = true
+	}
+	for _, kit := range kits {
+		for sku := range kit.Contents {
+			seen[sku] = true
+		}
+	}
+	skus := make([]string, 0, len(seen))
+	for sku := range seen {
+		skus = append(skus, sku)
+	}
+	sort.Strings(skus)
+	return skus
+}
+
+// skuPackSizes returns the single-SKU pack sizes of every SKU, sorted in
+// descending order. Without a catalogue every SKU uses the configured sizes.
+func (ps *PackService) skuPackSizes(skus []string) (map[string][]int, error) {
+	bySku := map[string][]int{}
+	catalog, err := ps.catalog()
+	if err != nil {
+		sizes, err := ps.repo.GetPackSizes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pack sizes: %w", err)
+		}
+		bySku[""] = sizes
+	} else {
+		packs, err := catalog.ListPacks()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list packs: %w", err)
+		}
+		now := time.Now()
+		for _, pack := range packs {
+			if !pack.InEffect(now) {
+				continue
+			}
+			sku := ""
+			if pack.Sku != nil {
+				sku = *pack.Sku
+			}
+			bySku[sku] = append(bySku[sku], pack.Size)
+		}
+	}
+	sizes := make(map[string][]int, len(skus))
+	for _, sku := range skus {
+		own, ok := bySku[sku]
+		if !ok {
+			own = bySku[""]
+		}
+		sizes[sku] = sortedDesc(own)
+	}
+	return sizes, nil
+}
+
+// skuSearch holds the state of the depth-first search over kit counts.
+type skuSearch struct {
+	items     map[string]int
+	skus      []string
+	kits      []swagger.Kit
+	packSizes map[string][]int
+	// maxCounts bounds every kit count: with that many kits, every SKU of the
+	// kit is covered and more kits only add overage.
+	maxCounts []int
+
+	counts  []int
+	covered map[string]int
+	nodes   int
+	// packs memoises the single-SKU packs for the items kits leave uncovered,
+	// keyed by SKU and then by the number of uncovered items, up to
+	// maxPackMemo entries per SKU.
+	packs map[string]map[int]map[int]int
+
+	best                   []int
+	bestOverage, bestUnits int
+}
+
+// newSkuSearch prepares the search with the kits sorted by size, largest first.
+func newSkuSearch(items map[string]int, skus []string, kits []swagger.Kit, packSizes map[string][]int) *skuSearch {
+	kits = append([]swagger.Kit{}, kits...)
+	sort.SliceStable(kits, func(i, j int) bool { return kitSize(kits[i]) > kitSize(kits[j]) })
+	s := &skuSearch{
+		items:     items,
+		skus:      skus,
+		kits:      kits,
+		packSizes: packSizes,
+		maxCounts: make([]int, len(kits)),
+		counts:    make([]int, len(kits)),
+		covered:   map[string]int{},
+		packs:     map[string]map[int]map[int]int{},
+	}
+	for i, kit := range kits {
+		for sku, count := range kit.Contents {
+			s.maxCounts[i] = max(s.maxCounts[i], (items[sku]+count-1)/count)
+		}
+	}
+	return s
+}
+
+// search tries the candidate counts of kit i and the kits after it, smallest
+// first, until the kits alone ship more overage than the best distribution.
+// Every count tried counts against MaxKitSearch.
+func (s *skuSearch) search(i int) {
+	if i == len(s.kits) {
+		s.evaluate()
+		return
+	}
+	defer func() { s.counts[i] = 0 }()
+	kit := s.kits[i]
+	for _, counts := range s.countRanges(i) {
+		for count := counts[0]; count <= counts[1]; count++ {
+			if s.nodes >= MaxKitSearch {
+				return
+			}
+			s.nodes++
+			s.add(kit, count)
+			cut := s.best != nil && s.kitOverage() > s.bestOverage
+			if !cut {
+				s.counts[i] = count
+				s.search(i + 1)
+			}
+			s.add(kit, -count)
+			if cut {
+				return
+			}
+		}
+	}
+}
+
+// countRanges returns the counts of kit i worth trying as disjoint inclusive
+// ranges, smallest first.
+//
+// A period of kits covers a whole number of the largest packs of every SKU in
+// the kit. Counts a period apart therefore leave the same overage while every
+// SKU keeps enough items uncovered for its packs to fill any multiple of their
+// greatest common divisor, and more kits only add overage once a SKU is fully
+// covered. Between those marks the best count of each residue is at one end,
+// so only the first and last period are tried; around the marks, where packs
+// fill irregularly, every count is.
+func (s *skuSearch) countRanges(i int) [][2]int {
+	kit := s.kits[i]
+	maxCount := s.maxCounts[i]
+	period := 1
+	var marks [][2]int
+	for sku, items := range kit.Contents {
+		rest := max(s.items[sku]-s.covered[sku], 0)
+		covered := (rest + items - 1) / items
+		regular := covered
+		if sizes := s.packSizes[sku]; len(sizes) > 0 {
+			largest := sizes[0]
+			if step := largest / gcd(largest, items); step >= maxKitPeriod {
+				period = maxKitPeriod
+			} else {
+				period = min(period/gcd(period, step)*step, maxKitPeriod)
+			}
+			regular = max(rest-fillThreshold(sizes, rest), 0) / items
+		}
+		marks = append(marks, [2]int{regular, covered})
+	}
+	ranges := [][2]int{{0, period - 1}, {maxCount - period + 1, maxCount}}
+	for _, mark := range marks {
+		ranges = append(ranges, [2]int{mark[0] - period, mark[1] + period})
+	}
+	for j := range ranges {
+		ranges[j] = [2]int{max(ranges[j][0], 0), min(ranges[j][1], maxCount)}
+	}
+	sort.Slice(ranges, func(a, b int) bool { return ranges[a][0] < ranges[b][0] })
+	merged := [][2]int{}
+	for _, r := range ranges {
+		if r[0] > r[1] {
+			continue
+		}
+		if last := len(merged) - 1; last >= 0 && r[0] <= merged[last][1]+1 {
+			merged[last][1] = max(merged[last][1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// fillThreshold returns a number of items from which packSizes, sorted in
+// descending order, fill every multiple of their greatest common divisor,
+// capped at rest.
+func fillThreshold(packSizes []int, rest int) int {
+	divisor := 0
+	for _, size := range packSizes {
+		divisor = gcd(divisor, size)
+	}
+	largest, smallest := packSizes[0], packSizes[len(packSizes)-1]
+	if largest/divisor > rest/smallest {
+		return rest
+	}
+	return largest / divisor * smallest
+}
+
+// add adds count kits to the covered items.
+func (s *skuSearch) add(kit swagger.Kit, count int) {
+	for sku, items := range kit.Contents {
+		s.covered[sku] += count * items
+	}
+}
+
+// kitOverage returns the items the kits of the current branch ship beyond
+// the order.
+func (s *skuSearch) kitOverage() int {
+	overage := 0
+	for sku, covered := range s.covered {
+		overage += max(covered-s.items[sku], 0)
+	}
+	return overage
+}
+
+// evaluate packs what the kit counts of the current branch leave uncovered
+// and keeps the distribution if it beats the best one.
+func (s *skuSearch) evaluate() {
+	overage, units := 0, 0
+	for _, count := range s.counts {
+		units += count
+	}
+	for _, sku := range s.skus {
+		rest := s.items[sku] - s.covered[sku]
+		if rest <= 0 {
+			overage -= rest
+			continue
+		}
+		packs, ok := s.packFor(sku, rest)
+		if !ok {
+			return
+		}
+		for size, count := range packs {
+			overage += size * count
+			units += count
+		}
+		overage -= rest
+	}
+	if s.best == nil || overage < s.bestOverage || (overage == s.bestOverage && units < s.bestUnits) {
+		s.best = append([]int{}, s.counts...)
+		s.bestOverage, s.bestUnits = overage, units
+	}
+}
+
+// packFor returns the single-SKU packs for rest items of sku, or ok false
+// when the SKU has no packs.
+func (s *skuSearch) packFor(sku string, rest int) (packs map[int]int, ok bool) {
+	if packs, ok := s.packs[sku][rest]; ok {
+		return packs, packs != nil
+	}
+	if s.packs[sku] == nil {
+		s.packs[sku] = map[int]map[int]int{}
+	}
+	if len(s.packSizes[sku]) > 0 {
+		if result, err := calculatePacks(rest, append([]int{}, s.packSizes[sku]...)); err == nil {
+			packs, _ = utils.ParseMapKeys(*result.PacksUsed)
+		}
+	}
+	if len(s.packs[sku]) < maxPackMemo {
+		s.packs[sku][rest] = packs
+	}
+	return packs, packs != nil
+}
+
+// result builds the response for the best kit counts.
+func (s *skuSearch) result() *swagger.SkuResult {
+	res := &swagger.SkuResult{
+		KitsUsed:      map[string]int{},
+		Lines:         make([]swagger.SkuLine, 0, len(s.skus)),
+		Overage:       s.bestOverage,
+		ShippingUnits: s.bestUnits,
+	}
+	fromKits := map[string]int{}
+	for i, count := range s.best {
+		if count == 0 {
+			continue
+		}
+		res.KitsUsed[s.kits[i].Id] = count
+		for sku, items := range s.kits[i].Contents {
+			fromKits[sku] += count * items
+		}
+	}
+	for _, sku := range s.skus {
+		line := swagger.SkuLine{
+			Sku:            sku,
+			ItemsOrdered:   s.items[sku],
+			ItemsFromKits:  fromKits[sku],
+			PacksUsed:      map[string]int{},
+			TotalItemsUsed: fromKits[sku],
+		}
+		if rest := s.items[sku] - fromKits[sku]; rest > 0 {
+			packs, _ := s.packFor(sku, rest)
+			line.PacksUsed = utils.ConvertMapKeys(packs)
+			for size, count := range packs {
+				line.TotalItemsUsed += size * count
+			}
+		}
+		if line.ItemsOrdered > 0 || line.TotalItemsUsed > 0 {
+			res.Lines = append(res.Lines, line)
+		}
+	}
+	return res
+}
+
+// kitSize returns the number of items in kit.
+func kitSize(kit swagger.Kit) int {
+	size := 0
+	for _, items := range kit.Contents {
+		size += items
+	}
+	return size
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// mockKitRepo is a mockCatalog that also stores kits.
+type mockKitRepo struct {
+	mockCatalog
+	kits mockRecords[swagger.Kit]
+}
+
+func (m *mockKitRepo) ListKits() ([]swagger.Kit, error) {
+	return m.kits.list()
+}
+
+func (m *mockKitRepo) GetKit(id string) (*swagger.Kit, error) {
+	return m.kits.get(id)
+}
+
+func (m *mockKitRepo) CreateKit(kit *swagger.Kit) error {
+	return m.kits.create(kit, &kit.Id)
+}
+
+func (m *mockKitRepo) UpdateKit(kit *swagger.Kit) error {
+	return m.kits.update(kit.Id, kit)
+}
+
+func (m *mockKitRepo) DeleteKit(id string) error {
+	return m.kits.delete(id)
+}
+
+func TestCalculateSkus(t *testing.T) {
+	repo := &mockKitRepo{}
+	ps := NewPackService(repo)
+	for _, payload := range []swagger.PackPayload{{Size: 10}, {Size: 5, Sku: utils.Ptr("B")}} {
+		_, err := ps.CreatePack(payload)
+		require.NoError(t, err)
+	}
+	_, err := ps.CreateKit(swagger.KitPayload{Name: "Starter", Contents: map[string]int{"A": 10, "B": 5}})
+	require.NoError(t, err)
+
+	t.Run("KitsOnly", func(t *testing.T) {
+		res, err := ps.CalculateSkus(swagger.SkuOrder{Items: map[string]int{"A": 20, "B": 10}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"1": 2}, res.KitsUsed)
+		assert.Equal(t, 0, res.Overage)
+		assert.Equal(t, 2, res.ShippingUnits)
+		assert.Equal(t, []swagger.SkuLine{
+			{Sku: "A", ItemsOrdered: 20, ItemsFromKits: 20, PacksUsed: map[string]int{}, TotalItemsUsed: 20},
+			{Sku: "B", ItemsOrdered: 10, ItemsFromKits: 10, PacksUsed: map[string]int{}, TotalItemsUsed: 10},
+		}, res.Lines)
+	})
+
+	t.Run("KitsAndPacks", func(t *testing.T) {
+		// A kit and a 10-pack of A beat three single packs at the same overage.
+		res, err := ps.CalculateSkus(swagger.SkuOrder{Items: map[string]int{"A": 20, "B": 3}})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"1": 1}, res.KitsUsed)
+		assert.Equal(t, 2, res.Overage)
+		assert.Equal(t, map[string]int{"10": 1}, res.Lines[0].PacksUsed)
+		assert.Equal(t, 2, res.ShippingUnits)
+	})
+
+	t.Run("PacksOnly", func(t *testing.T) {
+		// Kits would ship B, which is not ordered.
+		res, err := ps.CalculateSkus(swagger.SkuOrder{Items: map[string]int{"A": 25}})
+		require.NoError(t, err)
+		assert.Empty(t, res.KitsUsed)
+		assert.Equal(t, 5, res.Overage)
+		require.Len(t, res.Lines, 1)
+		assert.Equal(t, map[string]int{"10": 3}, res.Lines[0].PacksUsed)
+	})
+
+	t.Run("LargeOrder", func(t *testing.T) {
+		start := time.Now()
+		res, err := ps.CalculateSkus(swagger.SkuOrder{Items: map[string]int{"A": 3000000, "B": 7}})
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.Equal(t, map[string]int{"1": 2}, res.KitsUsed)
+		assert.Equal(t, 3, res.Overage)
+		assert.Equal(t, 300000, res.ShippingUnits)
+	})
+
+	t.Run("KitsNeverWorsenTheResult", func(t *testing.T) {
+		repo := &mockKitRepo{}
+		ps := NewPackService(repo)
+		packs := map[string][]int{"A": {250, 499, 997}, "B": {251, 503}, "C": {241, 509}}
+		for sku, sizes := range packs {
+			for _, size := range sizes {
+				_, err := ps.CreatePack(swagger.PackPayload{Size: size, Sku: utils.Ptr(sku)})
+				require.NoError(t, err)
+			}
+		}
+		order := swagger.SkuOrder{Items: map[string]int{"A": 5000, "B": 5007, "C": 5013}}
+		withoutKits, err := ps.CalculateSkus(order)
+		require.NoError(t, err)
+		assert.Equal(t, 36, withoutKits.Overage)
+
+		for _, contents := range []map[string]int{{"A": 10, "B": 5}, {"A": 7, "C": 3}, {"B": 11, "C": 13}, {"A": 3, "B": 3, "C": 3}} {
+			_, err := ps.CreateKit(swagger.KitPayload{Name: "Kit", Contents: contents})
+			require.NoError(t, err)
+		}
+		res, err := ps.CalculateSkus(order)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, res.Overage, withoutKits.Overage)
+		if res.Overage == withoutKits.Overage {
+			assert.LessOrEqual(t, res.ShippingUnits, withoutKits.ShippingUnits)
+		}
+	})
+
+	t.Run("Uncoverable", func(t *testing.T) {
+		ps := NewPackService(&mockKitRepo{})
+		_, err := ps.CreatePack(swagger.PackPayload{Size: 10, Sku: utils.Ptr("A")})
+		require.NoError(t, err)
+		_, err = ps.CalculateSkus(swagger.SkuOrder{Items: map[string]int{"B": 5}})
+		assert.ErrorIs(t, err, ErrUncoverable)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ps.CalculateSkus(swagger.SkuOrder{Items: map[string]int{"A": -1}})
+		var validation *ValidationError
+		assert.ErrorAs(t, err, &validation)
+	})
+}
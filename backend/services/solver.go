@@ -0,0 +1,292 @@
+package services
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// unreachable marks a total that cannot be assembled from the available pack sizes.
+const unreachable = math.MaxInt
+
+// packTable is a minimum-pack-count table over every total from 0 to a limit.
+// packs[s] holds the fewest packs that add up to exactly s (unreachable if none)
+// and last[s] holds the pack size added last on that path, so a distribution can
+// be rebuilt by walking back from s without storing a map per total.
+// Tables built with availability caps record the chosen chunks in taken instead.
+type packTable struct {
+	packs []int
+	last  []int
+
+	chunks []packChunk
+	taken  [][]bool
+}
+
+// packChunk is a group of count packs of the same size, used to turn a bounded
+// pack supply into 0/1 choices by binary splitting (1, 2, 4, ..., rest).
+type packChunk struct {
+	size  int
+	count int
+}
+
+// newPackTable fills a packTable up to limit. packSizes must be sorted in
+// descending order; ties are resolved exactly like calculatePacksDP does.
+func newPackTable(limit int, packSizes []int) *packTable {
+	t := &packTable{
+		packs: make([]int, limit+1),
+		last:  make([]int, limit+1),
+	}
+	for s := 1; s <= limit; s++ {
+		t.packs[s] = unreachable
+	}
+	for s := 0; s <= limit; s++ {
+		if t.packs[s] == unreachable {
+			continue
+		}
+		for _, size := range packSizes {
+			ns := s + size
+			if ns > limit {
+				continue
+			}
+			if t.packs[s]+1 < t.packs[ns] {
+				t.packs[ns] = t.packs[s] + 1
+				t.last[ns] = size
+			}
+		}
+	}
+	return t
+}
+
+// newBoundedPackTable fills a packTable up to limit where no more than caps[size]
+// packs of each size may be used. Sizes missing from caps are unlimited.
+func newBoundedPackTable(limit int, packSizes []int, caps map[int]int) *packTable {
+	var chunks []packChunk
+	for _, size := range packSizes {
+		available, capped := caps[size]
+		if !capped || available > limit/size {
+			available = limit / size
+		}
+		for n := 1; available > 0; n *= 2 {
+			take := min(n, available)
+			chunks = append(chunks, packChunk{size: size, count: take})
+			available -= take
+		}
+	}
+
+	t := &packTable{
+		packs:  make([]int, limit+1),
+		chunks: chunks,
+		taken:  make([][]bool, len(chunks)),
+	}
+	for s := 1; s <= limit; s++ {
+		t.packs[s] = unreachable
+	}
+	for i, chunk := range chunks {
+		t.taken[i] = make([]bool, limit+1)
+		weight := chunk.size * chunk.count
+		// Iterate downwards so every chunk is used at most once.
+		for s := limit; s >= weight; s-- {
+			if t.packs[s-weight] == unreachable {
+				continue
+			}
+			if t.packs[s-weight]+chunk.count < t.packs[s] {
+				t.packs[s] = t.packs[s-weight] + chunk.count
+				t.taken[i][s] = true
+			}
+		}
+	}
+	return t
+}
+
+// reachable reports whether the total s can be packed exactly.
+func (t *packTable) reachable(s int) bool {
+	return s >= 0 && s < len(t.packs) && t.packs[s] != unreachable
+}
+
+// distribution rebuilds the pack counts used to reach the total s.
+func (t *packTable) distribution(s int) map[int]int {
+	packs := make(map[int]int)
+	if t.chunks != nil {
+		for i := len(t.chunks) - 1; i >= 0 && s > 0; i-- {
+			if t.taken[i][s] {
+				packs[t.chunks[i].size] += t.chunks[i].count
+				s -= t.chunks[i].size * t.chunks[i].count
+			}
+		}
+		return packs
+	}
+	for s > 0 {
+		size := t.last[s]
+		packs[size]++
+		s -= size
+	}
+	return packs
+}
+
+// residueTable returns, for every remainder r modulo the smallest pack size, the
+// smallest total congruent to r that can be packed exactly (unreachable if none).
+// A total n is then representable if and only if n >= table[n % smallest], which
+// answers exact-fill questions for arbitrarily large orders in
+// O(smallest * k * log(smallest * k)). packSizes must be sorted in descending
+// order.
+func residueTable(packSizes []int) []int {
+	table, _ := residuePaths(packSizes)
+	return table
+}
+
+// residuePaths computes the residueTable of packSizes together with the last
+// pack on the path to every entry, from which the packs of the entry can be
+// rebuilt.
+func residuePaths(packSizes []int) (table, last []int) {
+	smallest := packSizes[len(packSizes)-1]
+	table = make([]int, smallest)
+	last = make([]int, smallest)
+	for r := range table {
+		table[r] = unreachable
+	}
+	table[0] = 0
+	done := make([]bool, smallest)
+	// Dijkstra over the residue classes: adding a pack of size p moves from
+	// residue r to (r+p) % smallest at a cost of p items. Residues leave the
+	// queue by total and then by residue, so ties resolve the same way on
+	// every run.
+	queue := &residueQueue{{}}
+	for queue.Len() > 0 {
+		r := heap.Pop(queue).(residueEntry).residue
+		if done[r] {
+			continue
+		}
+		done[r] = true
+		for _, size := range packSizes {
+			next := (r + size) % smallest
+			if table[r]+size < table[next] {
+				table[next], last[next] = table[r]+size, size
+				heap.Push(queue, residueEntry{total: table[next], residue: next})
+			}
+		}
+	}
+	return table, last
+}
+
+// residueEntry is a residue queued by residuePaths at the total it was
+// reached with. Entries whose residue is already done are stale.
+type residueEntry struct {
+	total, residue int
+}
+
+// residueQueue is a min-heap of residueEntry ordered by total, then residue.
+type residueQueue []residueEntry
+
+func (q residueQueue) Len() int { return len(q) }
+
+func (q residueQueue) Less(i, j int) bool {
+	if q[i].total != q[j].total {
+		return q[i].total < q[j].total
+	}
+	return q[i].residue < q[j].residue
+}
+
+func (q residueQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *residueQueue) Push(x any) { *q = append(*q, x.(residueEntry)) }
+
+func (q *residueQueue) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
+
+// representable reports whether n can be packed exactly according to table.
+func representable(n int, table []int) bool {
+	if n < 0 {
+		return false
+	}
+	return table[n%len(table)] <= n
+}
+
+// solveExact returns a distribution that adds up to exactly n, or false if no
+// such distribution exists. Totals up to dpThreshold use the minimum number of
+// packs. Larger totals are first reduced with the largest pack size until the
+// remainder fits the DP window and is still representable. When that takes
+// the remainder past twice dpThreshold, as with a very large pack, the
+// distribution is rebuilt from the residue paths instead, which keeps the
+// memory bounded while guaranteeing an exact result.
+func solveExact(n int, packSizes []int, table []int) (map[int]int, bool) {
+	if !representable(n, table) {
+		return nil, false
+	}
+	largest := packSizes[0]
+	peeled := 0
+	if n > dpThreshold {
+		peeled = (n - dpThreshold + largest - 1) / largest
+	}
+	rest := n - peeled*largest
+	for peeled > 0 && !representable(rest, table) {
+		peeled--
+		rest += largest
+	}
+	if rest > 2*dpThreshold {
+		return solveExactByResidues(n, packSizes), true
+	}
+	packs := newPackTable(rest, packSizes).distribution(rest)
+	if peeled > 0 {
+		packs[largest] += peeled
+	}
+	return packs, true
+}
+
+// solveExactByResidues returns a distribution that adds up to exactly n, which
+// must be representable, in O(smallest * k) memory. The path to the residue
+// table entry of n is walked back pack by pack, and what is left, a multiple of
+// the smallest size, is filled greedily with the sizes that are multiples of
+// the smallest. packSizes must be sorted in descending order.
+func solveExactByResidues(n int, packSizes []int) map[int]int {
+	table, last := residuePaths(packSizes)
+	smallest := len(table)
+	packs := make(map[int]int)
+	r := n % smallest
+	rest := n - table[r]
+	for r != 0 {
+		packs[last[r]]++
+		r = ((r-last[r])%smallest + smallest) % smallest
+	}
+	for _, size := range packSizes {
+		if size%smallest == 0 && rest >= size {
+			packs[size] += rest / size
+			rest %= size
+		}
+	}
+	return packs
+}
+
+// nearestRepresentable returns the largest representable total below n and the
+// smallest representable total above n. A missing neighbour is reported as -1.
+func nearestRepresentable(n int, table []int) (below, above int) {
+	smallest := len(table)
+	below, above = -1, -1
+	for r, minTotal := range table {
+		if minTotal == unreachable {
+			continue
+		}
+		// Largest m < n with m ≡ r (mod smallest).
+		m := n - 1 - ((n-1-r)%smallest+smallest)%smallest
+		if m >= minTotal && m > below {
+			below = m
+		}
+		// Smallest m > n with m ≡ r (mod smallest), but never below minTotal.
+		m = n + 1 + ((r-n-1)%smallest+smallest)%smallest
+		m = max(m, minTotal)
+		if above == -1 || m < above {
+			above = m
+		}
+	}
+	return below, above
+}
+
+// sortedDesc returns a copy of packSizes sorted in descending order.
+func sortedDesc(packSizes []int) []int {
+	sorted := append([]int{}, packSizes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	return sorted
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResidueTable(t *testing.T) {
+	// With packs of 3 and 5 every total from 8 upwards is representable.
+	table := residueTable([]int{5, 3})
+	for n, want := range map[int]bool{0: true, 1: false, 4: false, 7: false, 8: true, 11: true, 1000001: true} {
+		assert.Equal(t, want, representable(n, table), "n=%d", n)
+	}
+
+	// A large smallest pack stays fast: the queue only visits reached residues.
+	start := time.Now()
+	table = residueTable([]int{MaxRangeQuantity + 1, MaxRangeQuantity})
+	assert.Less(t, time.Since(start), time.Second)
+	frobenius := MaxRangeQuantity*(MaxRangeQuantity+1) - MaxRangeQuantity - (MaxRangeQuantity + 1)
+	assert.False(t, representable(frobenius, table))
+	assert.True(t, representable(frobenius+1, table))
+}
+
+func TestSolveExact(t *testing.T) {
+	sizes := []int{5000, 2000, 1000, 500, 250}
+	table := residueTable(sizes)
+
+	packs, ok := solveExact(750, sizes, table)
+	require.True(t, ok)
+	assert.Equal(t, map[int]int{500: 1, 250: 1}, packs)
+
+	_, ok = solveExact(501, sizes, table)
+	assert.False(t, ok)
+
+	// Large totals are reduced with the largest pack first.
+	packs, ok = solveExact(1000250, sizes, table)
+	require.True(t, ok)
+	total := 0
+	for size, count := range packs {
+		total += size * count
+	}
+	assert.Equal(t, 1000250, total)
+
+	// A very large pack would push the DP window to 10M items; the residue
+	// paths rebuild the distribution without it.
+	sizes = []int{20000001, 2}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	packs, ok = solveExact(30000001, sizes, residueTable(sizes))
+	runtime.ReadMemStats(&after)
+	require.True(t, ok)
+	assert.Equal(t, map[int]int{20000001: 1, 2: 5000000}, packs)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+}
+
+func TestCalculatePacksExact(t *testing.T) {
+	sizes := []int{5000, 2000, 1000, 500, 250}
+
+	t.Run("ExactMatch", func(t *testing.T) {
+		res, err := calculatePacksExact(1250, sizes)
+		require.NoError(t, err)
+		assert.Equal(t, 1250, *res.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"1000": 1, "250": 1}, *res.PacksUsed)
+	})
+
+	t.Run("NearestQuantities", func(t *testing.T) {
+		_, err := calculatePacksExact(501, sizes)
+		var fillErr *ExactFillError
+		require.True(t, errors.As(err, &fillErr))
+		assert.Equal(t, 500, *fillErr.Below.TotalItemsUsed)
+		assert.Equal(t, map[string]int{"500": 1}, *fillErr.Below.PacksUsed)
+		assert.Equal(t, 750, *fillErr.Above.TotalItemsUsed)
+	})
+
+	t.Run("NothingBelow", func(t *testing.T) {
+		_, err := calculatePacksExact(100, sizes)
+		var fillErr *ExactFillError
+		require.True(t, errors.As(err, &fillErr))
+		assert.Nil(t, fillErr.Below)
+		assert.Equal(t, 250, *fillErr.Above.TotalItemsUsed)
+	})
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"ship_line/swagger"
+	"ship_line/utils"
+)
+
+// ErrWarehouseNotFound is returned when a warehouse ID does not exist.
+var ErrWarehouseNotFound = errors.New("warehouse not found")
+
+// WarehouseRepository is implemented by repositories that can also persist
+// warehouses.
+type WarehouseRepository interface {
+	ListWarehouses() ([]swagger.Warehouse, error)
+	GetWarehouse(id string) (*swagger.Warehouse, error)
+	CreateWarehouse(warehouse *swagger.Warehouse) error
+	UpdateWarehouse(warehouse *swagger.Warehouse) error
+	DeleteWarehouse(id string) error
+}
+
+// warehouseRepo returns the warehouse storage of the configured repository.
+func (ps *PackService) warehouseRepo() (WarehouseRepository, error) {
+	repo, ok := ps.repo.(WarehouseRepository)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return repo, nil
+}
+
+// warehouses returns the CRUD methods of the stored warehouses.
+func (ps *PackService) warehouses() records[swagger.Warehouse, swagger.WarehousePayload] {
+	repo, err := ps.warehouseRepo()
+	if err != nil {
+		return records[swagger.Warehouse, swagger.WarehousePayload]{err: err}
+	}
+	return records[swagger.Warehouse, swagger.WarehousePayload]{
+		noun:        "warehouse",
+		plural:      "warehouses",
+		notFound:    ErrWarehouseNotFound,
+		fromPayload: warehouseFromPayload,
+		id:          func(warehouse *swagger.Warehouse) *string { return &warehouse.Id },
+		list:        repo.ListWarehouses,
+		get:         repo.GetWarehouse,
+		create:      repo.CreateWarehouse,
+		update:      repo.UpdateWarehouse,
+		delete:      repo.DeleteWarehouse,
+	}
+}
+
+// ListWarehouses retrieves every warehouse.
+func (ps *PackService) ListWarehouses() ([]swagger.Warehouse, error) {
+	return ps.warehouses().listAll()
+}
+
+// GetWarehouse retrieves a warehouse by ID.
+func (ps *PackService) GetWarehouse(id string) (*swagger.Warehouse, error) {
+	return ps.warehouses().getOne(id)
+}
+
+// CreateWarehouse validates payload and stores it as a new warehouse.
+func (ps *PackService) CreateWarehouse(payload swagger.WarehousePayload) (*swagger.Warehouse, error) {
+	return ps.warehouses().createFrom(payload)
+}
+
+// UpdateWarehouse replaces the warehouse with the given ID by payload.
+func (ps *PackService) UpdateWarehouse(id string, payload swagger.WarehousePayload) (*swagger.Warehouse, error) {
+	return ps.warehouses().updateFrom(id, payload)
+}
+
+// DeleteWarehouse removes a warehouse.
+func (ps *PackService) DeleteWarehouse(id string) error {
+	return ps.warehouses().remove(id)
+}
+
+// warehouseFromPayload validates payload and converts it into a Warehouse
+// without an ID. Pack sizes are stored in ascending order.
+func warehouseFromPayload(payload swagger.WarehousePayload) (*swagger.Warehouse, error) {
+	if payload.Name == "" {
+		return nil, invalid("name is required")
+	}
+	warehouse := &swagger.Warehouse{Name: payload.Name}
+	stocked := map[int]bool{}
+	if payload.PackSizes != nil {
+		if len(*payload.PackSizes) == 0 {
+			return nil, invalid("packSizes must not be empty; leave it out to use the configured pack sizes")
+		}
+		sizes := append([]int{}, *payload.PackSizes...)
+		sort.Ints(sizes)
+		for i, size := range sizes {
+			if size <= 0 || (i > 0 && size == sizes[i-1]) {
+				return nil, invalid("packSizes must be unique positive integers")
+			}
+			stocked[size] = true
+		}
+		warehouse.PackSizes = &sizes
+	}
+	if payload.Stock != nil {
+		stock, err := utils.ParseMapKeys(*payload.Stock)
+		if err != nil {
+			return nil, invalid("stock must be keyed by pack size")
+		}
+		for size, count := range stock {
+			if size <= 0 || count < 0 {
+				return nil, invalid("stock needs positive pack sizes and non-negative counts")
+			}
+			if payload.PackSizes != nil && !stocked[size] {
+				return nil, invalid(fmt.Sprintf("stock lists size %d, which is not one of the packSizes", size))
+			}
+		}
+		warehouse.Stock = utils.Ptr(utils.ConvertMapKeys(stock))
+	}
+	return warehouse, nil
+}
+
+// warehousePacks is a read-only PackRepository over the pack sizes of a
+// warehouse, so that a warehouse can be solved by its own PackService.
+type warehousePacks []int
+
+// GetPackSizes returns the pack sizes of the warehouse.
+func (w warehousePacks) GetPackSizes() ([]int, error) {
+	return append([]int{}, w...), nil
+}
+
+// SetPackSizes is not supported; warehouses are updated as a whole.
+func (w warehousePacks) SetPackSizes([]int) error {
+	return ErrNotSupported
+}
+
+// DeletePackSize is not supported; warehouses are updated as a whole.
+func (w warehousePacks) DeletePackSize(int) error {
+	return ErrNotSupported
+}
+
+// atWarehouse returns the PackService that solves orders with the pack sizes
+// of warehouse: ps itself for warehouses that use the configured pack sizes.
+func (ps *PackService) atWarehouse(warehouse swagger.Warehouse) *PackService {
+	if warehouse.PackSizes == nil {
+		return ps
+	}
+	return NewPackService(warehousePacks(*warehouse.PackSizes))
+}
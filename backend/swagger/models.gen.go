@@ -3,16 +3,526 @@
 // Code generated by github.com/deepmap/oapi-codegen version v1.16.3 DO NOT EDIT.
 package swagger
 
+import (
+	"time"
+)
+
+// Defines values for GetV1CalcParamsMode.
+const (
+	Backorder GetV1CalcParamsMode = "backorder"
+	Default   GetV1CalcParamsMode = "default"
+	Exact     GetV1CalcParamsMode = "exact"
+)
+
+// Defines values for LevelObjective.
+const (
+	Consolidate LevelObjective = "consolidate"
+	FewestUnits LevelObjective = "fewestUnits"
+)
+
+// Defines values for Objective.
+const (
+	Packs    Objective = "packs"
+	Price    Objective = "price"
+	Shipping Objective = "shipping"
+)
+
+// Defines values for OrderEventType.
+const (
+	Amended  OrderEventType = "amended"
+	Created  OrderEventType = "created"
+	Returned OrderEventType = "returned"
+)
+
+// Defines values for SimulationRequestSource.
+const (
+	SimulationRequestSourceCsv       SimulationRequestSource = "csv"
+	SimulationRequestSourceStored    SimulationRequestSource = "stored"
+	SimulationRequestSourceSynthetic SimulationRequestSource = "synthetic"
+)
+
+// Defines values for SyntheticOrdersDistribution.
+const (
+	Normal  SyntheticOrdersDistribution = "normal"
+	Uniform SyntheticOrdersDistribution = "uniform"
+)
+
+// BoxLayout defines model for BoxLayout.
+type BoxLayout struct {
+	ContainerTypeId string         `json:"containerTypeId"`
+	Dimensions      PackDimensions `json:"dimensions"`
+
+	// FillRatio Share of the inner volume filled by the packs.
+	FillRatio  float64     `json:"fillRatio"`
+	Name       string      `json:"name"`
+	Placements []Placement `json:"placements"`
+
+	// Weight Total gross weight of the packs in kilograms; omitted unless every pack has a weight.
+	Weight *float64 `json:"weight,omitempty"`
+}
+
 // CalcResult defines model for CalcResult.
 type CalcResult struct {
-	ItemsOrdered   *int            `json:"itemsOrdered,omitempty"`
-	PacksUsed      *map[string]int `json:"packsUsed,omitempty"`
-	TotalItemsUsed *int            `json:"totalItemsUsed,omitempty"`
+	// Containers Per-container manifests, returned with containers=true.
+	Containers *[]ContainerManifest `json:"containers,omitempty"`
+
+	// ItemsBackordered Items left unshipped by the backorder policy or by a stock shortage.
+	ItemsBackordered *int `json:"itemsBackordered,omitempty"`
+	ItemsOrdered     *int `json:"itemsOrdered,omitempty"`
+
+	// Layout Arrangement of the packs in shipping boxes, returned with layout=true.
+	Layout *[]BoxLayout `json:"layout,omitempty"`
+
+	// LooseItemsUsed Items of an order taken from the loose-item pool before opening packs; they count towards totalItemsUsed.
+	LooseItemsUsed *int `json:"looseItemsUsed,omitempty"`
+
+	// Packs The packs used, referenced by ID as well as size.
+	Packs *[]PackUsage `json:"packs,omitempty"`
+
+	// PacksOpened Packs broken open to ship part of their items loose, returned with break=true. They are not part of packsUsed.
+	PacksOpened *[]OpenedPack   `json:"packsOpened,omitempty"`
+	PacksUsed   *map[string]int `json:"packsUsed,omitempty"`
+
+	// Pallets Pallet load plan, returned with pallet=ID.
+	Pallets  *PalletPlan   `json:"pallets,omitempty"`
+	Shipping *ShippingPlan `json:"shipping,omitempty"`
+
+	// ShippingUnits Number of packs to ship.
+	ShippingUnits  *int `json:"shippingUnits,omitempty"`
+	TotalItemsUsed *int `json:"totalItemsUsed,omitempty"`
+
+	// TotalVolume Total outer volume of the packs in cubic centimetres; omitted unless every pack used has dimensions.
+	TotalVolume *float64 `json:"totalVolume,omitempty"`
+
+	// TotalWeight Total gross weight in kilograms; omitted unless every pack used has a weight.
+	TotalWeight *float64 `json:"totalWeight,omitempty"`
+
+	// Units Nested distribution into the levels of a packaging hierarchy, returned with hierarchy=ID.
+	Units *[]PackagingUnit `json:"units,omitempty"`
+}
+
+// ContainerManifest defines model for ContainerManifest.
+type ContainerManifest struct {
+	ContainerTypeId string `json:"containerTypeId"`
+
+	// Items Number of items in the container.
+	Items     int            `json:"items"`
+	Name      *string        `json:"name,omitempty"`
+	PacksUsed map[string]int `json:"packsUsed"`
+
+	// Volume Total volume of the packs in cubic centimetres; omitted unless every pack has dimensions.
+	Volume *float64 `json:"volume,omitempty"`
+
+	// Weight Total gross weight of the packs in kilograms; omitted unless every pack has a weight.
+	Weight *float64 `json:"weight,omitempty"`
+}
+
+// ContainerType defines model for ContainerType.
+type ContainerType struct {
+	// Cost Cost of using one container; cheaper types are preferred for the same contents.
+	Cost       *float64        `json:"cost,omitempty"`
+	Dimensions *PackDimensions `json:"dimensions,omitempty"`
+	Id         string          `json:"id"`
+
+	// MaxFillRatio Largest share of the inner volume the packs may fill in a layout, from 0 to 1.
+	MaxFillRatio *float64 `json:"maxFillRatio,omitempty"`
+
+	// MaxItems Largest number of items per container.
+	MaxItems *int `json:"maxItems,omitempty"`
+
+	// MaxVolume Largest total volume of the packs in cubic centimetres.
+	MaxVolume *float64 `json:"maxVolume,omitempty"`
+
+	// MaxWeight Largest gross weight in kilograms.
+	MaxWeight *float64 `json:"maxWeight,omitempty"`
+	Name      string   `json:"name"`
+
+	// TareWeight Weight of the empty container in kilograms; MaxWeight includes it.
+	TareWeight *float64 `json:"tareWeight,omitempty"`
+}
+
+// ContainerTypePayload defines model for ContainerTypePayload.
+type ContainerTypePayload struct {
+	// Cost Cost of using one container; cheaper types are preferred for the same contents.
+	Cost       *float64        `json:"cost,omitempty"`
+	Dimensions *PackDimensions `json:"dimensions,omitempty"`
+
+	// MaxFillRatio Largest share of the inner volume the packs may fill in a layout, from 0 to 1.
+	MaxFillRatio *float64 `json:"maxFillRatio,omitempty"`
+
+	// MaxItems Largest number of items per container.
+	MaxItems *int `json:"maxItems,omitempty"`
+
+	// MaxVolume Largest total volume of the packs in cubic centimetres.
+	MaxVolume *float64 `json:"maxVolume,omitempty"`
+
+	// MaxWeight Largest gross weight in kilograms.
+	MaxWeight *float64 `json:"maxWeight,omitempty"`
+	Name      string   `json:"name"`
+
+	// TareWeight Weight of the empty container in kilograms; MaxWeight includes it.
+	TareWeight *float64 `json:"tareWeight,omitempty"`
+}
+
+// Customer A customer and the packing policy of their contract.
+type Customer struct {
+	Id     string         `json:"id"`
+	Name   string         `json:"name"`
+	Policy *PackingPolicy `json:"policy,omitempty"`
+}
+
+// CustomerPayload defines model for CustomerPayload.
+type CustomerPayload struct {
+	Name   string         `json:"name"`
+	Policy *PackingPolicy `json:"policy,omitempty"`
 }
 
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	Error *string `json:"error,omitempty"`
+
+	// Fields The offending fields, when the input breaks field-level rules.
+	Fields *[]FieldError `json:"fields,omitempty"`
+}
+
+// ExactFillError defines model for ExactFillError.
+type ExactFillError struct {
+	Error *string `json:"error,omitempty"`
+
+	// ItemsOrdered The requested order quantity that cannot be filled exactly.
+	ItemsOrdered *int        `json:"itemsOrdered,omitempty"`
+	NearestAbove *CalcResult `json:"nearestAbove,omitempty"`
+	NearestBelow *CalcResult `json:"nearestBelow,omitempty"`
+}
+
+// FieldError A rule broken by one field of the input.
+type FieldError struct {
+	// Field Name of the parameter or JSON field, e.g. items or items.A.
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FulfilmentPlan Sourcing of an order from the warehouses.
+type FulfilmentPlan struct {
+	// ItemsBackordered Items no warehouse has the stock for.
+	ItemsBackordered int `json:"itemsBackordered"`
+	ItemsOrdered     int `json:"itemsOrdered"`
+
+	// Shipments One distribution per warehouse shipping part of the order.
+	Shipments      []WarehouseShipment `json:"shipments"`
+	TotalItemsUsed int                 `json:"totalItemsUsed"`
+}
+
+// Hierarchy defines model for Hierarchy.
+type Hierarchy struct {
+	Id string `json:"id"`
+
+	// Levels Packaging levels above the packs, innermost first.
+	Levels []PackagingLevel `json:"levels"`
+	Name   string           `json:"name"`
+}
+
+// HierarchyPayload defines model for HierarchyPayload.
+type HierarchyPayload struct {
+	// Levels Packaging levels above the packs, innermost first.
+	Levels []PackagingLevel `json:"levels"`
+	Name   string           `json:"name"`
+}
+
+// Kit A pack holding items of several SKUs.
+type Kit struct {
+	// Contents Items of every SKU in one kit.
+	Contents map[string]int `json:"contents"`
+	Id       string         `json:"id"`
+	Name     string         `json:"name"`
+}
+
+// KitPayload defines model for KitPayload.
+type KitPayload struct {
+	// Contents Items of every SKU in one kit.
+	Contents map[string]int `json:"contents"`
+	Name     string         `json:"name"`
+}
+
+// LayerBlock defines model for LayerBlock.
+type LayerBlock struct {
+	// Columns Packs along x.
+	Columns int `json:"columns"`
+
+	// Length Extent of one pack along x in centimetres.
+	Length float64 `json:"length"`
+
+	// Rows Packs along y.
+	Rows int `json:"rows"`
+
+	// Width Extent of one pack along y in centimetres.
+	Width float64 `json:"width"`
+
+	// X Position of the block corner nearest the pallet origin, in centimetres.
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// LayerPattern defines model for LayerPattern.
+type LayerPattern struct {
+	// Blocks Blocks of identically oriented packs that make up a full layer.
+	Blocks []LayerBlock `json:"blocks"`
+	PackId string       `json:"packId"`
+
+	// PacksPerLayer Packs in a full layer, after the weight limit of the pallet.
+	PacksPerLayer int `json:"packsPerLayer"`
+	Size          int `json:"size"`
+}
+
+// LevelObjective How a packaging level chooses its units: consolidate puts as many items as possible into units of the level, fewestUnits minimises the number of handling units at the level and below.
+type LevelObjective string
+
+// LooseStock defines model for LooseStock.
+type LooseStock struct {
+	// Items Loose items left over from opened packs and available to later orders.
+	Items int `json:"items"`
+}
+
+// Objective What the solver minimises.
+type Objective string
+
+// OpenedPack A pack broken open to ship part of its items loose.
+type OpenedPack struct {
+	// ItemsShipped Items taken from the pack and shipped loose.
+	ItemsShipped int `json:"itemsShipped"`
+
+	// ItemsToLooseStock Items left in the pack; they go to the loose-item pool.
+	ItemsToLooseStock int     `json:"itemsToLooseStock"`
+	PackId            *string `json:"packId,omitempty"`
+	Size              int     `json:"size"`
+}
+
+// Order defines model for Order.
+type Order struct {
+	// Attributes Order attributes the packing rules were matched against.
+	Attributes *map[string]string `json:"attributes,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+
+	// Currency ISO 4217 currency of total.
+	Currency *string `json:"currency,omitempty"`
+
+	// CustomerId Customer whose packing policy the order was packed with.
+	CustomerId *string `json:"customerId,omitempty"`
+
+	// History Changes to the order, oldest first.
+	History      *[]OrderEvent `json:"history,omitempty"`
+	Id           string        `json:"id"`
+	ItemsOrdered int           `json:"itemsOrdered"`
+
+	// Objective What the solver minimises.
+	Objective *Objective `json:"objective,omitempty"`
+
+	// QuoteId Quote the order was placed from.
+	QuoteId *string    `json:"quoteId,omitempty"`
+	Result  CalcResult `json:"result"`
+
+	// Total Price of the packs agreed in the quote the order was placed from.
+	Total *float64 `json:"total,omitempty"`
+}
+
+// OrderAmendment The changes made to a stored order by a new quantity.
+type OrderAmendment struct {
+	Order Order `json:"order"`
+
+	// PacksAdded Packs to add to the stored distribution, keyed by size.
+	PacksAdded map[string]int `json:"packsAdded"`
+
+	// PacksRemoved Packs to take out of the stored distribution, keyed by size.
+	PacksRemoved map[string]int `json:"packsRemoved"`
+
+	// PreviousItemsOrdered Quantity of the order before the amendment.
+	PreviousItemsOrdered int `json:"previousItemsOrdered"`
+}
+
+// OrderEvent A change to a stored order.
+type OrderEvent struct {
+	At time.Time `json:"at"`
+
+	// ItemsOrdered Quantity of the order after the change.
+	ItemsOrdered *int `json:"itemsOrdered,omitempty"`
+
+	// LooseItemsReturned Items of returned opened packs added to the loose-item pool.
+	LooseItemsReturned *int `json:"looseItemsReturned,omitempty"`
+
+	// PacksAdded Packs added to the distribution, keyed by size.
+	PacksAdded *map[string]int `json:"packsAdded,omitempty"`
+
+	// PacksRemoved Packs taken out of the distribution, keyed by size.
+	PacksRemoved *map[string]int `json:"packsRemoved,omitempty"`
+
+	// PacksRestocked Returned unopened packs put back into warehouse stock, keyed by size.
+	PacksRestocked *map[string]int `json:"packsRestocked,omitempty"`
+
+	// PacksReturned Packs returned by the customer, opened or not, keyed by size.
+	PacksReturned *map[string]int `json:"packsReturned,omitempty"`
+	Type          OrderEventType  `json:"type"`
+
+	// WarehouseId Warehouse the unopened packs were restocked into.
+	WarehouseId *string `json:"warehouseId,omitempty"`
+}
+
+// OrderEventType What changed the order.
+type OrderEventType string
+
+// OrderPayload defines model for OrderPayload.
+type OrderPayload struct {
+	// Attributes Order attributes matched by packing rule conditions, e.g. channel.
+	Attributes *map[string]string `json:"attributes,omitempty"`
+
+	// CustomerId Customer whose packing policy constrains the order.
+	CustomerId *string `json:"customerId,omitempty"`
+
+	// Items Number of items ordered.
+	Items int `json:"items"`
+
+	// QuoteId Quote to accept. The order takes the items, distribution, total, customer, attributes and objective of the quote, which must not have expired or been accepted; customerId and attributes must then be left out, and items must be 0 or match the quote.
+	QuoteId *string `json:"quoteId,omitempty"`
+}
+
+// Pack defines model for Pack.
+type Pack struct {
+	// Active Inactive packs are kept in the catalogue but never used by the solver.
+	Active bool `json:"active"`
+
+	// Cost Cost of one empty pack.
+	Cost *float64 `json:"cost,omitempty"`
+
+	// Currency ISO 4217 currency of price and priceTiers; required with a price.
+	Currency   *string         `json:"currency,omitempty"`
+	Dimensions *PackDimensions `json:"dimensions,omitempty"`
+
+	// EffectiveFrom The pack is not used for calculations before this time.
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+
+	// EffectiveUntil The pack is not used for calculations from this time on.
+	EffectiveUntil *time.Time `json:"effectiveUntil,omitempty"`
+
+	// Gtin GTIN-8, GTIN-12, GTIN-13 or GTIN-14 including the check digit.
+	Gtin *string `json:"gtin,omitempty"`
+	Id   string  `json:"id"`
+
+	// MaxStack Largest number of pallet layers, counting its own, from a layer of this pack to the top of the stack.
+	MaxStack *int    `json:"maxStack,omitempty"`
+	Name     *string `json:"name,omitempty"`
+
+	// Price Selling price of one pack.
+	Price *float64 `json:"price,omitempty"`
+
+	// PriceTiers Volume discounts: the unit price of the tier with the highest minPacks not above the number of packs ordered applies to all of them.
+	PriceTiers *[]PriceTier `json:"priceTiers,omitempty"`
+
+	// Size Number of items in the pack.
+	Size int     `json:"size"`
+	Sku  *string `json:"sku,omitempty"`
+
+	// Upright Whether the pack must keep its height vertical in a layout; it may still turn around the vertical axis.
+	Upright *bool `json:"upright,omitempty"`
+
+	// Weight Gross weight of a filled pack in kilograms.
+	Weight *float64 `json:"weight,omitempty"`
+}
+
+// PackDimensions Outer dimensions of a pack in centimetres.
+type PackDimensions struct {
+	Height float64 `json:"height"`
+	Length float64 `json:"length"`
+	Width  float64 `json:"width"`
+}
+
+// PackPayload defines model for PackPayload.
+type PackPayload struct {
+	// Active Defaults to true.
+	Active *bool `json:"active,omitempty"`
+
+	// Cost Cost of one empty pack.
+	Cost *float64 `json:"cost,omitempty"`
+
+	// Currency ISO 4217 currency of price and priceTiers; required with a price.
+	Currency   *string         `json:"currency,omitempty"`
+	Dimensions *PackDimensions `json:"dimensions,omitempty"`
+
+	// EffectiveFrom The pack is not used for calculations before this time.
+	EffectiveFrom *time.Time `json:"effectiveFrom,omitempty"`
+
+	// EffectiveUntil The pack is not used for calculations from this time on.
+	EffectiveUntil *time.Time `json:"effectiveUntil,omitempty"`
+
+	// Gtin GTIN-8, GTIN-12, GTIN-13 or GTIN-14 including the check digit.
+	Gtin *string `json:"gtin,omitempty"`
+
+	// MaxStack Largest number of pallet layers, counting its own, from a layer of this pack to the top of the stack.
+	MaxStack *int    `json:"maxStack,omitempty"`
+	Name     *string `json:"name,omitempty"`
+
+	// Price Selling price of one pack.
+	Price *float64 `json:"price,omitempty"`
+
+	// PriceTiers Volume discounts: the unit price of the tier with the highest minPacks not above the number of packs ordered applies to all of them.
+	PriceTiers *[]PriceTier `json:"priceTiers,omitempty"`
+
+	// Size Number of items in the pack.
+	Size int     `json:"size"`
+	Sku  *string `json:"sku,omitempty"`
+
+	// Upright Whether the pack must keep its height vertical in a layout; it may still turn around the vertical axis.
+	Upright *bool `json:"upright,omitempty"`
+
+	// Weight Gross weight of a filled pack in kilograms.
+	Weight *float64 `json:"weight,omitempty"`
+}
+
+// PackSetAnalysis defines model for PackSetAnalysis.
+type PackSetAnalysis struct {
+	// ExactlyFillable Number of quantities in the range that can be filled without overage.
+	ExactlyFillable int `json:"exactlyFillable"`
+
+	// FrobeniusNumber Largest quantity that cannot be filled exactly. Only present when the GCD is 1.
+	FrobeniusNumber *int `json:"frobeniusNumber,omitempty"`
+
+	// Gcd Greatest common divisor of the pack sizes.
+	Gcd int `json:"gcd"`
+
+	// NonRepresentableCount Number of quantities that cannot be filled exactly. Only present when the GCD is 1.
+	NonRepresentableCount *int          `json:"nonRepresentableCount,omitempty"`
+	PackSizes             []int         `json:"packSizes"`
+	Range                 QuantityRange `json:"range"`
+
+	// Version Identifies the analysed pack set; results are cached per version.
+	Version        string       `json:"version"`
+	WorstOverage   QuantityStat `json:"worstOverage"`
+	WorstPackCount QuantityStat `json:"worstPackCount"`
+}
+
+// PackSetRecommendation defines model for PackSetRecommendation.
+type PackSetRecommendation struct {
+	// CandidatesEvaluated Number of candidate pack sets scored by the optimiser.
+	CandidatesEvaluated int           `json:"candidatesEvaluated"`
+	Current             *PackSetScore `json:"current,omitempty"`
+
+	// Orders Number of order quantities in the uploaded sample.
+	Orders    int          `json:"orders"`
+	PackSizes []int        `json:"packSizes"`
+	Score     PackSetScore `json:"score"`
+}
+
+// PackSetScore defines model for PackSetScore.
+type PackSetScore struct {
+	AverageOverage float64 `json:"averageOverage"`
+	AveragePacks   float64 `json:"averagePacks"`
+	TotalOverage   int     `json:"totalOverage"`
+	TotalPacks     int     `json:"totalPacks"`
+}
+
+// PackShare Smallest share of the order to ship in packs of one size.
+type PackShare struct {
+	// Percent Percentage of the items ordered.
+	Percent float64 `json:"percent"`
+	Size    int     `json:"size"`
 }
 
 // PackSizesPayload defines model for PackSizesPayload.
@@ -21,11 +531,779 @@ type PackSizesPayload struct {
 	PackSizes []int `json:"pack_sizes"`
 }
 
+// PackSizesPreview defines model for PackSizesPreview.
+type PackSizesPreview struct {
+	// Added Sizes the change would add.
+	Added []int `json:"added"`
+
+	// After Pack sizes after the change.
+	After []int `json:"after"`
+
+	// Before Pack sizes before the change.
+	Before []int `json:"before"`
+
+	// DryRun Always true; nothing was stored.
+	DryRun bool `json:"dryRun"`
+
+	// Removed Sizes the change would remove.
+	Removed []int                    `json:"removed"`
+	Samples []PackSizesPreviewSample `json:"samples"`
+}
+
+// PackSizesPreviewSample defines model for PackSizesPreviewSample.
+type PackSizesPreviewSample struct {
+	After *CalcResult `json:"after,omitempty"`
+
+	// BecomesInfeasible The order could be shipped in full before the change but not after it, given the availability caps.
+	BecomesInfeasible bool        `json:"becomesInfeasible"`
+	Before            *CalcResult `json:"before,omitempty"`
+
+	// ExactAfter The order can be filled without overage after the change.
+	ExactAfter bool `json:"exactAfter"`
+
+	// ExactBefore The order can be filled without overage before the change.
+	ExactBefore bool `json:"exactBefore"`
+
+	// ExactFillLost The order could be filled exactly before the change but not after it.
+	ExactFillLost bool `json:"exactFillLost"`
+	ItemsOrdered  int  `json:"itemsOrdered"`
+
+	// OverageDelta Change in total items used.
+	OverageDelta *int `json:"overageDelta,omitempty"`
+}
+
+// PackUsage defines model for PackUsage.
+type PackUsage struct {
+	Count int `json:"count"`
+
+	// PackId ID of the catalogue pack; omitted when the pack set has no catalogue.
+	PackId *string `json:"packId,omitempty"`
+	Size   int     `json:"size"`
+}
+
+// PackagingLevel defines model for PackagingLevel.
+type PackagingLevel struct {
+	// Name Name of the unit at this level, such as carton or pallet.
+	Name string `json:"name"`
+
+	// Objective How a packaging level chooses its units: consolidate puts as many items as possible into units of the level, fewestUnits minimises the number of handling units at the level and below.
+	Objective *LevelObjective `json:"objective,omitempty"`
+
+	// Sizes Allowed unit sizes in items.
+	Sizes []int `json:"sizes"`
+}
+
+// PackagingUnit defines model for PackagingUnit.
+type PackagingUnit struct {
+	// Contents What one unit of this size holds; omitted for packs.
+	Contents *[]PackagingUnit `json:"contents,omitempty"`
+	Count    int              `json:"count"`
+
+	// Level Name of the packaging level, or pack for packs.
+	Level string `json:"level"`
+
+	// Size Items in one unit.
+	Size int `json:"size"`
+}
+
+// PackingPolicy Constraints applied to every calculation for a customer.
+type PackingPolicy struct {
+	// ForbiddenPackSizes Pack sizes that must not be shipped.
+	ForbiddenPackSizes *[]int `json:"forbiddenPackSizes,omitempty"`
+
+	// MaxOveragePercent Largest overage allowed, as a percentage of the items ordered.
+	MaxOveragePercent *float64 `json:"maxOveragePercent,omitempty"`
+
+	// MaxPacks Largest number of packs in one shipment.
+	MaxPacks *int `json:"maxPacks,omitempty"`
+
+	// MinPackShares Smallest shares of the order to ship in packs of given sizes.
+	MinPackShares *[]PackShare `json:"minPackShares,omitempty"`
+	Objective     *Objective   `json:"objective,omitempty"`
+}
+
+// PalletLayer defines model for PalletLayer.
+type PalletLayer struct {
+	PackId string `json:"packId"`
+
+	// Packs Number of packs in the layer; the top layer of a size may be partial.
+	Packs int `json:"packs"`
+	Size  int `json:"size"`
+
+	// Z Height of the bottom of the layer in centimetres.
+	Z float64 `json:"z"`
+}
+
+// PalletLoad defines model for PalletLoad.
+type PalletLoad struct {
+	// Height Stack height of the load in centimetres.
+	Height float64 `json:"height"`
+
+	// Layers Layers from the bottom up.
+	Layers []PalletLayer `json:"layers"`
+
+	// Weight Gross weight of the pallet including its tare in kilograms; omitted unless every pack has a weight.
+	Weight *float64 `json:"weight,omitempty"`
+}
+
+// PalletPlan defines model for PalletPlan.
+type PalletPlan struct {
+	ContainerTypeId string `json:"containerTypeId"`
+	Name            string `json:"name"`
+
+	// Pallets The loaded pallets; Pallets[0] is pallet number 1.
+	Pallets []PalletLoad `json:"pallets"`
+
+	// Patterns The layer pattern of every pack size on the pallet.
+	Patterns []LayerPattern `json:"patterns"`
+}
+
+// Parcel defines model for Parcel.
+type Parcel struct {
+	// BillableWeight The larger of the actual and the dimensional weight.
+	BillableWeight float64 `json:"billableWeight"`
+	Cost           float64 `json:"cost"`
+
+	// DimensionalWeight Volume of the packs divided by the rate card's divisor.
+	DimensionalWeight float64        `json:"dimensionalWeight"`
+	PacksUsed         map[string]int `json:"packsUsed"`
+
+	// Weight Actual gross weight in kilograms.
+	Weight float64 `json:"weight"`
+}
+
+// Placement defines model for Placement.
+type Placement struct {
+	// Height Extent along z after rotation, in centimetres.
+	Height float64 `json:"height"`
+
+	// Length Extent along x after rotation, in centimetres.
+	Length float64 `json:"length"`
+	PackId string  `json:"packId"`
+	Size   int     `json:"size"`
+
+	// Width Extent along y after rotation, in centimetres.
+	Width float64 `json:"width"`
+
+	// X Position of the pack corner nearest the box origin, in centimetres.
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// PolicyRule A conditional packing policy.
+type PolicyRule struct {
+	Name string         `json:"name"`
+	Then PackingPolicy  `json:"then"`
+	When *RuleCondition `json:"when,omitempty"`
+}
+
+// PriceTier defines model for PriceTier.
+type PriceTier struct {
+	// MinPacks Number of packs of this size from which the tier applies.
+	MinPacks  int     `json:"minPacks"`
+	UnitPrice float64 `json:"unitPrice"`
+}
+
+// QuantityRange defines model for QuantityRange.
+type QuantityRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// QuantityRule Order quantity rules of a product.
+type QuantityRule struct {
+	// BlockedRanges Quantities that cannot be ordered.
+	BlockedRanges *[]QuantityRange `json:"blockedRanges,omitempty"`
+
+	// MaxOrder Largest quantity that can be ordered.
+	MaxOrder *int `json:"maxOrder,omitempty"`
+
+	// MinOrder Smallest quantity that can be ordered.
+	MinOrder *int `json:"minOrder,omitempty"`
+
+	// Multiple Quantities must be a multiple of this number.
+	Multiple *int   `json:"multiple,omitempty"`
+	Sku      string `json:"sku"`
+}
+
+// QuantityRulePayload defines model for QuantityRulePayload.
+type QuantityRulePayload struct {
+	// BlockedRanges Quantities that cannot be ordered.
+	BlockedRanges *[]QuantityRange `json:"blockedRanges,omitempty"`
+
+	// MaxOrder Largest quantity that can be ordered.
+	MaxOrder *int `json:"maxOrder,omitempty"`
+
+	// MinOrder Smallest quantity that can be ordered.
+	MinOrder *int `json:"minOrder,omitempty"`
+
+	// Multiple Quantities must be a multiple of this number.
+	Multiple *int `json:"multiple,omitempty"`
+}
+
+// QuantityStat defines model for QuantityStat.
+type QuantityStat struct {
+	// ItemsOrdered The order quantity at which the value occurs.
+	ItemsOrdered int `json:"itemsOrdered"`
+	Value        int `json:"value"`
+}
+
+// Quote defines model for Quote.
+type Quote struct {
+	// AcceptedAt When an order was placed from the quote; a quote can be accepted once.
+	AcceptedAt *time.Time `json:"acceptedAt,omitempty"`
+
+	// Attributes Order attributes the packing rules were matched against.
+	Attributes *map[string]string `json:"attributes,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	Currency   string             `json:"currency"`
+
+	// CustomerId Customer whose packing policy the quote was packed with.
+	CustomerId *string `json:"customerId,omitempty"`
+
+	// Discount Sum of the line discounts.
+	Discount float64 `json:"discount"`
+
+	// ExpiresAt The quoted prices are honoured until this time.
+	ExpiresAt    time.Time   `json:"expiresAt"`
+	Id           string      `json:"id"`
+	ItemsOrdered int         `json:"itemsOrdered"`
+	Lines        []QuoteLine `json:"lines"`
+
+	// Objective What the solver minimises.
+	Objective Objective `json:"objective"`
+
+	// OrderId Order placed from the quote.
+	OrderId *string    `json:"orderId,omitempty"`
+	Result  CalcResult `json:"result"`
+
+	// Subtotal Sum of the line totals at list price.
+	Subtotal float64 `json:"subtotal"`
+	Total    float64 `json:"total"`
+}
+
+// QuoteLine defines model for QuoteLine.
+type QuoteLine struct {
+	Count int `json:"count"`
+
+	// Discount Line discount from volume tiers.
+	Discount float64 `json:"discount"`
+
+	// ListPrice Price of one pack before discounts.
+	ListPrice float64 `json:"listPrice"`
+	PackId    string  `json:"packId"`
+	Size      int     `json:"size"`
+
+	// Total Line total after discounts.
+	Total float64 `json:"total"`
+
+	// UnitPrice Price of one pack after discounts.
+	UnitPrice float64 `json:"unitPrice"`
+}
+
+// QuoteRequest defines model for QuoteRequest.
+type QuoteRequest struct {
+	// Attributes Order attributes matched by packing rule conditions, e.g. channel.
+	Attributes *map[string]string `json:"attributes,omitempty"`
+
+	// CustomerId Customer whose packing policy constrains the quoted distribution.
+	CustomerId *string `json:"customerId,omitempty"`
+
+	// Items Number of items ordered.
+	Items int `json:"items"`
+
+	// Objective What the solver minimises.
+	Objective *Objective `json:"objective,omitempty"`
+
+	// ValidDays Number of days the quoted prices are honoured. Defaults to 14.
+	ValidDays *int `json:"validDays,omitempty"`
+}
+
+// RateCard defines model for RateCard.
+type RateCard struct {
+	// Brackets Parcel prices by billable weight; the first bracket whose maxWeight is not below the parcel's billable weight applies. The largest maxWeight is the parcel weight limit.
+	Brackets []WeightBracket `json:"brackets"`
+	Carrier  string          `json:"carrier"`
+
+	// Currency ISO 4217 currency of the prices, also assumed for pack costs.
+	Currency string `json:"currency"`
+
+	// DimDivisor Cubic centimetres per kilogram of dimensional weight.
+	DimDivisor float64 `json:"dimDivisor"`
+	Id         string  `json:"id"`
+
+	// ParcelFee Fixed fee added to every parcel.
+	ParcelFee float64 `json:"parcelFee"`
+	Service   *string `json:"service,omitempty"`
+}
+
+// RateCardPayload defines model for RateCardPayload.
+type RateCardPayload struct {
+	// Brackets Parcel prices by billable weight; the first bracket whose maxWeight is not below the parcel's billable weight applies. The largest maxWeight is the parcel weight limit.
+	Brackets []WeightBracket `json:"brackets"`
+	Carrier  string          `json:"carrier"`
+
+	// Currency ISO 4217 currency of the prices, also assumed for pack costs.
+	Currency string `json:"currency"`
+
+	// DimDivisor Cubic centimetres per kilogram of dimensional weight.
+	DimDivisor float64 `json:"dimDivisor"`
+
+	// ParcelFee Fixed fee added to every parcel.
+	ParcelFee *float64 `json:"parcelFee,omitempty"`
+	Service   *string  `json:"service,omitempty"`
+}
+
+// ReturnPayload defines model for ReturnPayload.
+type ReturnPayload struct {
+	Packs []ReturnedPack `json:"packs"`
+
+	// WarehouseId Warehouse the unopened packs go back to; required when several warehouses are stored.
+	WarehouseId *string `json:"warehouseId,omitempty"`
+}
+
+// ReturnedPack Packs of one size returned by a customer.
+type ReturnedPack struct {
+	// Count Number of packs returned.
+	Count int `json:"count"`
+
+	// Items Opened packs only. Items left in the returned packs; defaults to all of their items.
+	Items *int `json:"items,omitempty"`
+
+	// Opened Opened packs go to the loose-item pool instead of warehouse stock.
+	Opened *bool `json:"opened,omitempty"`
+	Size   int   `json:"size"`
+}
+
+// RuleCondition When a rule applies; every given field must match.
+type RuleCondition struct {
+	// Attributes Order attributes that must all have the given values, e.g. channel.
+	Attributes *map[string]string `json:"attributes,omitempty"`
+
+	// MaxItems Largest order quantity the rule applies to.
+	MaxItems *int `json:"maxItems,omitempty"`
+
+	// MinItems Smallest order quantity the rule applies to.
+	MinItems *int `json:"minItems,omitempty"`
+}
+
+// RuleSample A sample order to test a rule set against.
+type RuleSample struct {
+	// Attributes Order attributes matched by rule conditions.
+	Attributes *map[string]string `json:"attributes,omitempty"`
+	Items      int                `json:"items"`
+}
+
+// RuleSampleResult Outcome of a rule set for one sample order.
+type RuleSampleResult struct {
+	// Error Why the sample cannot be packed under the rules.
+	Error *string `json:"error,omitempty"`
+	Items int     `json:"items"`
+
+	// MatchedRules Names of the rules that apply, in rule set order.
+	MatchedRules []string      `json:"matchedRules"`
+	Policy       PackingPolicy `json:"policy"`
+	Result       *CalcResult   `json:"result,omitempty"`
+}
+
+// RuleSet A stored version of the packing rules.
+type RuleSet struct {
+	CreatedAt time.Time    `json:"createdAt"`
+	Rules     []PolicyRule `json:"rules"`
+	Version   int          `json:"version"`
+}
+
+// RuleSetPayload defines model for RuleSetPayload.
+type RuleSetPayload struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// RuleSetTest Sample orders and the rules to test them against.
+type RuleSetTest struct {
+	Orders []RuleSample `json:"orders"`
+
+	// Rules A draft rule set; without it the stored version, or the active one, is tested.
+	Rules *[]PolicyRule `json:"rules,omitempty"`
+
+	// Version Stored rule set version to test.
+	Version *int `json:"version,omitempty"`
+}
+
+// RuleSetTestResult Outcome of a rule set for every sample order.
+type RuleSetTestResult struct {
+	Results []RuleSampleResult `json:"results"`
+
+	// Version The stored version tested; absent for a draft or without rules.
+	Version *int `json:"version,omitempty"`
+}
+
+// ShippingPlan defines model for ShippingPlan.
+type ShippingPlan struct {
+	Carrier  string `json:"carrier"`
+	Currency string `json:"currency"`
+
+	// PackagingCost Sum of the cost of the empty packs.
+	PackagingCost float64  `json:"packagingCost"`
+	Parcels       []Parcel `json:"parcels"`
+	RateCardId    string   `json:"rateCardId"`
+	Service       *string  `json:"service,omitempty"`
+	ShippingCost  float64  `json:"shippingCost"`
+
+	// TotalCost Shipping plus packaging cost.
+	TotalCost float64 `json:"totalCost"`
+}
+
+// SimulationDelta defines model for SimulationDelta.
+type SimulationDelta struct {
+	AverageOverage float64 `json:"averageOverage"`
+	AveragePacks   float64 `json:"averagePacks"`
+	TotalOverage   int     `json:"totalOverage"`
+	TotalPacks     int     `json:"totalPacks"`
+}
+
+// SimulationOrder defines model for SimulationOrder.
+type SimulationOrder struct {
+	CandidateOverage int `json:"candidateOverage"`
+	CandidatePacks   int `json:"candidatePacks"`
+	CurrentOverage   int `json:"currentOverage"`
+	CurrentPacks     int `json:"currentPacks"`
+	ItemsOrdered     int `json:"itemsOrdered"`
+
+	// OverageDelta Candidate overage minus current overage.
+	OverageDelta int `json:"overageDelta"`
+}
+
+// SimulationRequest defines model for SimulationRequest.
+type SimulationRequest struct {
+	// Csv Order quantities as CSV, used when source is "csv".
+	Csv *string `json:"csv,omitempty"`
+
+	// PackSizes Candidate pack sizes.
+	PackSizes []int                   `json:"packSizes"`
+	Source    SimulationRequestSource `json:"source"`
+	Synthetic *SyntheticOrders        `json:"synthetic,omitempty"`
+
+	// WorstOrders Number of orders with the largest overage increase to return.
+	WorstOrders *int `json:"worstOrders,omitempty"`
+}
+
+// SimulationRequestSource defines model for SimulationRequest.Source.
+type SimulationRequestSource string
+
+// SimulationResult defines model for SimulationResult.
+type SimulationResult struct {
+	Candidate SimulationSummary `json:"candidate"`
+	Current   SimulationSummary `json:"current"`
+	Delta     SimulationDelta   `json:"delta"`
+
+	// Orders Number of orders in the sample.
+	Orders      int               `json:"orders"`
+	WorstOrders []SimulationOrder `json:"worstOrders"`
+}
+
+// SimulationSummary defines model for SimulationSummary.
+type SimulationSummary struct {
+	AverageOverage float64 `json:"averageOverage"`
+	AveragePacks   float64 `json:"averagePacks"`
+
+	// Failed Orders the solver could not pack; they are left out of the totals, the averages and worstOrders.
+	Failed    int   `json:"failed"`
+	PackSizes []int `json:"packSizes"`
+
+	// PerSizeUsage Number of packs used per pack size across the sample.
+	PerSizeUsage map[string]int `json:"perSizeUsage"`
+	TotalOverage int            `json:"totalOverage"`
+	TotalPacks   int            `json:"totalPacks"`
+}
+
+// SkuLine The items of one SKU in a multi-SKU distribution.
+type SkuLine struct {
+	ItemsOrdered int `json:"itemsOrdered"`
+
+	// ItemsFromKits Items of the SKU shipped in kits.
+	ItemsFromKits int `json:"itemsFromKits"`
+
+	// PacksUsed Single-SKU packs, keyed by size.
+	PacksUsed      map[string]int `json:"packsUsed"`
+	Sku            string         `json:"sku"`
+	TotalItemsUsed int            `json:"totalItemsUsed"`
+}
+
+// SkuOrder An order of several SKUs.
+type SkuOrder struct {
+	// Items Number of items ordered per SKU.
+	Items map[string]int `json:"items"`
+}
+
+// SkuResult Distribution of a multi-SKU order into kits and single-SKU packs.
+type SkuResult struct {
+	// KitsUsed Kits used, keyed by kit ID.
+	KitsUsed map[string]int `json:"kitsUsed"`
+
+	// Lines One line per SKU ordered or shipped in a kit, ordered by SKU.
+	Lines []SkuLine `json:"lines"`
+
+	// Overage Items shipped beyond the order, over all SKUs.
+	Overage int `json:"overage"`
+
+	// ShippingUnits Number of kits and packs to ship.
+	ShippingUnits int `json:"shippingUnits"`
+}
+
+// SyntheticOrders defines model for SyntheticOrders.
+type SyntheticOrders struct {
+	Count        int                         `json:"count"`
+	Distribution SyntheticOrdersDistribution `json:"distribution"`
+	Max          *int                        `json:"max,omitempty"`
+	Mean         *float64                    `json:"mean,omitempty"`
+	Min          *int                        `json:"min,omitempty"`
+	Seed         *int64                      `json:"seed,omitempty"`
+	Stddev       *float64                    `json:"stddev,omitempty"`
+}
+
+// SyntheticOrdersDistribution defines model for SyntheticOrders.Distribution.
+type SyntheticOrdersDistribution string
+
+// Warehouse defines model for Warehouse.
+type Warehouse struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+
+	// PackSizes Pack sizes the warehouse ships; defaults to the configured pack sizes.
+	PackSizes *[]int `json:"packSizes,omitempty"`
+
+	// Stock Unopened packs on hand, keyed by size; sizes left out are not limited.
+	Stock *map[string]int `json:"stock,omitempty"`
+}
+
+// WarehousePayload defines model for WarehousePayload.
+type WarehousePayload struct {
+	Name string `json:"name"`
+
+	// PackSizes Pack sizes the warehouse ships; defaults to the configured pack sizes.
+	PackSizes *[]int `json:"packSizes,omitempty"`
+
+	// Stock Unopened packs on hand, keyed by size; sizes left out are not limited.
+	Stock *map[string]int `json:"stock,omitempty"`
+}
+
+// WarehouseShipment The part of an order shipped from one warehouse.
+type WarehouseShipment struct {
+	Name        string     `json:"name"`
+	Result      CalcResult `json:"result"`
+	WarehouseId string     `json:"warehouseId"`
+}
+
+// WeightBracket defines model for WeightBracket.
+type WeightBracket struct {
+	// MaxWeight Largest billable weight in kilograms this bracket covers.
+	MaxWeight float64 `json:"maxWeight"`
+	Price     float64 `json:"price"`
+}
+
+// DeletePackSizesSizeParams defines parameters for DeletePackSizesSize.
+type DeletePackSizesSizeParams struct {
+	// DryRun Validate the change and preview its impact without storing it.
+	DryRun *bool `form:"dryRun,omitempty" json:"dryRun,omitempty"`
+
+	// Samples Dry run only. Comma-separated order quantities to compare.
+	Samples *string `form:"samples,omitempty" json:"samples,omitempty"`
+
+	// Availability Dry run only. Per-size pack caps as comma-separated size:count pairs.
+	Availability *string `form:"availability,omitempty" json:"availability,omitempty"`
+}
+
 // GetV1CalcParams defines parameters for GetV1Calc.
 type GetV1CalcParams struct {
 	// Items Number of items ordered.
 	Items int `form:"items" json:"items"`
+
+	// Mode Use "exact" to reject any overage instead of rounding the order up, or "backorder" to allow shipping less than ordered.
+	Mode *GetV1CalcParamsMode `form:"mode,omitempty" json:"mode,omitempty"`
+
+	// Tolerance Backorder mode only. Largest number of items that may be left unshipped; 0 means no limit.
+	Tolerance *int `form:"tolerance,omitempty" json:"tolerance,omitempty"`
+
+	// Penalty Backorder mode only. Cost of backordering one item relative to one item of overage.
+	Penalty *float64 `form:"penalty,omitempty" json:"penalty,omitempty"`
+
+	// Availability Per-size pack caps as comma-separated size:count pairs, e.g. 250:10,500:3.
+	Availability *string `form:"availability,omitempty" json:"availability,omitempty"`
+
+	// Objective "packs" minimises overage and then pack count; "price" minimises the total price of the packs; "shipping" minimises shipping plus packaging cost.
+	Objective *Objective `form:"objective,omitempty" json:"objective,omitempty"`
+
+	// MaxWeight Largest total gross weight of the shipment in kilograms.
+	MaxWeight *float64 `form:"maxWeight,omitempty" json:"maxWeight,omitempty"`
+
+	// MaxVolume Largest total volume of the shipment in cubic centimetres.
+	MaxVolume *float64 `form:"maxVolume,omitempty" json:"maxVolume,omitempty"`
+
+	// At Calculate with the packs in effect at this time instead of now.
+	At *time.Time `form:"at,omitempty" json:"at,omitempty"`
+
+	// Containers Assign the packs to the stored container types and return per-container manifests.
+	Containers *bool `form:"containers,omitempty" json:"containers,omitempty"`
+
+	// Layout Arrange the packs in the container types with dimensions and return the box layouts.
+	Layout *bool `form:"layout,omitempty" json:"layout,omitempty"`
+
+	// Pallet ID of a container type with dimensions to plan pallet loads on; with Accept text/csv or text/plain the plan is returned as a printable manifest.
+	Pallet *string `form:"pallet,omitempty" json:"pallet,omitempty"`
+
+	// Hierarchy ID of a packaging hierarchy to nest the packs into.
+	Hierarchy *string `form:"hierarchy,omitempty" json:"hierarchy,omitempty"`
+
+	// Break Allow opening a pack and shipping part of its items loose when that is cheaper than the overage.
+	Break *bool `form:"break,omitempty" json:"break,omitempty"`
+
+	// BreakCost Break only. Cost of opening one pack relative to one item of overage.
+	BreakCost *float64 `form:"breakCost,omitempty" json:"breakCost,omitempty"`
+
+	// LooseCost Break only. Handling cost of shipping one loose item relative to one item of overage.
+	LooseCost *float64 `form:"looseCost,omitempty" json:"looseCost,omitempty"`
+
+	// Sku Product whose quantity rules the order must satisfy.
+	Sku *string `form:"sku,omitempty" json:"sku,omitempty"`
+
+	// CustomerId Customer whose packing policy constrains the calculation.
+	CustomerId *string `form:"customerId,omitempty" json:"customerId,omitempty"`
+	// Attributes Order attributes matched by packing rule conditions, as comma-separated key:value pairs such as channel:wholesale.
+	Attributes *string `form:"attributes,omitempty" json:"attributes,omitempty"`
+}
+
+// GetV1CalcParamsMode defines parameters for GetV1Calc.
+type GetV1CalcParamsMode string
+
+// GetV1CalcRangeParams defines parameters for GetV1CalcRange.
+type GetV1CalcRangeParams struct {
+	// From First order quantity.
+	From int `form:"from" json:"from"`
+
+	// To Last order quantity.
+	To int `form:"to" json:"to"`
+
+	// Step Increment between order quantities.
+	Step *int `form:"step,omitempty" json:"step,omitempty"`
+}
+
+// GetV1FulfilmentParams defines parameters for GetV1Fulfilment.
+type GetV1FulfilmentParams struct {
+	// Items Number of items ordered.
+	Items int `form:"items" json:"items"`
+}
+
+// GetV1PackSizesAnalysisParams defines parameters for GetV1PackSizesAnalysis.
+type GetV1PackSizesAnalysisParams struct {
+	// Sizes Comma-separated proposed pack sizes. Defaults to the configured pack sizes.
+	Sizes *string `form:"sizes,omitempty" json:"sizes,omitempty"`
+
+	// From First order quantity of the analysed range.
+	From *int `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Last order quantity of the analysed range.
+	To *int `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// PostV1PackSizesRecommendParams defines parameters for PostV1PackSizesRecommend.
+type PostV1PackSizesRecommendParams struct {
+	// K Number of pack sizes to suggest.
+	K int `form:"k" json:"k"`
+
+	// Min Smallest allowed pack size.
+	Min int `form:"min" json:"min"`
+
+	// Max Largest allowed pack size.
+	Max int `form:"max" json:"max"`
+
+	// PackWeight Cost of one pack relative to one item of overage.
+	PackWeight *float64 `form:"packWeight,omitempty" json:"packWeight,omitempty"`
+}
+
+// PutV1PackSizesParams defines parameters for PutV1PackSizes.
+type PutV1PackSizesParams struct {
+	// DryRun Validate the change and preview its impact without storing it.
+	DryRun *bool `form:"dryRun,omitempty" json:"dryRun,omitempty"`
+
+	// Samples Dry run only. Comma-separated order quantities to compare.
+	Samples *string `form:"samples,omitempty" json:"samples,omitempty"`
+
+	// Availability Dry run only. Per-size pack caps as comma-separated size:count pairs.
+	Availability *string `form:"availability,omitempty" json:"availability,omitempty"`
 }
 
+// PatchV1OrdersIdJSONRequestBody defines body for PatchV1OrdersId for application/json ContentType.
+type PatchV1OrdersIdJSONRequestBody = OrderPayload
+
+// PostV1CalcSkusJSONRequestBody defines body for PostV1CalcSkus for application/json ContentType.
+type PostV1CalcSkusJSONRequestBody = SkuOrder
+
+// PostV1ContainerTypesJSONRequestBody defines body for PostV1ContainerTypes for application/json ContentType.
+type PostV1ContainerTypesJSONRequestBody = ContainerTypePayload
+
+// PostV1CustomersJSONRequestBody defines body for PostV1Customers for application/json ContentType.
+type PostV1CustomersJSONRequestBody = CustomerPayload
+
+// PostV1HierarchiesJSONRequestBody defines body for PostV1Hierarchies for application/json ContentType.
+type PostV1HierarchiesJSONRequestBody = HierarchyPayload
+
+// PostV1KitsJSONRequestBody defines body for PostV1Kits for application/json ContentType.
+type PostV1KitsJSONRequestBody = KitPayload
+
+// PostV1OrdersIdReturnsJSONRequestBody defines body for PostV1OrdersIdReturns for application/json ContentType.
+type PostV1OrdersIdReturnsJSONRequestBody = ReturnPayload
+
+// PostV1OrdersJSONRequestBody defines body for PostV1Orders for application/json ContentType.
+type PostV1OrdersJSONRequestBody = OrderPayload
+
+// PostV1PacksJSONRequestBody defines body for PostV1Packs for application/json ContentType.
+type PostV1PacksJSONRequestBody = PackPayload
+
+// PostV1QuotesJSONRequestBody defines body for PostV1Quotes for application/json ContentType.
+type PostV1QuotesJSONRequestBody = QuoteRequest
+
+// PostV1RateCardsJSONRequestBody defines body for PostV1RateCards for application/json ContentType.
+type PostV1RateCardsJSONRequestBody = RateCardPayload
+
+// PostV1RuleSetsJSONRequestBody defines body for PostV1RuleSets for application/json ContentType.
+type PostV1RuleSetsJSONRequestBody = RuleSetPayload
+
+// PostV1RuleSetsTestJSONRequestBody defines body for PostV1RuleSetsTest for application/json ContentType.
+type PostV1RuleSetsTestJSONRequestBody = RuleSetTest
+
+// PostV1SimulationsJSONRequestBody defines body for PostV1Simulations for application/json ContentType.
+type PostV1SimulationsJSONRequestBody = SimulationRequest
+
+// PostV1WarehousesJSONRequestBody defines body for PostV1Warehouses for application/json ContentType.
+type PostV1WarehousesJSONRequestBody = WarehousePayload
+
+// PutV1ContainerTypesIdJSONRequestBody defines body for PutV1ContainerTypesId for application/json ContentType.
+type PutV1ContainerTypesIdJSONRequestBody = ContainerTypePayload
+
+// PutV1CustomersIdJSONRequestBody defines body for PutV1CustomersId for application/json ContentType.
+type PutV1CustomersIdJSONRequestBody = CustomerPayload
+
+// PutV1HierarchiesIdJSONRequestBody defines body for PutV1HierarchiesId for application/json ContentType.
+type PutV1HierarchiesIdJSONRequestBody = HierarchyPayload
+
+// PutV1KitsIdJSONRequestBody defines body for PutV1KitsId for application/json ContentType.
+type PutV1KitsIdJSONRequestBody = KitPayload
+
+// PutV1LooseStockJSONRequestBody defines body for PutV1LooseStock for application/json ContentType.
+type PutV1LooseStockJSONRequestBody = LooseStock
+
 // PutV1PackSizesJSONRequestBody defines body for PutV1PackSizes for application/json ContentType.
 type PutV1PackSizesJSONRequestBody = PackSizesPayload
+
+// PutV1PacksIdJSONRequestBody defines body for PutV1PacksId for application/json ContentType.
+type PutV1PacksIdJSONRequestBody = PackPayload
+
+// PutV1QuantityRulesSkuJSONRequestBody defines body for PutV1QuantityRulesSku for application/json ContentType.
+type PutV1QuantityRulesSkuJSONRequestBody = QuantityRulePayload
+
+// PutV1RateCardsIdJSONRequestBody defines body for PutV1RateCardsId for application/json ContentType.
+type PutV1RateCardsIdJSONRequestBody = RateCardPayload
+
+// PutV1WarehousesIdJSONRequestBody defines body for PutV1WarehousesId for application/json ContentType.
+type PutV1WarehousesIdJSONRequestBody = WarehousePayload
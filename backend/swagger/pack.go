@@ -0,0 +1,18 @@
+package swagger
+
+import "time"
+
+// InEffect reports whether the pack is active and at lies in its effective
+// window.
+func (p Pack) InEffect(at time.Time) bool {
+	return p.Active && p.WithinWindow(at)
+}
+
+// WithinWindow reports whether at lies in the effective window of the pack.
+// Effective windows are half-open: [EffectiveFrom, EffectiveUntil).
+func (p Pack) WithinWindow(at time.Time) bool {
+	if p.EffectiveFrom != nil && at.Before(*p.EffectiveFrom) {
+		return false
+	}
+	return p.EffectiveUntil == nil || at.Before(*p.EffectiveUntil)
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "strconv"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // ConvertMapKeys converts a map's int keys to string keys.
 func ConvertMapKeys[V any](m map[int]V) map[string]V {
@@ -10,3 +14,77 @@ func ConvertMapKeys[V any](m map[int]V) map[string]V {
 	}
 	return result
 }
+
+// ParseIntPairs parses a comma-separated list of key:value integer pairs such as
+// "250:10,500:3" into a map. An empty string yields a nil map.
+func ParseIntPairs(s string) (map[int]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	result := make(map[int]int)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found {
+			return nil, fmt.Errorf("invalid pair %q", pair)
+		}
+		k, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key in pair %q", pair)
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value in pair %q", pair)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+// ParsePairs parses a comma-separated list of key:value pairs such as
+// "channel:wholesale,region:eu" into a map. An empty string yields a nil map.
+func ParsePairs(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	result := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid pair %q", pair)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// ParseIntList parses a comma-separated list of integers such as "250,500".
+// An empty string yields a nil slice.
+func ParseIntList(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	result := make([]int, 0, len(parts))
+	for _, part := range parts {
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", part)
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// ParseMapKeys converts a map's string keys back to int keys. It is the
+// inverse of ConvertMapKeys.
+func ParseMapKeys[V any](m map[string]V) (map[int]V, error) {
+	result := make(map[int]V, len(m))
+	for k, v := range m {
+		key, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q", k)
+		}
+		result[key] = v
+	}
+	return result, nil
+}
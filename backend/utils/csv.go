@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParseQuantitiesCSV reads order quantities from CSV data. Quantities are taken
+// from the column named "quantity" or "items" when a header row is present and
+// from the first column otherwise. Blank lines are skipped.
+func ParseQuantitiesCSV(r io.Reader) ([]int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var quantities []int
+	column := 0
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+		if line == 1 {
+			if header := headerColumn(record); header >= 0 {
+				column = header
+				continue
+			}
+		}
+		if column >= len(record) {
+			return nil, fmt.Errorf("line %d: missing quantity column", line)
+		}
+		quantity, err := strconv.Atoi(strings.TrimSpace(record[column]))
+		if err != nil || quantity < 0 {
+			return nil, fmt.Errorf("line %d: invalid quantity %q", line, record[column])
+		}
+		quantities = append(quantities, quantity)
+	}
+	if len(quantities) == 0 {
+		return nil, errors.New("no order quantities found")
+	}
+	return quantities, nil
+}
+
+// headerColumn returns the index of the quantity column if record is a header
+// row, or -1 if record holds data.
+func headerColumn(record []string) int {
+	if _, err := strconv.Atoi(strings.TrimSpace(record[0])); err == nil {
+		return -1
+	}
+	for i, name := range record {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "quantity", "items":
+			return i
+		}
+	}
+	return 0
+}